@@ -27,14 +27,16 @@ import (
 type DeployPhase string
 
 const (
-	Pending       DeployPhase = "Pending"
-	Initializing  DeployPhase = "Initializing"
-	BatchStarted  DeployPhase = "BatchStarted"
-	BatchFinished DeployPhase = "BatchFinished"
-	Success       DeployPhase = "Success"
-	Failed        DeployPhase = "Failed"
-	Aborted       DeployPhase = "Aborted"
-	Canceled      DeployPhase = "Canceled"
+	Pending          DeployPhase = "Pending"
+	Initializing     DeployPhase = "Initializing"
+	BatchStarted     DeployPhase = "BatchStarted"
+	BatchFinished    DeployPhase = "BatchFinished"
+	Reverting        DeployPhase = "Reverting"
+	BlueGreenStarted DeployPhase = "BlueGreenStarted"
+	Success          DeployPhase = "Success"
+	Failed           DeployPhase = "Failed"
+	Aborted          DeployPhase = "Aborted"
+	Canceled         DeployPhase = "Canceled"
 )
 
 // 部署类型
@@ -52,12 +54,27 @@ const (
 	BatchPending     BatchPhase = "Pending"
 	BatchSmoking     BatchPhase = "Smoking"
 	BatchSmoked      BatchPhase = "Smoked"
+	BatchShadowing   BatchPhase = "Shadowing"
 	BatchBaking      BatchPhase = "Baking"
 	BatchBaked       BatchPhase = "Baked"
 	BatchSmokeFailed BatchPhase = "SmokeFailed"
 	BatchBakeFailed  BatchPhase = "BakeFailed"
 )
 
+const (
+	// ApplicationTypeCloneSet deploys the application as a Kruise CloneSet, the pods of a batch are created next to the old ones.
+	ApplicationTypeCloneSet = "cloneset"
+	// ApplicationTypeStatefulSet deploys the application as a Kruise Advanced StatefulSet,
+	// the pods of a batch replace the old ones of the highest ordinals in place.
+	ApplicationTypeStatefulSet = "statefulset"
+	// ApplicationTypeDeployment deploys the application as a native Deployment, which is paused between batches
+	// and stepped by maxSurge, the pods of a batch are created next to the old ones.
+	ApplicationTypeDeployment = "deployment"
+	// ApplicationTypeDaemonSet deploys the application as a Kruise Advanced DaemonSet, a batch is a number of nodes
+	// whose pods are replaced in place, and it may be limited to the nodes selected by the step.
+	ApplicationTypeDaemonSet = "daemonset"
+)
+
 /**
 主要功能模块：
 
@@ -96,15 +113,50 @@ type ApplicationSpec struct {
 	// Replicas defines the replicas num of app
 	Replicas *int32 `json:"replicas,omitempty"`
 
-	// ApplicationType defines the type of app, ex: cloneset, advanced statefulset
+	// +kubebuilder:validation:Enum=cloneset;statefulset;deployment;daemonset
+
+	// ApplicationType defines the type of the workload, cloneset by default, statefulset for an Advanced StatefulSet,
+	// deployment for a native Deployment, daemonset for an Advanced DaemonSet.
 	ApplicationType string `json:"applicationType,omitempty"`
 
-	// VolumeClaimTemplates is a list of claims that pods are allowed to reference.
-	// Note that PVC will be deleted when its pod has been deleted.
+	// VolumeClaimTemplates is a list of claims that pods are allowed to reference, it works for a CloneSet and an Advanced StatefulSet.
+	// What happens to the PVCs when their pods are removed is decided by PersistentVolumeClaimRetentionPolicy.
 	VolumeClaimTemplates []corev1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty"`
 
-	// ApplicationLabel defines the label of app
+	// PersistentVolumeClaimRetentionPolicy decides whether the PVCs created from VolumeClaimTemplates are retained or deleted
+	// when their pods are scaled in, the default behavior of the workload is kept if it is not set.
+	PersistentVolumeClaimRetentionPolicy *PersistentVolumeClaimRetentionPolicy `json:"persistentVolumeClaimRetentionPolicy,omitempty"`
+
+	// ApplicationLabel defines the label of app, it is set to the workload, the pod template and the DeployFlow.
+	// Labels set by Triton, ex: the selector labels, take precedence over it, and it takes precedence over the labels of the template.
 	ApplicationLabel map[string]string `json:"applicationLabel,omitempty"`
+
+	// ApplicationAnnotation defines the annotation of app, it is merged the same way as ApplicationLabel.
+	ApplicationAnnotation map[string]string `json:"applicationAnnotation,omitempty"`
+}
+
+// PersistentVolumeClaimRetentionPolicyType is what happens to a PVC when its pod is removed.
+type PersistentVolumeClaimRetentionPolicyType string
+
+const (
+	// RetainPersistentVolumeClaimRetentionPolicyType keeps the PVC, it is no longer owned by the workload.
+	RetainPersistentVolumeClaimRetentionPolicyType PersistentVolumeClaimRetentionPolicyType = "Retain"
+	// DeletePersistentVolumeClaimRetentionPolicyType deletes the PVC once its pod is removed.
+	DeletePersistentVolumeClaimRetentionPolicyType PersistentVolumeClaimRetentionPolicyType = "Delete"
+)
+
+// PersistentVolumeClaimRetentionPolicy describes the lifecycle of the PVCs created from VolumeClaimTemplates.
+// If a policy is not set, a CloneSet deletes the PVCs with their pods, an Advanced StatefulSet retains them.
+type PersistentVolumeClaimRetentionPolicy struct {
+	// +kubebuilder:validation:Enum=Retain;Delete
+
+	// WhenScaled applies to the PVCs of the pods removed by a scale in.
+	WhenScaled PersistentVolumeClaimRetentionPolicyType `json:"whenScaled,omitempty"`
+
+	// +kubebuilder:validation:Enum=Retain;Delete
+
+	// WhenDeleted applies to the PVCs of the pods removed by a scale in to zero, after which the workload is deleted.
+	WhenDeleted PersistentVolumeClaimRetentionPolicyType `json:"whenDeleted,omitempty"`
 }
 
 // DeployFlowSpec defines the desired state of DeployFlow
@@ -139,10 +191,312 @@ type DeployUpdateStrategy struct {
 	// +kubebuilder:validation:Optional
 	Canary int `json:"canary,omitempty"`
 
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// BakeSeconds is the minimum time new pods of a batch serve traffic before old pods are removed,
+	// it is counted from the time new pods are pulled in.
+	BakeSeconds int32 `json:"bakeSeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// CanaryBakeSeconds is the bake time of the canary batch, defaults to .bakeSeconds.
+	CanaryBakeSeconds *int32 `json:"canaryBakeSeconds,omitempty"`
+
 	// +kubebuilder:validation:Optional
 
 	// Stage describes the desired stage you want to go to.
 	Stage BatchPhase `json:"stage,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// Analysis evaluates metrics while a batch is Smoked (canary only) or Baking, to promote, pause or fail the batch.
+	Analysis *Analysis `json:"analysis,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Pause;Rollback;Abort
+
+	// FailurePolicy is the action taken when a batch fails, candidates are "Pause", "Rollback" and "Abort".
+	// "Pause" pauses the deploy and waits for a human, "Rollback" creates a rollback deploy to the previous successful revision,
+	// "Abort" aborts the deploy and leaves the CloneSet as it is. Defaults to "Pause".
+	FailurePolicy FailurePolicy `json:"failurePolicy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// RevertOnCancel indicates that the updated pods are reverted to the previous revision in batches when the deploy is canceled,
+	// the deploy is Reverting until all pods are reverted, and then Canceled. It works for update and rollback only.
+	RevertOnCancel bool `json:"revertOnCancel,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// Routing sends requests matching headers or a cookie to the canary pods through a dedicated Service during the canary batch,
+	// so that they can be tested on purpose before any real user hits them. Headers and cookie work with .canary only.
+	// A share of all requests is routed to the updated pods if .routing.weight or .steps[].weight is set.
+	Routing *CanaryRouting `json:"routing,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// BlueGreen brings up a full set of new pods next to the old ones instead of updating in batches,
+	// traffic is switched to the new pods at once when the deploy is promoted. It works for update and rollback only.
+	BlueGreen *BlueGreenStrategy `json:"blueGreen,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// Shadowing mirrors a copy of requests to the canary pods in a Shadowing phase between Smoked and Baking,
+	// before the canary pods are pulled in. It works with .canary only.
+	Shadowing *Shadowing `json:"shadowing,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// Workload customizes the update strategy of the CloneSet and the pods it creates. Fields not set are taken from
+	// the ConfigMap triton-defaults in the namespace of the deploy, and then the built-in defaults.
+	Workload *WorkloadStrategy `json:"workload,omitempty"`
+}
+
+// PodUpdatePolicyType is how a CloneSet updates its pods.
+type PodUpdatePolicyType string
+
+const (
+	// RecreatePodUpdatePolicy deletes the old pods and creates new ones.
+	RecreatePodUpdatePolicy PodUpdatePolicyType = "ReCreate"
+	// InPlaceIfPossiblePodUpdatePolicy updates the pods in place if only the images are changed, otherwise recreates them.
+	InPlaceIfPossiblePodUpdatePolicy PodUpdatePolicyType = "InPlaceIfPossible"
+	// InPlaceOnlyPodUpdatePolicy always updates the pods in place, the update is rejected if it can not be done in place.
+	InPlaceOnlyPodUpdatePolicy PodUpdatePolicyType = "InPlaceOnly"
+)
+
+// WorkloadStrategy describes how the CloneSet updates its pods, and what the pods are created with.
+// The namespace defaults are read from the keys of the same names in the ConfigMap triton-defaults,
+// imagePullSecrets and readinessGates are comma separated there, an empty imagePullSecrets sets none.
+type WorkloadStrategy struct {
+	// +kubebuilder:validation:Optional
+
+	// MaxSurge is the max number of pods created above the replicas when the CloneSet is updated, defaults to 60%.
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// MaxUnavailable is the max number of pods unavailable when the CloneSet is updated, defaults to 0.
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=ReCreate;InPlaceIfPossible;InPlaceOnly
+
+	// PodUpdatePolicy is how the CloneSet updates its pods, candidates are "ReCreate", "InPlaceIfPossible" and "InPlaceOnly".
+	// Defaults to "ReCreate".
+	PodUpdatePolicy PodUpdatePolicyType `json:"podUpdatePolicy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// ImagePullSecrets replaces the image pull secrets of the pods, defaults to proharborregcred.
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// ReadinessGates are added to the pods next to apps.triton.io/ready, which is always set since pods are pulled in by it.
+	ReadinessGates []corev1.PodReadinessGate `json:"readinessGates,omitempty"`
+}
+
+// 流量镜像方式
+type MirrorType string
+
+const (
+	// MirrorVirtualService mirrors requests by the mirror rule of an Istio VirtualService.
+	MirrorVirtualService MirrorType = "VirtualService"
+	// MirrorHTTPRoute mirrors requests by a RequestMirror filter of a Gateway API HTTPRoute.
+	MirrorHTTPRoute MirrorType = "HTTPRoute"
+)
+
+// Shadowing describes how requests are mirrored to the canary pods. The canary pods are labeled with apps.triton.io/canary,
+// and requests sent to the stable Service are mirrored to a canary Service selecting them, responses of the canary pods are discarded.
+// An existing mirror of the VirtualService routes to the stable Service is replaced, and removed after the Shadowing phase.
+type Shadowing struct {
+	// +kubebuilder:validation:Enum=VirtualService;HTTPRoute
+
+	// Type is the kind of the route, candidates are "VirtualService" and "HTTPRoute".
+	Type MirrorType `json:"type"`
+
+	// Service is the stable Service of the app, the canary Service is copied from it.
+	Service string `json:"service"`
+
+	// Route is the name of the VirtualService or HTTPRoute sending requests to the stable Service.
+	Route string `json:"route"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+
+	// Percent is the percent of requests mirrored, defaults to 100. HTTPRoute supports it since Gateway API v1.2.
+	Percent *int32 `json:"percent,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// DurationSeconds is how long requests are mirrored before the canary pods are pulled in. Defaults to 300.
+	DurationSeconds *int32 `json:"durationSeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// Analysis evaluates metrics during the Shadowing phase, defaults to .analysis.
+	Analysis *Analysis `json:"analysis,omitempty"`
+}
+
+// BlueGreenStrategy describes how traffic is switched between the old and the new set of pods.
+type BlueGreenStrategy struct {
+	// +kubebuilder:validation:Optional
+
+	// Service is the Service of the app, its selector is pinned to the revision serving traffic, and flipped on promotion.
+	// If it is empty, new pods are pulled in and old pods are pulled out by the traffic provider instead.
+	Service string `json:"service,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// AutoPromote promotes the deploy once all new pods are ready, otherwise it waits in Preview for a Promote.
+	AutoPromote bool `json:"autoPromote,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// ScaleDownDelaySeconds is how long the old pods are kept after promotion, so that traffic can be switched back at once.
+	// Defaults to 600.
+	ScaleDownDelaySeconds *int32 `json:"scaleDownDelaySeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// Promoted indicates that traffic should be switched to the new pods.
+	Promoted bool `json:"promoted,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// SwitchedBack indicates that traffic should be switched back to the old pods, and the new pods are removed.
+	SwitchedBack bool `json:"switchedBack,omitempty"`
+}
+
+// 金丝雀路由方式
+type RoutingType string
+
+const (
+	// RoutingHTTPRoute routes by a Gateway API HTTPRoute.
+	RoutingHTTPRoute RoutingType = "HTTPRoute"
+	// RoutingIngress routes by an NGINX Ingress with canary annotations.
+	RoutingIngress RoutingType = "Ingress"
+)
+
+// CanaryRouting describes how requests are routed to the canary pods. The canary pods are labeled with apps.triton.io/canary,
+// a canary Service selecting them and a canary route sending matched requests to it are created, and removed once the deploy is finished.
+type CanaryRouting struct {
+	// +kubebuilder:validation:Enum=HTTPRoute;Ingress
+
+	// Type is the kind of the route, candidates are "HTTPRoute" and "Ingress".
+	Type RoutingType `json:"type"`
+
+	// Service is the stable Service of the app, the canary Service is copied from it.
+	Service string `json:"service"`
+
+	// Route is the name of the HTTPRoute or Ingress sending requests to the stable Service, the canary route is copied from it.
+	Route string `json:"route"`
+
+	// +kubebuilder:validation:Optional
+
+	// Headers are matched against requests, all of them must match. NGINX Ingress supports one header only.
+	Headers []HeaderMatch `json:"headers,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// Cookie is matched against requests. NGINX Ingress routes requests whose cookie value is "always", the value is ignored.
+	Cookie *CookieMatch `json:"cookie,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+
+	// Weight is the percent of requests routed to the canary batch besides the matched ones, regardless of its replicas.
+	// Once traffic is weighted, the route sends the rest of requests to a stable Service selecting the pods not updated yet,
+	// and the weight goes on with .steps[].weight, it is 100 in the last batch.
+	Weight int32 `json:"weight,omitempty"`
+}
+
+type HeaderMatch struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type CookieMatch struct {
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Optional
+	Value string `json:"value,omitempty"`
+}
+
+// 批次失败后的处理方式
+type FailurePolicy string
+
+const (
+	FailurePause    FailurePolicy = "Pause"
+	FailureRollback FailurePolicy = "Rollback"
+	FailureAbort    FailurePolicy = "Abort"
+)
+
+// 指标分析失败后的处理方式
+type AnalysisFailurePolicy string
+
+const (
+	AnalysisPause AnalysisFailurePolicy = "Pause"
+	AnalysisFail  AnalysisFailurePolicy = "Fail"
+)
+
+type Analysis struct {
+	// +kubebuilder:validation:Optional
+
+	// Address is the address of a Prometheus-compatible server, ex: http://prometheus:9090.
+	// Defaults to the address of the controller (--analysis-address).
+	Address string `json:"address,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// IntervalSeconds is the time interval between two runs. Defaults to 30.
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// SuccessfulRuns is the number of consecutive successful runs required to promote the batch. Defaults to 1.
+	SuccessfulRuns int32 `json:"successfulRuns,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// InconclusiveLimit is the number of consecutive errored or inconclusive runs allowed,
+	// the analysis fails and .onFailure is taken once it is exceeded. Defaults to 5.
+	InconclusiveLimit int32 `json:"inconclusiveLimit,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Pause;Fail
+
+	// OnFailure is the action taken when a failure condition is met, candidates are "Pause" and "Fail".
+	// "Fail" fails the batch and applies .failurePolicy if it is "Rollback" or "Abort". Defaults to "Pause".
+	OnFailure AnalysisFailurePolicy `json:"onFailure,omitempty"`
+
+	Metrics []AnalysisMetric `json:"metrics"`
+}
+
+type AnalysisMetric struct {
+	Name string `json:"name"`
+
+	// Query is a PromQL query whose result is a scalar or a single-sample vector.
+	Query string `json:"query"`
+
+	// +kubebuilder:validation:Optional
+
+	// SuccessCondition is met if the result matches it, ex: "< 0.01".
+	SuccessCondition string `json:"successCondition,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// FailureCondition is met if the result matches it, ex: ">= 0.05".
+	FailureCondition string `json:"failureCondition,omitempty"`
 }
 
 type DeployNonUpdateStrategy struct {
@@ -168,21 +522,165 @@ type BaseStrategy struct {
 
 	// number of pods that can be scheduled at a time. Value can be an absolute number (ex: 5) or a percentage of desired pods (ex: 10%).
 	// Absolute number is calculated from percentage by rounding up. Defaults to the same value with Replicas
-	// Value can be changed during a deploy. If it is changed, .status.batches needs to be calculated again.
+	// Value can be changed during a deploy. If it is changed, the remaining batches are planned again and recorded in .status.replans.
 	BatchSize *intstr.IntOrString `json:"batchSize,omitempty"`
 
+	// +kubebuilder:validation:Optional
+
+	// Steps is the schedule of batches, ex: [1, 5%, 25%, 50%, 100%], .batchSize is ignored if it is set.
+	// A canary batch is still planned first if .canary is set.
+	Steps []BatchStep `json:"steps,omitempty"`
+
 	// Minimum time interval to wait between two batches
 	BatchIntervalSeconds int32 `json:"batchIntervalSeconds,omitempty"`
 
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// BatchTimeoutSeconds is the progress deadline of a batch, a batch staying in Smoking or Baking longer than it
+	// is marked as SmokeFailed or BakeFailed, and .failurePolicy is applied. 0 means no deadline.
+	BatchTimeoutSeconds int32 `json:"batchTimeoutSeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// TimeoutSeconds is the progress deadline of the whole deploy, counted from its start or the last resume after
+	// a timeout, the time waiting for the user is included. 0 means no deadline.
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// GracefulPeriodSeconds is the grace period of the pods deleted by the controller directly, ex: in a restart.
+	// 0 means the terminationGracePeriodSeconds of the pod is used.
+	GracefulPeriodSeconds int32 `json:"gracefulPeriodSeconds,omitempty"`
+
 	// Deploy mode, candidates are "auto" and "manual", if not set, default to "manual".
 	// "manual" indicates that the DeployFlow is controlled by user, he can make progress by updating "Batches",
 	// "auto" indicates that the DeployFlow will always move forward no matter what "Batches" is.
 	Mode DeployMode `json:"mode,omitempty"`
 
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// MaxFailedReplicas is the max number of failed pods tolerated in a batch, a batch within tolerance keeps going
+	// instead of pausing the deploy. Defaults to 0.
+	MaxFailedReplicas int32 `json:"maxFailedReplicas,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+
+	// MaxFailedPercent is the max percentage of failed pods tolerated in a batch, rounded down.
+	// If both .maxFailedReplicas and .maxFailedPercent are set, the smaller one is used.
+	MaxFailedPercent int32 `json:"maxFailedPercent,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// ReplaceFailedPods indicates that the tolerated failed pods are deleted to be created again,
+	// the replaced pods still count in the tolerance of the batch.
+	ReplaceFailedPods bool `json:"replaceFailedPods,omitempty"`
+
 	// +kubebuilder:default=1
 
 	// Batches is the number of batch you want to finish
 	Batches int `json:"batches,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// TrafficProvider is the name of the provider used to pull pods in and out, ex: readiness.
+	// If not set, the default provider of the controller (--traffic-provider) is used.
+	TrafficProvider string `json:"trafficProvider,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// PullInTimeoutSeconds is the max time to wait for new pods to be enabled by the traffic provider,
+	// pods not enabled in time are marked as PullInFailed. Defaults to 20s.
+	PullInTimeoutSeconds int32 `json:"pullInTimeoutSeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// PreBatchHooks are called before a batch starts, the batch does not start until all of them succeed.
+	PreBatchHooks []Webhook `json:"preBatchHooks,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// PostBatchHooks are called after old pods of a batch are pulled out, the batch is not finished until all of them succeed.
+	PostBatchHooks []Webhook `json:"postBatchHooks,omitempty"`
+}
+
+type BatchStep struct {
+	// Replicas is the total number of pods processed once this step is finished. Value can be an absolute number (ex: 5)
+	// or a percentage of pods to process in this deploy (ex: 10%). Absolute number is calculated from percentage by rounding up.
+	// A step processing no more pods than the previous ones is skipped, and the remaining pods after the last step
+	// are processed in a final batch.
+	Replicas intstr.IntOrString `json:"replicas"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// IntervalSeconds is the time interval to wait after this step, it overrides .batchIntervalSeconds if it is set.
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// Pause indicates that the deploy waits for the user after this step even in "auto" mode,
+	// it moves forward once .batches is set to a later batch.
+	Pause bool `json:"pause,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+
+	// Weight is the percent of requests routed to the updated pods once the pods of this step are ready, it requires .routing
+	// of the update strategy. The weight of the previous batch is kept if it is not set.
+	Weight *int32 `json:"weight,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// NodeSelector limits the pods updated in this step to the nodes it selects, it works for a DaemonSet only.
+	// .replicas still counts the pods, it should not process more pods than the selected nodes not updated yet.
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+}
+
+// webhook 失败后的处理方式
+type WebhookFailurePolicy string
+
+const (
+	WebhookBlock WebhookFailurePolicy = "Block"
+	WebhookFail  WebhookFailurePolicy = "Fail"
+	WebhookPause WebhookFailurePolicy = "Pause"
+)
+
+type Webhook struct {
+	Name string `json:"name"`
+
+	// URL is called with a POST request whose body describes the DeployFlow and the batch in JSON.
+	URL string `json:"url"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// TimeoutSeconds is the timeout of a call. Defaults to 10.
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+
+	// Retries is the number of retries before the failure policy is applied.
+	Retries int32 `json:"retries,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Block;Fail;Pause
+
+	// FailurePolicy is applied when a call fails with a non-2xx response or a timeout after all retries,
+	// "Block" keeps retrying, "Fail" fails the batch and applies .failurePolicy if it is "Rollback" or "Abort",
+	// "Pause" pauses the deploy. Defaults to "Block".
+	FailurePolicy WebhookFailurePolicy `json:"failurePolicy,omitempty"`
 }
 
 // DeployFlowStatus defines the observed state of DeployFlow
@@ -238,6 +736,150 @@ type DeployFlowStatus struct {
 
 	// +nullable
 	FinishedAt metav1.Time `json:"finishedAt,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// RollbackBy is the name of the deploy created to roll back this one.
+	RollbackBy string `json:"rollbackBy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// RollbackOf is the name of the failed deploy rolled back by this one.
+	RollbackOf string `json:"rollbackOf,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +nullable
+
+	// Replans records the changes of remaining batches during the deploy, ex: .batchSize is changed.
+	Replans []Replan `json:"replans,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +nullable
+
+	// Revert is the progress of reverting updated pods after the deploy is canceled.
+	Revert *RevertStatus `json:"revert,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +nullable
+
+	// BlueGreen is the progress of a blue/green deploy.
+	BlueGreen *BlueGreenStatus `json:"blueGreen,omitempty"`
+}
+
+// 蓝绿发布阶段
+type BlueGreenPhase string
+
+const (
+	// BlueGreenScalingUp means the new pods are being created next to the old ones.
+	BlueGreenScalingUp BlueGreenPhase = "ScalingUp"
+	// BlueGreenPreview means all new pods are ready, and traffic still goes to the old pods.
+	BlueGreenPreview BlueGreenPhase = "Preview"
+	// BlueGreenPromoted means traffic goes to the new pods, and the old pods are kept for switching back.
+	BlueGreenPromoted BlueGreenPhase = "Promoted"
+	// BlueGreenScalingDown means the old pods are being removed.
+	BlueGreenScalingDown BlueGreenPhase = "ScalingDown"
+	// BlueGreenSwitchedBack means traffic goes back to the old pods, and the new pods are being removed.
+	BlueGreenSwitchedBack BlueGreenPhase = "SwitchedBack"
+)
+
+// BlueGreenStatus describes a blue/green deploy, the CloneSet is scaled out with the partition kept at the number of old pods,
+// so that a full set of new pods is created, and scaled in again with the old or the new pods deleted when it is finished.
+type BlueGreenStatus struct {
+	Phase BlueGreenPhase `json:"phase"`
+
+	// StableRevision is the revision of the old pods.
+	StableRevision string `json:"stableRevision"`
+
+	// UpdateRevision is the revision of the new pods.
+	UpdateRevision string `json:"updateRevision"`
+
+	// +kubebuilder:validation:Optional
+
+	// ActiveRevision is the revision serving traffic.
+	ActiveRevision string `json:"activeRevision,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +nullable
+	OldPods []PodInfo `json:"oldPods,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +nullable
+	NewPods []PodInfo `json:"newPods,omitempty"`
+
+	// +nullable
+	StartedAt metav1.Time `json:"startedAt,omitempty"`
+
+	// +nullable
+	PromotedAt metav1.Time `json:"promotedAt,omitempty"`
+
+	// +nullable
+	SwitchedBackAt metav1.Time `json:"switchedBackAt,omitempty"`
+}
+
+// RevertStatus describes the revert of a canceled deploy, updated pods are reverted in batches,
+// pods of the previous revision are created and pulled in first, and then the updated ones are pulled out and removed.
+type RevertStatus struct {
+	// Replicas is the number of updated replicas when the revert is started.
+	Replicas int `json:"replicas"`
+
+	// +kubebuilder:validation:Optional
+	RevertedReplicas int `json:"revertedReplicas"`
+
+	// Batch is the number of current revert batch, starting from 1.
+	Batch int `json:"batch"`
+
+	// +kubebuilder:validation:Optional
+	BatchSize int `json:"batchSize"`
+
+	// +kubebuilder:validation:Optional
+
+	// Partition is the partition of the CloneSet after current batch is reverted.
+	Partition int `json:"partition"`
+
+	// Phase is the phase of current revert batch, it goes through Pending, Smoking, Smoked, Baking and Baked.
+	Phase BatchPhase `json:"phase"`
+
+	// +kubebuilder:validation:Optional
+	// +nullable
+
+	// Pods is the pods of the previous revision created in current batch.
+	Pods []PodInfo `json:"pods,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +nullable
+
+	// PulledOutPods is the names of updated pods pulled out in current batch.
+	PulledOutPods []string `json:"pulledOutPods,omitempty"`
+
+	// +nullable
+	StartedAt metav1.Time `json:"startedAt,omitempty"`
+
+	// +nullable
+	BatchStartedAt metav1.Time `json:"batchStartedAt,omitempty"`
+
+	// +nullable
+	PulledInAt metav1.Time `json:"pulledInAt,omitempty"`
+
+	// +nullable
+	PulledOutAt metav1.Time `json:"pulledOutAt,omitempty"`
+
+	// +nullable
+	FinishedAt metav1.Time `json:"finishedAt,omitempty"`
+}
+
+type Replan struct {
+	// Batch is current batch when the remaining batches are planned again.
+	Batch int `json:"batch"`
+
+	OldBatches int `json:"oldBatches"`
+	Batches    int `json:"batches"`
+
+	// OldBatchSize and BatchSize are the size of current batch, they are different only if current batch is not started yet.
+	OldBatchSize int `json:"oldBatchSize"`
+	BatchSize    int `json:"batchSize"`
+
+	// +nullable
+	ReplannedAt metav1.Time `json:"replannedAt,omitempty"`
 }
 
 type BatchCondition struct {
@@ -246,10 +888,33 @@ type BatchCondition struct {
 	// 批次大小
 	BatchSize int  `json:"batchSize"`
 	Canary    bool `json:"canary"`
+
+	// +kubebuilder:validation:Optional
+
+	// Step is the index of .steps starting from 1 which this batch is planned by, 0 means it is not planned by a step.
+	Step int `json:"step,omitempty"`
+
 	// 批次阶段（Smoked/Baking/Baked）
 	Phase          BatchPhase `json:"phase"`
 	FailedReplicas int        `json:"failedReplicas"`
 
+	// +kubebuilder:validation:Optional
+
+	// Weight is the percent of requests routed to the updated pods in this batch, it is nil if traffic is not weighted.
+	Weight *int32 `json:"weight,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +nullable
+
+	// FailedPods is the names of failed pods tolerated in this batch, including the replaced ones.
+	FailedPods []string `json:"failedPods,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +nullable
+
+	// AcceptedFailedPods is the names of failed pods accepted by RetryBatch, they are not counted in the tolerance.
+	AcceptedFailedPods []string `json:"acceptedFailedPods,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// +nullable
 	Pods []PodInfo `json:"pods"`
@@ -257,13 +922,119 @@ type BatchCondition struct {
 	// +nullable
 	StartedAt metav1.Time `json:"startedAt,omitempty"`
 
+	// +nullable
+
+	// ShadowedAt is the time when requests start to be mirrored to the pods of this batch.
+	ShadowedAt metav1.Time `json:"shadowedAt,omitempty"`
+
 	// +nullable
 	PulledInAt metav1.Time `json:"pulledInAt,omitempty"`
 
+	// +nullable
+	PulledOutAt metav1.Time `json:"pulledOutAt,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +nullable
+
+	// PulledOutPods is the names of old pods pulled out in this batch.
+	PulledOutPods []string `json:"pulledOutPods,omitempty"`
+
+	// +nullable
+
+	// RemovedAt is the time when the workload is patched to remove the old pods of this batch, it is patched once in a batch
+	// even if the batch is requeued after that, ex: by a post-batch hook.
+	RemovedAt metav1.Time `json:"removedAt,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +nullable
+	Analysis *AnalysisStatus `json:"analysis,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +nullable
+	Hooks []WebhookStatus `json:"hooks,omitempty"`
+
+	// +nullable
+
+	// TimedOutAt is the time when the batch exceeded its progress deadline.
+	TimedOutAt metav1.Time `json:"timedOutAt,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// TimedOutGeneration is the generation of the DeployFlow when the batch exceeded its progress deadline,
+	// the deploy can not be resumed until its spec is changed.
+	TimedOutGeneration int64 `json:"timedOutGeneration,omitempty"`
+
+	// +nullable
+
+	// ResumedAt is the last time the batch is resumed after a timeout, the progress deadline is counted from it.
+	ResumedAt metav1.Time `json:"resumedAt,omitempty"`
+
 	// +nullable
 	FinishedAt metav1.Time `json:"finishedAt,omitempty"`
 }
 
+// webhook 调用阶段
+type WebhookStage string
+
+const (
+	PreBatch  WebhookStage = "PreBatch"
+	PostBatch WebhookStage = "PostBatch"
+)
+
+type WebhookStatus struct {
+	Name      string       `json:"name"`
+	Stage     WebhookStage `json:"stage"`
+	Succeeded bool         `json:"succeeded"`
+	Attempts  int32        `json:"attempts"`
+
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// ObservedGeneration is the generation of the DeployFlow when the failure policy is applied,
+	// the deploy can not be resumed until its spec is changed.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// +nullable
+	CalledAt metav1.Time `json:"calledAt,omitempty"`
+}
+
+// 指标分析阶段
+type AnalysisPhase string
+
+const (
+	AnalysisRunning    AnalysisPhase = "Running"
+	AnalysisSuccessful AnalysisPhase = "Successful"
+	AnalysisFailed     AnalysisPhase = "Failed"
+)
+
+type AnalysisStatus struct {
+	// Stage is the batch phase in which the analysis runs.
+	Stage BatchPhase    `json:"stage"`
+	Phase AnalysisPhase `json:"phase"`
+
+	Runs           int32 `json:"runs"`
+	SuccessfulRuns int32 `json:"successfulRuns"`
+
+	// +kubebuilder:validation:Optional
+
+	// InconclusiveRuns is the number of consecutive errored or inconclusive runs.
+	InconclusiveRuns int32 `json:"inconclusiveRuns,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+
+	// +kubebuilder:validation:Optional
+
+	// ObservedGeneration is the generation of the DeployFlow when the analysis failed,
+	// the deploy can not be resumed until its spec is changed.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// +nullable
+	LastRunAt metav1.Time `json:"lastRunAt,omitempty"`
+}
+
 type PodInfo struct {
 	Name         string `json:"name"`
 	IP           string `json:"ip"`
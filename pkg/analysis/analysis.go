@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package analysis judges a batch by metrics.
+package analysis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+)
+
+type Result int
+
+const (
+	// Inconclusive means neither the success conditions nor any failure condition is met.
+	Inconclusive Result = iota
+	Successful
+	Failed
+)
+
+// Evaluate runs all metrics, it fails if any failure condition is met, and succeeds only if all metrics succeed.
+// A metric without a success condition succeeds if its failure condition is not met, and vice versa.
+func Evaluate(q Querier, metrics []tritonappsv1alpha1.AnalysisMetric) (Result, string, error) {
+	result := Successful
+	messages := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		v, err := q.Query(m.Query)
+		if err != nil {
+			return Inconclusive, "", fmt.Errorf("failed to query metric %s: %w", m.Name, err)
+		}
+
+		failed, err := match(m.FailureCondition, v)
+		if err != nil {
+			return Inconclusive, "", fmt.Errorf("invalid failure condition of metric %s: %w", m.Name, err)
+		}
+		succeeded, err := match(m.SuccessCondition, v)
+		if err != nil {
+			return Inconclusive, "", fmt.Errorf("invalid success condition of metric %s: %w", m.Name, err)
+		}
+
+		switch {
+		case m.FailureCondition != "" && failed,
+			m.FailureCondition == "" && m.SuccessCondition != "" && !succeeded:
+			return Failed, fmt.Sprintf("metric %s is %s, success condition is %q, failure condition is %q",
+				m.Name, strconv.FormatFloat(v, 'f', -1, 64), m.SuccessCondition, m.FailureCondition), nil
+		case m.SuccessCondition != "" && !succeeded:
+			result = Inconclusive
+		}
+		messages = append(messages, fmt.Sprintf("%s=%s", m.Name, strconv.FormatFloat(v, 'f', -1, 64)))
+	}
+
+	return result, strings.Join(messages, ", "), nil
+}
+
+var operators = []string{"<=", ">=", "==", "!=", "<", ">"}
+
+// match returns true if v matches the condition like "< 0.01", an empty condition never matches.
+func match(condition string, v float64) (bool, error) {
+	condition = strings.TrimSpace(condition)
+	if condition == "" {
+		return false, nil
+	}
+
+	for _, op := range operators {
+		if !strings.HasPrefix(condition, op) {
+			continue
+		}
+
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(condition[len(op):]), 64)
+		if err != nil {
+			return false, err
+		}
+
+		switch op {
+		case "<=":
+			return v <= threshold, nil
+		case ">=":
+			return v >= threshold, nil
+		case "==":
+			return v == threshold, nil
+		case "!=":
+			return v != threshold, nil
+		case "<":
+			return v < threshold, nil
+		default:
+			return v > threshold, nil
+		}
+	}
+
+	return false, fmt.Errorf("unknown operator in %q, candidates are %s", condition, strings.Join(operators, ", "))
+}
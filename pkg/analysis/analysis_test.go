@@ -0,0 +1,146 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+)
+
+// newFakePrometheus returns a stand-in of the Prometheus query API, results are looked up by query.
+func newFakePrometheus(results map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != queryPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		result, ok := results[r.URL.Query().Get("query")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprint(w, `{"status":"error","errorType":"bad_data","error":"parse error"}`)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"status":"success","data":%s}`, result)
+	}))
+}
+
+func TestPrometheusQuery(t *testing.T) {
+	srv := newFakePrometheus(map[string]string{
+		"vector": `{"resultType":"vector","result":[{"metric":{},"value":[1435781451.781,"0.5"]}]}`,
+		"scalar": `{"resultType":"scalar","result":[1435781451.781,"2"]}`,
+		"empty":  `{"resultType":"vector","result":[]}`,
+	})
+	defer srv.Close()
+
+	p := NewPrometheus(srv.URL)
+	cases := []struct {
+		query   string
+		value   float64
+		wantErr bool
+	}{
+		{query: "vector", value: 0.5},
+		{query: "scalar", value: 2},
+		{query: "empty", wantErr: true},
+		{query: "invalid", wantErr: true},
+	}
+	for _, c := range cases {
+		v, err := p.Query(c.query)
+		if (err != nil) != c.wantErr {
+			t.Errorf("query %s: expected error %t, got %v", c.query, c.wantErr, err)
+			continue
+		}
+		if v != c.value {
+			t.Errorf("query %s: expected %v, got %v", c.query, c.value, v)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	srv := newFakePrometheus(map[string]string{
+		"error_rate": `{"resultType":"scalar","result":[1435781451.781,"0.02"]}`,
+		"latency":    `{"resultType":"scalar","result":[1435781451.781,"150"]}`,
+	})
+	defer srv.Close()
+
+	p := NewPrometheus(srv.URL)
+	cases := []struct {
+		name    string
+		metrics []tritonappsv1alpha1.AnalysisMetric
+		result  Result
+		wantErr bool
+	}{
+		{
+			name: "all succeed",
+			metrics: []tritonappsv1alpha1.AnalysisMetric{
+				{Name: "error_rate", Query: "error_rate", SuccessCondition: "< 0.05", FailureCondition: ">= 0.1"},
+				{Name: "latency", Query: "latency", SuccessCondition: "<= 200"},
+			},
+			result: Successful,
+		},
+		{
+			name: "failure condition met",
+			metrics: []tritonappsv1alpha1.AnalysisMetric{
+				{Name: "error_rate", Query: "error_rate", FailureCondition: "> 0.01"},
+			},
+			result: Failed,
+		},
+		{
+			name: "success condition not met without failure condition",
+			metrics: []tritonappsv1alpha1.AnalysisMetric{
+				{Name: "latency", Query: "latency", SuccessCondition: "< 100"},
+			},
+			result: Failed,
+		},
+		{
+			name: "inconclusive",
+			metrics: []tritonappsv1alpha1.AnalysisMetric{
+				{Name: "error_rate", Query: "error_rate", SuccessCondition: "< 0.01", FailureCondition: ">= 0.1"},
+			},
+			result: Inconclusive,
+		},
+		{
+			name: "query error",
+			metrics: []tritonappsv1alpha1.AnalysisMetric{
+				{Name: "missing", Query: "missing", SuccessCondition: "< 1"},
+			},
+			result:  Inconclusive,
+			wantErr: true,
+		},
+		{
+			name: "invalid condition",
+			metrics: []tritonappsv1alpha1.AnalysisMetric{
+				{Name: "latency", Query: "latency", SuccessCondition: "~ 1"},
+			},
+			result:  Inconclusive,
+			wantErr: true,
+		},
+	}
+	for _, c := range cases {
+		result, _, err := Evaluate(p, c.metrics)
+		if (err != nil) != c.wantErr {
+			t.Errorf("%s: expected error %t, got %v", c.name, c.wantErr, err)
+		}
+		if result != c.result {
+			t.Errorf("%s: expected result %d, got %d", c.name, c.result, result)
+		}
+	}
+}
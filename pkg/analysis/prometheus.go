@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const queryPath = "/api/v1/query"
+
+// Querier runs a query and returns a single value.
+type Querier interface {
+	Query(query string) (float64, error)
+}
+
+// Prometheus queries a Prometheus-compatible HTTP API.
+type Prometheus struct {
+	addr string
+	http *http.Client
+}
+
+var _ Querier = &Prometheus{}
+
+func NewPrometheus(addr string) *Prometheus {
+	return &Prometheus{
+		addr: strings.TrimSuffix(addr, "/"),
+		http: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type queryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+type sample struct {
+	Value []interface{} `json:"value"`
+}
+
+// Query runs an instant query, the result must be a scalar or a vector with exactly one sample.
+func (p *Prometheus) Query(query string) (float64, error) {
+	resp, err := p.http.Get(p.addr + queryPath + "?" + url.Values{"query": {query}}.Encode())
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	r := &queryResponse{}
+	if err := json.Unmarshal(body, r); err != nil {
+		return 0, fmt.Errorf("failed to decode result of query %q with status %d: %v", query, resp.StatusCode, err)
+	}
+	if r.Status != "success" {
+		return 0, fmt.Errorf("query %q failed: %s", query, r.Error)
+	}
+
+	var value []interface{}
+	switch r.Data.ResultType {
+	case "scalar":
+		if err := json.Unmarshal(r.Data.Result, &value); err != nil {
+			return 0, err
+		}
+	case "vector":
+		samples := []sample{}
+		if err := json.Unmarshal(r.Data.Result, &samples); err != nil {
+			return 0, err
+		}
+		if len(samples) != 1 {
+			return 0, fmt.Errorf("query %q returns %d samples, expected 1", query, len(samples))
+		}
+		value = samples[0].Value
+	default:
+		return 0, fmt.Errorf("unsupported result type %q of query %q", r.Data.ResultType, query)
+	}
+
+	// value is [<unix time>, "<value>"]
+	if len(value) != 2 {
+		return 0, fmt.Errorf("invalid value %v of query %q", value, query)
+	}
+	s, ok := value[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("invalid value %v of query %q", value, query)
+	}
+
+	return strconv.ParseFloat(s, 64)
+}
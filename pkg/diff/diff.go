@@ -0,0 +1,190 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diff compares pod templates of two revisions.
+package diff
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+type Kind string
+
+const (
+	Added    Kind = "Added"
+	Removed  Kind = "Removed"
+	Modified Kind = "Modified"
+)
+
+// Change is a changed field, Path is relative to the section it belongs to, ex: env.JAVA_OPTS, resources.limits.cpu.
+type Change struct {
+	Path string `json:"path"`
+	Kind Kind   `json:"kind"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// ContainerDiff is the changes of a container, containers are matched by name.
+type ContainerDiff struct {
+	Name    string   `json:"name"`
+	Kind    Kind     `json:"kind"`
+	Changes []Change `json:"changes,omitempty"`
+}
+
+type TemplateDiff struct {
+	Labels         []Change        `json:"labels,omitempty"`
+	Annotations    []Change        `json:"annotations,omitempty"`
+	InitContainers []ContainerDiff `json:"initContainers,omitempty"`
+	Containers     []ContainerDiff `json:"containers,omitempty"`
+}
+
+// Empty returns true if nothing is changed.
+func (d *TemplateDiff) Empty() bool {
+	return len(d.Labels) == 0 && len(d.Annotations) == 0 && len(d.InitContainers) == 0 && len(d.Containers) == 0
+}
+
+// Templates returns the changes from one template to another, nil template is treated as an empty one.
+func Templates(from, to *corev1.PodTemplateSpec) *TemplateDiff {
+	if from == nil {
+		from = &corev1.PodTemplateSpec{}
+	}
+	if to == nil {
+		to = &corev1.PodTemplateSpec{}
+	}
+
+	return &TemplateDiff{
+		Labels:         maps("", from.Labels, to.Labels),
+		Annotations:    maps("", from.Annotations, to.Annotations),
+		InitContainers: containers(from.Spec.InitContainers, to.Spec.InitContainers),
+		Containers:     containers(from.Spec.Containers, to.Spec.Containers),
+	}
+}
+
+func containers(from, to []corev1.Container) []ContainerDiff {
+	var res []ContainerDiff
+
+	fromByName := make(map[string]*corev1.Container, len(from))
+	for i := range from {
+		fromByName[from[i].Name] = &from[i]
+	}
+	toByName := make(map[string]*corev1.Container, len(to))
+	for i := range to {
+		toByName[to[i].Name] = &to[i]
+	}
+
+	for i := range from {
+		if _, ok := toByName[from[i].Name]; !ok {
+			res = append(res, ContainerDiff{Name: from[i].Name, Kind: Removed, Changes: container(&from[i], &corev1.Container{})})
+		}
+	}
+	for i := range to {
+		c := &to[i]
+		old, ok := fromByName[c.Name]
+		if !ok {
+			res = append(res, ContainerDiff{Name: c.Name, Kind: Added, Changes: container(&corev1.Container{}, c)})
+			continue
+		}
+		if changes := container(old, c); len(changes) > 0 {
+			res = append(res, ContainerDiff{Name: c.Name, Kind: Modified, Changes: changes})
+		}
+	}
+
+	return res
+}
+
+func container(from, to *corev1.Container) []Change {
+	var res []Change
+
+	res = appendChange(res, "image", from.Image, to.Image)
+	res = appendChange(res, "command", strings.Join(from.Command, " "), strings.Join(to.Command, " "))
+	res = appendChange(res, "args", strings.Join(from.Args, " "), strings.Join(to.Args, " "))
+	res = append(res, maps("env.", envs(from.Env), envs(to.Env))...)
+	res = append(res, maps("resources.limits.", quantities(from.Resources.Limits), quantities(to.Resources.Limits))...)
+	res = append(res, maps("resources.requests.", quantities(from.Resources.Requests), quantities(to.Resources.Requests))...)
+
+	return res
+}
+
+// maps returns the changes of two maps, sorted by key.
+func maps(prefix string, from, to map[string]string) []Change {
+	keys := make([]string, 0, len(from)+len(to))
+	for k := range from {
+		keys = append(keys, k)
+	}
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var res []Change
+	for _, k := range keys {
+		f, inFrom := from[k]
+		t, inTo := to[k]
+		switch {
+		case !inFrom:
+			res = append(res, Change{Path: prefix + k, Kind: Added, To: t})
+		case !inTo:
+			res = append(res, Change{Path: prefix + k, Kind: Removed, From: f})
+		case f != t:
+			res = append(res, Change{Path: prefix + k, Kind: Modified, From: f, To: t})
+		}
+	}
+
+	return res
+}
+
+func appendChange(res []Change, path, from, to string) []Change {
+	switch {
+	case from == to:
+		return res
+	case from == "":
+		return append(res, Change{Path: path, Kind: Added, To: to})
+	case to == "":
+		return append(res, Change{Path: path, Kind: Removed, From: from})
+	default:
+		return append(res, Change{Path: path, Kind: Modified, From: from, To: to})
+	}
+}
+
+// envs returns the env of a container by name, valueFrom is encoded as json.
+func envs(env []corev1.EnvVar) map[string]string {
+	res := make(map[string]string, len(env))
+	for _, e := range env {
+		if e.ValueFrom != nil {
+			v, _ := json.Marshal(e.ValueFrom)
+			res[e.Name] = string(v)
+			continue
+		}
+		res[e.Name] = e.Value
+	}
+
+	return res
+}
+
+func quantities(l corev1.ResourceList) map[string]string {
+	res := make(map[string]string, len(l))
+	for k, v := range l {
+		res[string(k)] = v.String()
+	}
+
+	return res
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTemplates(t *testing.T) {
+	from := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "demo", "version": "v1"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "app",
+					Image: "demo:v1",
+					Env:   []corev1.EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}},
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+					},
+				},
+				{Name: "sidecar", Image: "sidecar:v1"},
+			},
+		},
+	}
+	to := from.DeepCopy()
+	to.Labels["version"] = "v2"
+	to.Spec.Containers[0].Image = "demo:v2"
+	to.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "A", Value: "1"}, {Name: "C", Value: "3"}}
+	to.Spec.Containers[0].Resources.Limits[corev1.ResourceCPU] = resource.MustParse("2")
+	to.Spec.Containers = append(to.Spec.Containers[:1], corev1.Container{Name: "agent", Image: "agent:v1"})
+
+	d := Templates(from, to)
+
+	if expected := []Change{{Path: "version", Kind: Modified, From: "v1", To: "v2"}}; !reflect.DeepEqual(d.Labels, expected) {
+		t.Fatalf("expected label changes %+v, got %+v", expected, d.Labels)
+	}
+
+	expected := []ContainerDiff{
+		{Name: "sidecar", Kind: Removed, Changes: []Change{{Path: "image", Kind: Removed, From: "sidecar:v1"}}},
+		{Name: "app", Kind: Modified, Changes: []Change{
+			{Path: "image", Kind: Modified, From: "demo:v1", To: "demo:v2"},
+			{Path: "env.B", Kind: Removed, From: "2"},
+			{Path: "env.C", Kind: Added, To: "3"},
+			{Path: "resources.limits.cpu", Kind: Modified, From: "1", To: "2"},
+		}},
+		{Name: "agent", Kind: Added, Changes: []Change{{Path: "image", Kind: Added, To: "agent:v1"}}},
+	}
+	if !reflect.DeepEqual(d.Containers, expected) {
+		t.Fatalf("expected container changes %+v, got %+v", expected, d.Containers)
+	}
+
+	if d := Templates(from, from.DeepCopy()); !d.Empty() {
+		t.Fatalf("expected no changes, got %+v", d)
+	}
+}
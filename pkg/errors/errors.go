@@ -11,6 +11,14 @@ import (
 const LastDeployInProgress = "last deploy in progress"
 const TimeIntervalNotReached = "time interval not reached"
 const InstanceNotUp = "instance is not up yet"
+const InstanceNotDrained = "instance is not drained yet"
+const AnalysisInProgress = "analysis in progress"
+const WebhookFailed = "webhook failed"
+const ProgressDeadlineNotReached = "progress deadline not reached"
+const BakingNotFinished = "baking is not finished yet"
+const RevertInProgress = "revert in progress"
+const BlueGreenInProgress = "blue/green deploy in progress"
+const ShadowingNotFinished = "shadowing is not finished yet"
 
 type RequeueError interface {
 	RequeueAfter() time.Duration
@@ -103,3 +111,35 @@ func NewTimeIntervalNotReachedError(requeueAfter time.Duration) error {
 func NewInstanceNotUpError(requeueAfter time.Duration) error {
 	return &requeueAfterError{msg: InstanceNotUp, requeueAfter: requeueAfter}
 }
+
+func NewInstanceNotDrainedError(requeueAfter time.Duration) error {
+	return &requeueAfterError{msg: InstanceNotDrained, requeueAfter: requeueAfter}
+}
+
+func NewAnalysisInProgressError(requeueAfter time.Duration) error {
+	return &requeueAfterError{msg: AnalysisInProgress, requeueAfter: requeueAfter}
+}
+
+func NewWebhookFailedError(requeueAfter time.Duration) error {
+	return &requeueAfterError{msg: WebhookFailed, requeueAfter: requeueAfter}
+}
+
+func NewProgressDeadlineNotReachedError(requeueAfter time.Duration) error {
+	return &requeueAfterError{msg: ProgressDeadlineNotReached, requeueAfter: requeueAfter}
+}
+
+func NewBakingNotFinishedError(requeueAfter time.Duration) error {
+	return &requeueAfterError{msg: BakingNotFinished, requeueAfter: requeueAfter}
+}
+
+func NewRevertInProgressError(requeueAfter time.Duration) error {
+	return &requeueAfterError{msg: RevertInProgress, requeueAfter: requeueAfter}
+}
+
+func NewBlueGreenInProgressError(requeueAfter time.Duration) error {
+	return &requeueAfterError{msg: BlueGreenInProgress, requeueAfter: requeueAfter}
+}
+
+func NewShadowingNotFinishedError(requeueAfter time.Duration) error {
+	return &requeueAfterError{msg: ShadowingNotFinished, requeueAfter: requeueAfter}
+}
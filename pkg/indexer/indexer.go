@@ -22,6 +22,18 @@ func AddDefaultIndexers(mgr manager.Manager) error {
 	if err != nil {
 		return err
 	}
+	err = mgr.GetFieldIndexer().IndexField(
+		context.TODO(),
+		&tritonappsv1alpha1.DeployFlow{},
+		"spec.clonesetName",
+		func(rawObj runtime.Object) []string {
+			flow := rawObj.(*tritonappsv1alpha1.DeployFlow)
+			return []string{flow.Spec.Application.CloneSetName}
+		},
+	)
+	if err != nil {
+		return err
+	}
 	log.Info("Added indexer for DeployFlow")
 	return nil
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/sirupsen/logrus"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -93,14 +94,27 @@ func (r *CloneSetReconciler) doReconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	deploy, found, err := fetcher.GetDeployInCacheOwningCloneSet(cs, r.Client)
+	return r.reconcileWorkload(ctx, cs, map[string]string{appsv1.ControllerRevisionHashLabelKey: cs.Status.UpdateRevision})
+}
+
+// reconcileWorkload populates the pods of the update revision, which are matched by updatedPodsLabel,
+// into the batches of the DeployFlow controlling the workload.
+func (r *CloneSetReconciler) reconcileWorkload(ctx context.Context, obj metav1.Object, updatedPodsLabel map[string]string) (ctrl.Result, error) {
+	logger := r.logger.WithField("workload", obj.GetNamespace()+"/"+obj.GetName())
+
+	deploy, found, err := fetcher.GetDeployInCacheOwning(obj, r.Client)
 	if err != nil || !found {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
 	idl := internaldeploy.FromDeploy(deploy)
 
-	err = r.syncPodStatusInPreviousBatches(cs, idl.Unwrap())
+	// updated pods are removed on purpose in a revert or a blue/green deploy, do not count them as failed.
+	if idl.Reverting() || idl.BlueGreenStarted() {
+		return ctrl.Result{}, nil
+	}
+
+	err = r.syncPodStatusInPreviousBatches(obj.GetNamespace(), updatedPodsLabel, idl.Unwrap())
 	if err != nil {
 		r.logger.WithError(err).Errorf("sync batch pod status failed")
 		return ctrl.Result{}, nil
@@ -114,7 +128,7 @@ func (r *CloneSetReconciler) doReconcile(ctx context.Context, req ctrl.Request)
 
 	// logger.Info("Start to reconcile")
 
-	if err := r.populatePods(ctx, cs, deploy); err != nil {
+	if err := r.populatePods(ctx, obj.GetNamespace(), updatedPodsLabel, deploy); err != nil {
 		logger.WithError(err).Error("failed to populate pods")
 		return ctrl.Result{}, err
 	}
@@ -123,7 +137,7 @@ func (r *CloneSetReconciler) doReconcile(ctx context.Context, req ctrl.Request)
 	return ctrl.Result{}, nil
 }
 
-func (r *CloneSetReconciler) populatePods(ctx context.Context, cs *kruiseappsv1alpha1.CloneSet, deploy *tritonappsv1alpha1.DeployFlow) error {
+func (r *CloneSetReconciler) populatePods(ctx context.Context, ns string, updatedPodsLabel map[string]string, deploy *tritonappsv1alpha1.DeployFlow) error {
 	idl := internaldeploy.FromDeploy(deploy)
 
 	currentBatchInfo := idl.CurrentBatchInfo()
@@ -136,10 +150,7 @@ func (r *CloneSetReconciler) populatePods(ctx context.Context, cs *kruiseappsv1a
 	}
 
 	pods := &corev1.PodList{}
-	updatedPodsLabel := map[string]string{
-		appsv1.ControllerRevisionHashLabelKey: cs.Status.UpdateRevision,
-	}
-	if err := r.List(ctx, pods, client.InNamespace(cs.Namespace), client.MatchingLabels(updatedPodsLabel)); err != nil {
+	if err := r.List(ctx, pods, client.InNamespace(ns), client.MatchingLabels(updatedPodsLabel)); err != nil {
 		// do not retry, wait for next reconcile
 		return nil
 	}
@@ -157,6 +168,10 @@ func (r *CloneSetReconciler) populatePods(ctx context.Context, cs *kruiseappsv1a
 		if populatedPods.Has(p.Name) && !batchPods.Has(p.Name) || p.CreationTimestamp.Before(&currentBatchInfo.StartedAt) {
 			continue
 		}
+		// a terminating pod, ex: a failed pod deleted by RetryBatch, is replaced by a new one, do not count it in the batch.
+		if !p.DeletionTimestamp.IsZero() {
+			continue
+		}
 
 		r.logger.Infof("populate pod %s", p.Name)
 
@@ -184,16 +199,14 @@ func (r *CloneSetReconciler) populatePods(ctx context.Context, cs *kruiseappsv1a
 
 	idl.SetPodsStatus(populatedPods.List())
 	idl.SetCondition(*currentBatchInfo)
+	idl.SyncFailedReplicas()
 
 	return r.updateDeployStatus(idl)
 }
 
-func (r *CloneSetReconciler) syncPodStatusInPreviousBatches(cs *kruiseappsv1alpha1.CloneSet, deploy *tritonappsv1alpha1.DeployFlow) error {
+func (r *CloneSetReconciler) syncPodStatusInPreviousBatches(ns string, updatedPodsLabel map[string]string, deploy *tritonappsv1alpha1.DeployFlow) error {
 	pods := &corev1.PodList{}
-	updatedPodsLabel := map[string]string{
-		appsv1.ControllerRevisionHashLabelKey: cs.Status.UpdateRevision,
-	}
-	if err := r.List(context.TODO(), pods, client.InNamespace(cs.Namespace), client.MatchingLabels(updatedPodsLabel)); err != nil {
+	if err := r.List(context.TODO(), pods, client.InNamespace(ns), client.MatchingLabels(updatedPodsLabel)); err != nil {
 		// do not retry, wait for next reconcile
 		return nil
 	}
@@ -207,7 +220,7 @@ func (r *CloneSetReconciler) syncPodStatusInPreviousBatches(cs *kruiseappsv1alph
 
 	idl := internaldeploy.FromDeploy(deploy)
 	var changed bool
-	for i := idl.CurrentBatchNumber() - 1; i > 0; i-- {
+	for i := idl.CurrentBatchNumber() - 1; i >= 0; i-- {
 		batch := idl.Status.Conditions[i] // i-1
 		var failed int32
 		for j := range batch.Pods {
@@ -249,6 +262,7 @@ func (r *CloneSetReconciler) syncPodStatusInPreviousBatches(cs *kruiseappsv1alph
 	}
 
 	if changed {
+		idl.SyncFailedReplicas()
 		return r.updateDeployStatus(idl)
 	}
 
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloneset
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	"github.com/triton-io/triton/pkg/setting"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestPopulatePodsSkipsTerminatingPods checks that a failed pod deleted by a retry is not recorded as failed again
+// while it is terminating.
+func TestPopulatePodsSkipsTerminatingPods(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = tritonappsv1alpha1.AddToScheme(scheme)
+
+	startedAt := metav1.NewTime(time.Now().Add(-time.Minute))
+	created := metav1.NewTime(time.Now())
+	deleted := metav1.Now()
+	ls := map[string]string{appsv1.ControllerRevisionHashLabelKey: "v2"}
+
+	newPod := func(name string, ready bool, restarts int32, deletionTimestamp *metav1.Time) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         "default",
+				Name:              name,
+				Labels:            ls,
+				CreationTimestamp: created,
+				DeletionTimestamp: deletionTimestamp,
+			},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "app", RestartCount: restarts}},
+			},
+		}
+	}
+
+	deploy := &tritonappsv1alpha1.DeployFlow{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app-update"},
+		Spec:       tritonappsv1alpha1.DeployFlowSpec{Action: setting.Update},
+		Status: tritonappsv1alpha1.DeployFlowStatus{
+			Phase:          tritonappsv1alpha1.BatchStarted,
+			Batches:        1,
+			FailedReplicas: 1,
+			Pods:           []string{"app-1", "app-2"},
+			Conditions: []tritonappsv1alpha1.BatchCondition{
+				{
+					Batch:          1,
+					BatchSize:      2,
+					Phase:          tritonappsv1alpha1.BatchSmoking,
+					StartedAt:      startedAt,
+					FailedReplicas: 1,
+					Pods: []tritonappsv1alpha1.PodInfo{
+						{Name: "app-1", Phase: setting.PodFailed},
+						{Name: "app-2", Phase: setting.PodReady},
+					},
+				},
+			},
+		},
+	}
+
+	cl := fake.NewFakeClientWithScheme(scheme, deploy,
+		newPod("app-1", false, 3, &deleted),
+		newPod("app-2", true, 0, nil),
+		newPod("app-3", false, 0, nil),
+	)
+	r := &CloneSetReconciler{Client: cl, Scheme: scheme, logger: logrus.NewEntry(logrus.New())}
+
+	if err := r.populatePods(context.TODO(), "default", ls, deploy); err != nil {
+		t.Fatal(err)
+	}
+
+	got := &tritonappsv1alpha1.DeployFlow{}
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "app-update"}, got); err != nil {
+		t.Fatal(err)
+	}
+	c := got.Status.Conditions[0]
+	if c.FailedReplicas != 0 || got.Status.FailedReplicas != 0 {
+		t.Errorf("expected no failed pods, got %d in batch and %d in deploy", c.FailedReplicas, got.Status.FailedReplicas)
+	}
+	if len(c.Pods) != 2 || c.Pods[0].Name != "app-2" || c.Pods[1].Name != "app-3" {
+		t.Errorf("expected pods app-2 and app-3 in batch, got %v", c.Pods)
+	}
+}
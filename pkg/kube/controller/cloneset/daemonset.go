@@ -0,0 +1,48 @@
+package cloneset
+
+import (
+	"context"
+
+	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/triton-io/triton/pkg/log"
+)
+
+// AddDaemonSet creates a controller populating the pods of Advanced DaemonSets into batches, the same way as CloneSets.
+func AddDaemonSet(mgr manager.Manager) error {
+	r := newCloneSetReconciler(mgr)
+	r.logger = log.WithField("controller", "DaemonSet")
+	r.reconcileFunc = r.doReconcileDaemonSet
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&kruiseappsv1alpha1.DaemonSet{}).
+		Owns(&corev1.Pod{}).
+		Complete(r)
+
+	if err != nil {
+		return err
+	}
+
+	log.Info("DaemonSet Controller created")
+
+	return nil
+}
+
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=daemonsets/status,verbs=get
+
+func (r *CloneSetReconciler) doReconcileDaemonSet(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.logger.WithField("daemonSet", req.NamespacedName)
+
+	ds := &kruiseappsv1alpha1.DaemonSet{}
+	if err := r.Get(ctx, req.NamespacedName, ds); err != nil {
+		logger.WithError(err).Error("unable to fetch DaemonSet")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	return r.reconcileWorkload(ctx, ds, map[string]string{appsv1.ControllerRevisionHashLabelKey: ds.Status.DaemonSetHash})
+}
@@ -0,0 +1,59 @@
+package cloneset
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/triton-io/triton/pkg/kube/fetcher"
+	"github.com/triton-io/triton/pkg/log"
+)
+
+// AddDeployment creates a controller populating the pods of the new ReplicaSets of Deployments into batches, the same way as CloneSets.
+func AddDeployment(mgr manager.Manager) error {
+	r := newCloneSetReconciler(mgr)
+	r.logger = log.WithField("controller", "Deployment")
+	r.reconcileFunc = r.doReconcileDeployment
+
+	// pods are owned by the ReplicaSets, the Deployment is reconciled when the status of them is changed.
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1.Deployment{}).
+		Owns(&appsv1.ReplicaSet{}).
+		Complete(r)
+
+	if err != nil {
+		return err
+	}
+
+	log.Info("Deployment Controller created")
+
+	return nil
+}
+
+// +kubebuilder:rbac:groups=apps,resources=deployments/status,verbs=get
+// +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch
+
+func (r *CloneSetReconciler) doReconcileDeployment(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.logger.WithField("deployment", req.NamespacedName)
+
+	d := &appsv1.Deployment{}
+	if err := r.Get(ctx, req.NamespacedName, d); err != nil {
+		logger.WithError(err).Error("unable to fetch Deployment")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	rs, err := fetcher.GetNewReplicaSetInCache(d, r.Client)
+	if err != nil {
+		logger.WithError(err).Error("unable to fetch new ReplicaSet")
+		return ctrl.Result{}, err
+	}
+	// the new ReplicaSet is not created until the Deployment is resumed.
+	if rs == nil {
+		return ctrl.Result{}, nil
+	}
+
+	return r.reconcileWorkload(ctx, d, map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: rs.Labels[appsv1.DefaultDeploymentUniqueLabelKey]})
+}
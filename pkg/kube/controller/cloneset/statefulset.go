@@ -0,0 +1,48 @@
+package cloneset
+
+import (
+	"context"
+
+	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/triton-io/triton/pkg/log"
+)
+
+// AddStatefulSet creates a controller populating the pods of Advanced StatefulSets into batches, the same way as CloneSets.
+func AddStatefulSet(mgr manager.Manager) error {
+	r := newCloneSetReconciler(mgr)
+	r.logger = log.WithField("controller", "StatefulSet")
+	r.reconcileFunc = r.doReconcileStatefulSet
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&kruiseappsv1alpha1.StatefulSet{}).
+		Owns(&corev1.Pod{}).
+		Complete(r)
+
+	if err != nil {
+		return err
+	}
+
+	log.Info("StatefulSet Controller created")
+
+	return nil
+}
+
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=statefulsets/status,verbs=get
+
+func (r *CloneSetReconciler) doReconcileStatefulSet(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.logger.WithField("statefulSet", req.NamespacedName)
+
+	sts := &kruiseappsv1alpha1.StatefulSet{}
+	if err := r.Get(ctx, req.NamespacedName, sts); err != nil {
+		logger.WithError(err).Error("unable to fetch StatefulSet")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	return r.reconcileWorkload(ctx, sts, map[string]string{appsv1.ControllerRevisionHashLabelKey: sts.Status.UpdateRevision})
+}
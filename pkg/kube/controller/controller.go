@@ -42,6 +42,12 @@ func init() {
 	controllerAddFuncs = append(controllerAddFuncs, deployflow.Add)
 	// 将 cloneset 控制器的 Add 方法注册到控制器列表
 	controllerAddFuncs = append(controllerAddFuncs, cloneset.Add)
+	// 将 Advanced StatefulSet 控制器的 Add 方法注册到控制器列表
+	controllerAddFuncs = append(controllerAddFuncs, cloneset.AddStatefulSet)
+	// 将 Deployment 控制器的 Add 方法注册到控制器列表
+	controllerAddFuncs = append(controllerAddFuncs, cloneset.AddDeployment)
+	// 将 Advanced DaemonSet 控制器的 Add 方法注册到控制器列表
+	controllerAddFuncs = append(controllerAddFuncs, cloneset.AddDaemonSet)
 }
 
 func SetupWithManager(m manager.Manager) error {
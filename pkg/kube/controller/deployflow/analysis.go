@@ -0,0 +1,156 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployflow
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	"github.com/triton-io/triton/pkg/analysis"
+	terrors "github.com/triton-io/triton/pkg/errors"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	flag.StringVar(&defaultAnalysisAddress, "analysis-address", "",
+		"Default address of the Prometheus-compatible server used by .spec.updateStrategy.analysis of DeployFlows.")
+}
+
+var defaultAnalysisAddress string
+
+const (
+	defaultAnalysisInterval          = 30 * time.Second
+	defaultAnalysisInconclusiveLimit = 5
+)
+
+// analyze evaluates the analysis of current batch on an interval, it returns true once the analysis is successful.
+// The analysis restarts when the batch moves to another phase.
+func (r *DeployFlowReconciler) analyze(idl *internaldeploy.Deploy) (bool, error) {
+	logger := r.logger.WithField("deploy", idl)
+
+	a := idl.BatchAnalysis()
+	if a == nil {
+		return true, nil
+	}
+
+	phase := idl.CurrentBatchPhase()
+	status := idl.CurrentBatchAnalysis()
+	if status == nil || status.Stage != phase {
+		status = &tritonappsv1alpha1.AnalysisStatus{Stage: phase, Phase: tritonappsv1alpha1.AnalysisRunning}
+	}
+	switch status.Phase {
+	case tritonappsv1alpha1.AnalysisSuccessful:
+		return true, nil
+	case tritonappsv1alpha1.AnalysisFailed:
+		return false, nil
+	}
+
+	interval := defaultAnalysisInterval
+	if a.IntervalSeconds > 0 {
+		interval = time.Duration(a.IntervalSeconds) * time.Second
+	}
+	if wait := interval - time.Since(status.LastRunAt.Time); !status.LastRunAt.IsZero() && wait > 0 {
+		return false, terrors.NewAnalysisInProgressError(wait)
+	}
+
+	addr := a.Address
+	if addr == "" {
+		addr = defaultAnalysisAddress
+	}
+	if addr == "" {
+		return false, errors.New("address of analysis is not set, please specify it by .spec.updateStrategy.analysis.address or --analysis-address")
+	}
+
+	successfulRuns := a.SuccessfulRuns
+	if successfulRuns <= 0 {
+		successfulRuns = 1
+	}
+	inconclusiveLimit := a.InconclusiveLimit
+	if inconclusiveLimit <= 0 {
+		inconclusiveLimit = defaultAnalysisInconclusiveLimit
+	}
+
+	logger.Infof("Running analysis of batch %d in phase %s", idl.CurrentBatchNumber(), phase)
+	result, msg, err := analysis.Evaluate(analysis.NewPrometheus(addr), a.Metrics)
+	status.Runs++
+	status.LastRunAt = metav1.Now()
+	status.Message = msg
+	switch {
+	case err != nil:
+		logger.WithError(err).Warn("Failed to run analysis, try again later")
+		status.SuccessfulRuns = 0
+		status.InconclusiveRuns++
+		status.Message = err.Error()
+	case result == analysis.Successful:
+		status.SuccessfulRuns++
+		status.InconclusiveRuns = 0
+		if status.SuccessfulRuns >= successfulRuns {
+			status.Phase = tritonappsv1alpha1.AnalysisSuccessful
+		}
+	case result == analysis.Failed:
+		status.Phase = tritonappsv1alpha1.AnalysisFailed
+		status.ObservedGeneration = idl.Generation
+	default:
+		status.SuccessfulRuns = 0
+		status.InconclusiveRuns++
+	}
+	// the analysis can not run forever, it fails once too many runs are errored or inconclusive in a row.
+	if status.InconclusiveRuns > inconclusiveLimit {
+		status.Phase = tritonappsv1alpha1.AnalysisFailed
+		status.ObservedGeneration = idl.Generation
+		status.Message = fmt.Sprintf("%d consecutive runs are errored or inconclusive, last: %s", status.InconclusiveRuns, status.Message)
+	}
+	idl.SetCurrentBatchAnalysis(status)
+
+	switch status.Phase {
+	case tritonappsv1alpha1.AnalysisSuccessful:
+		logger.Infof("Analysis is successful: %s", status.Message)
+		return true, nil
+	case tritonappsv1alpha1.AnalysisFailed:
+		return false, r.failAnalysis(idl, a, status)
+	}
+
+	return false, terrors.NewAnalysisInProgressError(interval)
+}
+
+// failAnalysis pauses the deploy or fails current batch according to the failure policy.
+func (r *DeployFlowReconciler) failAnalysis(idl *internaldeploy.Deploy, a *tritonappsv1alpha1.Analysis, status *tritonappsv1alpha1.AnalysisStatus) error {
+	logger := r.logger.WithField("deploy", idl)
+
+	logger.Warnf("Analysis failed: %s", status.Message)
+	r.recorder.Event(idl.Unwrap(), corev1.EventTypeWarning, eventReasonAnalysis, status.Message)
+
+	if a.OnFailure == tritonappsv1alpha1.AnalysisFail {
+		logger.Info("Current batch failed due to analysis failure")
+		return r.failCurrentBatch(idl)
+	}
+
+	if err := r.pauseWorkload(idl); err != nil {
+		logger.WithError(err).Error("Failed to pause workload")
+		return err
+	}
+
+	logger.Info("Paused deploy due to analysis failure")
+	idl.MarkAsPaused()
+
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployflow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	terrors "github.com/triton-io/triton/pkg/errors"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	"github.com/triton-io/triton/pkg/setting"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// TestAnalyzeFailsAfterInconclusiveLimit checks that an analysis whose runs keep erroring or being inconclusive
+// pauses the deploy once more than 2 runs in a row are not conclusive.
+func TestAnalyzeFailsAfterInconclusiveLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("query") == "error_rate" {
+			_, _ = fmt.Fprint(w, `{"status":"success","data":{"resultType":"scalar","result":[1435781451.781,"0.02"]}}`)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "errored", query: "unavailable"},
+		{name: "inconclusive", query: "error_rate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var replicas int32 = 10
+			deploy := &tritonappsv1alpha1.DeployFlow{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app-update", UID: "uid"},
+				Spec: tritonappsv1alpha1.DeployFlowSpec{
+					Action:      setting.Update,
+					Application: &tritonappsv1alpha1.ApplicationSpec{CloneSetName: "app", Replicas: &replicas},
+					UpdateStrategy: &tritonappsv1alpha1.DeployUpdateStrategy{
+						Analysis: &tritonappsv1alpha1.Analysis{
+							Address:           server.URL,
+							InconclusiveLimit: 2,
+							Metrics: []tritonappsv1alpha1.AnalysisMetric{
+								{Name: "error_rate", Query: tt.query, SuccessCondition: "< 0.01", FailureCondition: ">= 0.05"},
+							},
+						},
+					},
+				},
+				Status: tritonappsv1alpha1.DeployFlowStatus{
+					ReplicasToProcess: replicas,
+					Batches:           2,
+					Conditions: []tritonappsv1alpha1.BatchCondition{
+						{Batch: 1, BatchSize: 5, Phase: tritonappsv1alpha1.BatchBaking},
+					},
+				},
+			}
+			cs := &kruiseappsv1alpha1.CloneSet{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+				Spec:       kruiseappsv1alpha1.CloneSetSpec{Replicas: &replicas},
+			}
+			scheme := newTestScheme()
+			_ = controllerutil.SetControllerReference(deploy, cs, scheme)
+			r := newTestReconciler(scheme, cs)
+			idl := internaldeploy.FromDeploy(deploy)
+
+			for i := 1; i <= 2; i++ {
+				if _, err := r.analyze(idl); err == nil || err.Error() != terrors.AnalysisInProgress {
+					t.Fatalf("expected run %d to be in progress, got %v", i, err)
+				}
+				// run again without waiting for the interval.
+				idl.CurrentBatchAnalysis().LastRunAt = metav1.Time{}
+			}
+			if ok, err := r.analyze(idl); ok || err != nil {
+				t.Fatalf("expected run 3 to fail the analysis, got %t and %v", ok, err)
+			}
+
+			if a := idl.CurrentBatchAnalysis(); a.Phase != tritonappsv1alpha1.AnalysisFailed || a.InconclusiveRuns != 3 {
+				t.Errorf("expected the analysis to fail after 3 inconclusive runs, got %s after %d", a.Phase, a.InconclusiveRuns)
+			}
+			got := &kruiseappsv1alpha1.CloneSet{}
+			if err := r.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "app"}, got); err != nil {
+				t.Fatal(err)
+			}
+			if !idl.Paused() || !got.Spec.UpdateStrategy.Paused {
+				t.Errorf("expected the deploy and the CloneSet paused, got %t and %t", idl.Paused(), got.Spec.UpdateStrategy.Paused)
+			}
+		})
+	}
+}
@@ -0,0 +1,382 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	terrors "github.com/triton-io/triton/pkg/errors"
+	"github.com/triton-io/triton/pkg/kube/fetcher"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	internalpod "github.com/triton-io/triton/pkg/kube/types/pod"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// startBlueGreen scales out the CloneSet with the partition kept at the number of old pods,
+// so that a full set of new pods is created next to the old ones.
+// If a Service is given, its selector is pinned to the revision of the old pods first.
+func (r *DeployFlowReconciler) startBlueGreen(idl *internaldeploy.Deploy) error {
+	logger := r.logger.WithField("deploy", idl)
+
+	cs, found, err := fetcher.GetCloneSetInCacheOwnedByDeploy(idl.Unwrap(), r.Client)
+	if err != nil || !found {
+		logger.WithError(err).Error("unable to fetch CloneSet")
+		return fmt.Errorf("unable to fetch CloneSet: %w", err)
+	}
+
+	pods, err := r.listPods(idl)
+	if err != nil {
+		return err
+	}
+
+	revisions := sets.NewString()
+	oldPods := make([]tritonappsv1alpha1.PodInfo, 0, len(pods))
+	for _, p := range pods {
+		revision := p.Labels[appsv1.ControllerRevisionHashLabelKey]
+		if revision == cs.Status.UpdateRevision {
+			continue
+		}
+		revisions.Insert(revision)
+		oldPods = append(oldPods, podInfo(p))
+	}
+
+	if len(oldPods) == 0 {
+		logger.Info("All pods are of the update revision, nothing to switch, process the deploy in batches")
+		idl.StartBatch()
+		return nil
+	}
+
+	bg := idl.BlueGreen()
+	if bg.Service != "" && revisions.Len() > 1 {
+		msg := fmt.Sprintf("Old pods are of revisions %s, Service %s can not be pinned to one of them",
+			strings.Join(revisions.List(), ", "), bg.Service)
+		logger.Warn(msg)
+		r.recorder.Event(idl.Unwrap(), corev1.EventTypeWarning, eventReasonBlueGreen, msg)
+		idl.MarkAsFailed()
+		return nil
+	}
+	stable := revisions.List()[0]
+
+	if bg.Service != "" {
+		logger.Infof("Pinning Service %s to revision %s", bg.Service, stable)
+		if err := r.pinService(idl, stable); err != nil {
+			logger.WithError(err).Errorf("Failed to update Service %s", bg.Service)
+			return err
+		}
+	}
+
+	replicas := int(*idl.Spec.Application.Replicas)
+	patchBytes := []byte(fmt.Sprintf(`{"spec":{"replicas":%d,"updateStrategy":{"partition":%d}}}`, len(oldPods)+replicas, len(oldPods)))
+	if err := PatchWorkload(idl.Unwrap(), patchBytes, r.Client); err != nil {
+		return err
+	}
+
+	logger.Infof("Blue/green deploy is started, creating %d pods of revision %s", replicas, cs.Status.UpdateRevision)
+	idl.StartBlueGreen(stable, cs.Status.UpdateRevision, oldPods)
+	r.recorder.Event(idl.Unwrap(), corev1.EventTypeNormal, eventReasonBlueGreen,
+		fmt.Sprintf("Creating %d pods of revision %s next to %d pods of revision %s", replicas, cs.Status.UpdateRevision, len(oldPods), stable))
+
+	return nil
+}
+
+// processBlueGreenDeploy moves a blue/green deploy through:
+// 1. ScalingUp: wait for all new pods to be ContainersReady.
+// 2. Preview: wait for the deploy to be promoted, traffic still goes to the old pods.
+// 3. Promoted: traffic is switched to the new pods at once, the old pods are kept for the scale down delay.
+// 4. ScalingDown: the old pods are removed, the deploy is done once the CloneSet has the new pods only.
+// The deploy can be switched back before ScalingDown, traffic goes back to the old pods and the new pods are removed.
+func (r *DeployFlowReconciler) processBlueGreenDeploy(idl *internaldeploy.Deploy) error {
+	logger := r.logger.WithField("deploy", idl)
+
+	if err := r.populateReplicasStatus(idl); err != nil {
+		return err
+	}
+
+	cs, found, err := fetcher.GetCloneSetInCacheOwnedByDeploy(idl.Unwrap(), r.Client)
+	if err != nil || !found {
+		logger.WithError(err).Error("unable to fetch CloneSet")
+		return fmt.Errorf("unable to fetch CloneSet: %w", err)
+	}
+
+	if cs.GetGeneration() != cs.Status.ObservedGeneration {
+		logger.Info("CloneSet status is not updated yet, checking again")
+		return terrors.NewBlueGreenInProgressError(time.Second)
+	}
+
+	bg := idl.Status.BlueGreen
+	switch bg.Phase {
+	case tritonappsv1alpha1.BlueGreenScalingUp:
+		if idl.ShouldSwitchBack() {
+			return r.switchBack(idl, cs)
+		}
+		return r.processScalingUpBlueGreen(idl, cs)
+	case tritonappsv1alpha1.BlueGreenPreview:
+		if idl.ShouldSwitchBack() {
+			return r.switchBack(idl, cs)
+		}
+		if idl.ShouldPromote() {
+			return r.promote(idl)
+		}
+	case tritonappsv1alpha1.BlueGreenPromoted:
+		if idl.ShouldSwitchBack() {
+			return r.switchBack(idl, cs)
+		}
+		if remaining := idl.ScaleDownRemaining(); remaining > 0 {
+			logger.Infof("Old pods are kept for %s to switch back", remaining.Round(time.Second))
+			return terrors.NewBlueGreenInProgressError(remaining)
+		}
+		return r.scaleDownOldPods(idl, cs)
+	case tritonappsv1alpha1.BlueGreenScalingDown:
+		replicas := *idl.Spec.Application.Replicas
+		if cs.Status.Replicas != replicas || cs.Status.UpdatedReplicas != replicas {
+			logger.Info("Old pods are not removed yet, checking again")
+			return terrors.NewBlueGreenInProgressError(time.Second)
+		}
+		if err := r.unpinService(idl); err != nil {
+			return err
+		}
+
+		logger.Info("Old pods are removed, the deploy is done")
+		idl.MarkAsSuccess()
+		r.recorder.Event(idl.Unwrap(), corev1.EventTypeNormal, eventReasonDeployed, eventMessageDeployed)
+	case tritonappsv1alpha1.BlueGreenSwitchedBack:
+		if cs.Status.Replicas != int32(len(bg.OldPods)) || cs.Status.UpdatedReplicas != 0 {
+			logger.Info("New pods are not removed yet, checking again")
+			return terrors.NewBlueGreenInProgressError(time.Second)
+		}
+		if err := r.unpinService(idl); err != nil {
+			return err
+		}
+
+		logger.Info("New pods are removed, the deploy is canceled")
+		idl.MarkAsCanceled()
+	}
+
+	return nil
+}
+
+func (r *DeployFlowReconciler) processScalingUpBlueGreen(idl *internaldeploy.Deploy, cs *kruiseappsv1alpha1.CloneSet) error {
+	logger := r.logger.WithField("deploy", idl)
+
+	pods, err := r.listPods(idl)
+	if err != nil {
+		return err
+	}
+
+	newPods := make([]tritonappsv1alpha1.PodInfo, 0, len(pods))
+	ready := true
+	for _, p := range pods {
+		if p.Labels[appsv1.ControllerRevisionHashLabelKey] != cs.Status.UpdateRevision {
+			continue
+		}
+
+		ip := internalpod.FromPod(p)
+		if ip.Failed() {
+			logger.Warnf("New pod %s failed, it needs to be fixed or the deploy switched back", p.Name)
+		}
+		ready = ready && ip.ContainersReady()
+		newPods = append(newPods, podInfo(p))
+	}
+
+	if len(newPods) < int(*idl.Spec.Application.Replicas) || !ready {
+		logger.Info("New pods are not ready yet, checking again")
+		return terrors.NewBlueGreenInProgressError(time.Second)
+	}
+
+	// the Service selects the old pods only, new pods can be Ready without serving traffic.
+	if idl.BlueGreen().Service != "" {
+		for _, p := range newPods {
+			if err := internalpod.SetPodReadinessGate(idl.Namespace, p.Name, r.Client); err != nil {
+				logger.WithError(err).Errorf("Failed to update pod %s", p.Name)
+				return err
+			}
+		}
+	}
+
+	logger.Infof("New pods %s are ready, waiting for promotion", strings.Join(podNames(newPods), ", "))
+	idl.PreviewBlueGreen(newPods)
+
+	return nil
+}
+
+// promote switches traffic from the old pods to the new ones.
+func (r *DeployFlowReconciler) promote(idl *internaldeploy.Deploy) error {
+	bg := idl.Status.BlueGreen
+	if err := r.switchTraffic(idl, bg.UpdateRevision, bg.NewPods, bg.OldPods); err != nil {
+		return err
+	}
+
+	r.logger.WithField("deploy", idl).Infof("Deploy promoted, traffic is switched to revision %s", bg.UpdateRevision)
+	idl.PromoteBlueGreen()
+	r.recorder.Event(idl.Unwrap(), corev1.EventTypeNormal, eventReasonBlueGreen,
+		fmt.Sprintf("Traffic is switched to revision %s, old pods are removed after %s", bg.UpdateRevision, idl.ScaleDownDelay()))
+
+	return nil
+}
+
+// switchBack switches traffic back to the old pods if the deploy is promoted, and removes the new pods.
+func (r *DeployFlowReconciler) switchBack(idl *internaldeploy.Deploy, cs *kruiseappsv1alpha1.CloneSet) error {
+	logger := r.logger.WithField("deploy", idl)
+	bg := idl.Status.BlueGreen
+
+	newPods, err := r.getUpdatedPodsForDeletion(idl, cs)
+	if err != nil {
+		return err
+	}
+
+	if bg.ActiveRevision == bg.UpdateRevision {
+		if err := r.switchTraffic(idl, bg.StableRevision, bg.OldPods, newPods); err != nil {
+			return err
+		}
+		if err := r.removeDisabledPods(idl, newPods); err != nil {
+			return err
+		}
+	}
+
+	patchBytes, err := withPodsToDelete(
+		[]byte(fmt.Sprintf(`{"spec":{"replicas":%d,"updateStrategy":{"partition":%d}}}`, len(bg.OldPods), len(bg.OldPods))),
+		podNames(newPods))
+	if err != nil {
+		return err
+	}
+	if err := PatchWorkload(idl.Unwrap(), patchBytes, r.Client); err != nil {
+		return err
+	}
+
+	logger.Infof("Deploy switched back, traffic is switched to revision %s", bg.StableRevision)
+	idl.SwitchBackBlueGreen()
+	r.recorder.Event(idl.Unwrap(), corev1.EventTypeNormal, eventReasonBlueGreen,
+		fmt.Sprintf("Traffic is switched back to revision %s, removing %d new pods", bg.StableRevision, len(newPods)))
+
+	return nil
+}
+
+// scaleDownOldPods removes the old pods once the scale down delay is reached.
+func (r *DeployFlowReconciler) scaleDownOldPods(idl *internaldeploy.Deploy, cs *kruiseappsv1alpha1.CloneSet) error {
+	logger := r.logger.WithField("deploy", idl)
+
+	pods, err := r.listPods(idl)
+	if err != nil {
+		return err
+	}
+
+	oldPods := make([]tritonappsv1alpha1.PodInfo, 0, len(pods))
+	for _, p := range pods {
+		if p.Labels[appsv1.ControllerRevisionHashLabelKey] != cs.Status.UpdateRevision {
+			oldPods = append(oldPods, podInfo(p))
+		}
+	}
+
+	if err := r.removeDisabledPods(idl, oldPods); err != nil {
+		return err
+	}
+
+	patchBytes, err := withPodsToDelete(
+		[]byte(fmt.Sprintf(`{"spec":{"replicas":%d,"updateStrategy":{"partition":0}}}`, *idl.Spec.Application.Replicas)),
+		podNames(oldPods))
+	if err != nil {
+		return err
+	}
+	if err := PatchWorkload(idl.Unwrap(), patchBytes, r.Client); err != nil {
+		return err
+	}
+
+	logger.Infof("Removing old pods %s", strings.Join(podNames(oldPods), ", "))
+	idl.ScaleDownBlueGreen()
+
+	return nil
+}
+
+// switchTraffic sends traffic to pods of the revision in one step. The selector of the Service is flipped if any,
+// otherwise the pods in are pulled in and the pods out are pulled out by the traffic provider.
+func (r *DeployFlowReconciler) switchTraffic(idl *internaldeploy.Deploy, revision string, in, out []tritonappsv1alpha1.PodInfo) error {
+	logger := r.logger.WithField("deploy", idl)
+
+	if service := idl.BlueGreen().Service; service != "" {
+		logger.Infof("Pinning Service %s to revision %s", service, revision)
+		return r.pinService(idl, revision)
+	}
+
+	for _, p := range in {
+		if err := internalpod.SetPodReadinessGate(idl.Namespace, p.Name, r.Client); client.IgnoreNotFound(err) != nil {
+			logger.WithError(err).Errorf("Failed to update pod %s", p.Name)
+			return err
+		}
+	}
+	logger.Infof("Pulling in pods %s", strings.Join(podNames(in), ", "))
+	if err := r.pullInPods(idl, in); err != nil {
+		logger.WithError(err).Error("Failed to pull in pods")
+		return err
+	}
+
+	if err := r.deregisterPods(idl, out); err != nil {
+		return err
+	}
+	// the readiness provider does nothing in Deregister, pull the pods out of the Services here.
+	for _, p := range out {
+		if err := internalpod.UnsetPodReadinessGate(idl.Namespace, p.Name, r.Client); client.IgnoreNotFound(err) != nil {
+			logger.WithError(err).Errorf("Failed to update pod %s", p.Name)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pinService restricts the selector of the blue/green Service to pods of the revision.
+func (r *DeployFlowReconciler) pinService(idl *internaldeploy.Deploy, revision string) error {
+	return r.patchServiceSelector(idl, fmt.Sprintf("%q", revision))
+}
+
+// unpinService removes the revision from the selector of the blue/green Service.
+func (r *DeployFlowReconciler) unpinService(idl *internaldeploy.Deploy) error {
+	if idl.BlueGreen().Service == "" {
+		return nil
+	}
+	return r.patchServiceSelector(idl, "null")
+}
+
+func (r *DeployFlowReconciler) patchServiceSelector(idl *internaldeploy.Deploy, value string) error {
+	patchBytes := []byte(fmt.Sprintf(`{"spec":{"selector":{%q:%s}}}`, appsv1.ControllerRevisionHashLabelKey, value))
+
+	return r.Patch(context.TODO(), &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: idl.Namespace,
+			Name:      idl.BlueGreen().Service,
+		},
+	}, client.RawPatch(types.MergePatchType, patchBytes))
+}
+
+func podInfo(p *corev1.Pod) tritonappsv1alpha1.PodInfo {
+	ip := internalpod.FromPod(p)
+	return tritonappsv1alpha1.PodInfo{
+		Name:  ip.Name,
+		IP:    ip.GetPodIP(),
+		Port:  ip.GetAppPort(),
+		Phase: string(ip.GetPhase()),
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployflow
+
+import (
+	"fmt"
+	"time"
+
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	terrors "github.com/triton-io/triton/pkg/errors"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// processWithDeadline calls process unless current batch or the deploy exceeds its progress deadline,
+// the deploy is requeued at the deadline if current batch stays in the same phase.
+func (r *DeployFlowReconciler) processWithDeadline(idl *internaldeploy.Deploy, process func(*internaldeploy.Deploy) error) error {
+	return r.processBefore(idl, progressDeadline, process)
+}
+
+// processBefore calls process unless the deadline returned by deadlineOf is exceeded,
+// the deploy is requeued at the deadline if current batch stays in the same phase.
+func (r *DeployFlowReconciler) processBefore(idl *internaldeploy.Deploy, deadlineOf func(*internaldeploy.Deploy) (time.Time, string),
+	process func(*internaldeploy.Deploy) error) error {
+	deadline, reason := deadlineOf(idl)
+	if !deadline.IsZero() && time.Now().After(deadline) {
+		return r.failOnDeadline(idl, reason)
+	}
+
+	phase := idl.CurrentBatchPhase()
+	if err := process(idl); err != nil || deadline.IsZero() || idl.CurrentBatchPhase() != phase {
+		return err
+	}
+
+	// pods stuck in ImagePullBackOff never trigger a reconcile, wake up at the deadline.
+	return terrors.NewProgressDeadlineNotReachedError(time.Until(deadline) + time.Second)
+}
+
+// progressDeadline returns the earliest deadline of current batch and the deploy, and the reason if it is exceeded.
+// A zero time means there is no deadline.
+func progressDeadline(idl *internaldeploy.Deploy) (time.Time, string) {
+	deadline, reason := batchDeadline(idl)
+	if d, r := deployDeadline(idl); deadline.IsZero() || !d.IsZero() && d.Before(deadline) {
+		deadline, reason = d, r
+	}
+
+	return deadline, reason
+}
+
+// batchDeadline returns the deadline of current batch and the reason if it is exceeded.
+func batchDeadline(idl *internaldeploy.Deploy) (time.Time, string) {
+	c := idl.CurrentBatchInfo()
+	t := idl.BatchTimeout()
+	if c == nil || t <= 0 {
+		return time.Time{}, ""
+	}
+
+	// a batch starts baking once its new pods are pulled in.
+	start := c.StartedAt.Time
+	if c.Phase == tritonappsv1alpha1.BatchBaking {
+		start = c.PulledInAt.Time
+	}
+	// and the time of shadowing is not counted in smoking.
+	if c.Phase == tritonappsv1alpha1.BatchShadowing {
+		start = c.ShadowedAt.Time
+	}
+	if c.ResumedAt.After(start) {
+		start = c.ResumedAt.Time
+	}
+
+	return start.Add(t), fmt.Sprintf("Batch %d stays in %s for more than %s", c.Batch, c.Phase, t)
+}
+
+// deployDeadline returns the deadline of the deploy and the reason if it is exceeded, it applies to all phases of a batch.
+func deployDeadline(idl *internaldeploy.Deploy) (time.Time, string) {
+	c := idl.CurrentBatchInfo()
+	t := idl.Timeout()
+	if c == nil || t <= 0 {
+		return time.Time{}, ""
+	}
+
+	start := idl.Status.StartedAt.Time
+	if c.ResumedAt.After(start) {
+		start = c.ResumedAt.Time
+	}
+
+	return start.Add(t), fmt.Sprintf("Deploy is not finished in %s", t)
+}
+
+// failOnDeadline marks current batch as SmokeFailed or BakeFailed and applies the failure policy.
+func (r *DeployFlowReconciler) failOnDeadline(idl *internaldeploy.Deploy, reason string) error {
+	logger := r.logger.WithField("deploy", idl)
+
+	logger.Warnf("%s, failure policy is %s", reason, idl.FailurePolicy())
+	if idl.FailurePolicy() == tritonappsv1alpha1.FailurePause {
+		if err := r.pauseWorkload(idl); err != nil {
+			logger.WithError(err).Error("Failed to pause workload")
+			return err
+		}
+		idl.MarkAsPaused()
+	} else if err := r.failCurrentBatch(idl); err != nil {
+		return err
+	}
+
+	idl.MarkCurrentBatchAsTimedOut()
+	r.recorder.Event(idl.Unwrap(), corev1.EventTypeWarning, eventReasonTimeout, reason)
+
+	return nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployflow
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	"github.com/triton-io/triton/pkg/kube/fetcher"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	"github.com/triton-io/triton/pkg/setting"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+// keys of the ConfigMap triton-defaults.
+const (
+	maxSurgeKey         = "maxSurge"
+	maxUnavailableKey   = "maxUnavailable"
+	podUpdatePolicyKey  = "podUpdatePolicy"
+	imagePullSecretsKey = "imagePullSecrets"
+	readinessGatesKey   = "readinessGates"
+)
+
+// getWorkloadStrategy returns the workload strategy of the deploy, fields not set are taken from the ConfigMap
+// triton-defaults in the namespace of the deploy. Fields still not set are left to the built-in defaults.
+func (r *DeployFlowReconciler) getWorkloadStrategy(idl *internaldeploy.Deploy) (*tritonappsv1alpha1.WorkloadStrategy, error) {
+	ws := &tritonappsv1alpha1.WorkloadStrategy{}
+	if s := idl.UpdateStrategy().Workload; s != nil {
+		ws = s.DeepCopy()
+	}
+
+	cm, found, err := fetcher.GetConfigMapInCache(idl.Namespace, setting.DefaultsConfigMap, r.Client)
+	if err != nil || !found {
+		return ws, err
+	}
+
+	defaults := workloadStrategyFromConfigMap(cm, r.logger.WithField("deploy", idl))
+	if ws.MaxSurge == nil {
+		ws.MaxSurge = defaults.MaxSurge
+	}
+	if ws.MaxUnavailable == nil {
+		ws.MaxUnavailable = defaults.MaxUnavailable
+	}
+	if ws.PodUpdatePolicy == "" {
+		ws.PodUpdatePolicy = defaults.PodUpdatePolicy
+	}
+	if ws.ImagePullSecrets == nil {
+		ws.ImagePullSecrets = defaults.ImagePullSecrets
+	}
+	if ws.ReadinessGates == nil {
+		ws.ReadinessGates = defaults.ReadinessGates
+	}
+
+	return ws, nil
+}
+
+// workloadStrategyFromConfigMap parses the workload strategy in the ConfigMap, invalid values are ignored.
+func workloadStrategyFromConfigMap(cm *corev1.ConfigMap, logger *logrus.Entry) *tritonappsv1alpha1.WorkloadStrategy {
+	ws := &tritonappsv1alpha1.WorkloadStrategy{}
+
+	switch p := tritonappsv1alpha1.PodUpdatePolicyType(strings.TrimSpace(cm.Data[podUpdatePolicyKey])); p {
+	case "":
+	case tritonappsv1alpha1.RecreatePodUpdatePolicy,
+		tritonappsv1alpha1.InPlaceIfPossiblePodUpdatePolicy,
+		tritonappsv1alpha1.InPlaceOnlyPodUpdatePolicy:
+		ws.PodUpdatePolicy = p
+	default:
+		logger.Warnf("Invalid %s %q in ConfigMap %s/%s, ignore it", podUpdatePolicyKey, p, cm.Namespace, cm.Name)
+	}
+	if v := strings.TrimSpace(cm.Data[maxSurgeKey]); v != "" {
+		maxSurge := intstr.Parse(v)
+		ws.MaxSurge = &maxSurge
+	}
+	if v := strings.TrimSpace(cm.Data[maxUnavailableKey]); v != "" {
+		maxUnavailable := intstr.Parse(v)
+		ws.MaxUnavailable = &maxUnavailable
+	}
+	// an empty value sets no secret, the key is not set if it is absent.
+	if v, ok := cm.Data[imagePullSecretsKey]; ok {
+		ws.ImagePullSecrets = []corev1.LocalObjectReference{}
+		for _, name := range splitList(v) {
+			ws.ImagePullSecrets = append(ws.ImagePullSecrets, corev1.LocalObjectReference{Name: name})
+		}
+	}
+	for _, c := range splitList(cm.Data[readinessGatesKey]) {
+		ws.ReadinessGates = append(ws.ReadinessGates, corev1.PodReadinessGate{ConditionType: corev1.PodConditionType(c)})
+	}
+
+	return ws
+}
+
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployflow
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	"github.com/triton-io/triton/pkg/setting"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// TestWorkloadStrategyFromConfigMap checks that the defaults in triton-defaults are parsed, and invalid values are ignored.
+func TestWorkloadStrategyFromConfigMap(t *testing.T) {
+	maxSurge, maxUnavailable := intstr.FromString("25%"), intstr.FromInt(1)
+
+	tests := []struct {
+		name     string
+		data     map[string]string
+		expected *tritonappsv1alpha1.WorkloadStrategy
+	}{
+		{
+			name:     "empty",
+			expected: &tritonappsv1alpha1.WorkloadStrategy{},
+		},
+		{
+			name: "all set",
+			data: map[string]string{
+				maxSurgeKey:         " 25% ",
+				maxUnavailableKey:   "1",
+				podUpdatePolicyKey:  "InPlaceIfPossible",
+				imagePullSecretsKey: "registry-a, ,registry-b",
+				readinessGatesKey:   "app.ready",
+			},
+			expected: &tritonappsv1alpha1.WorkloadStrategy{
+				MaxSurge:         &maxSurge,
+				MaxUnavailable:   &maxUnavailable,
+				PodUpdatePolicy:  tritonappsv1alpha1.InPlaceIfPossiblePodUpdatePolicy,
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry-a"}, {Name: "registry-b"}},
+				ReadinessGates:   []corev1.PodReadinessGate{{ConditionType: "app.ready"}},
+			},
+		},
+		{
+			name:     "recreate",
+			data:     map[string]string{podUpdatePolicyKey: "ReCreate"},
+			expected: &tritonappsv1alpha1.WorkloadStrategy{PodUpdatePolicy: tritonappsv1alpha1.RecreatePodUpdatePolicy},
+		},
+		{
+			name:     "in place only",
+			data:     map[string]string{podUpdatePolicyKey: " InPlaceOnly "},
+			expected: &tritonappsv1alpha1.WorkloadStrategy{PodUpdatePolicy: tritonappsv1alpha1.InPlaceOnlyPodUpdatePolicy},
+		},
+		{
+			name:     "invalid pod update policy",
+			data:     map[string]string{podUpdatePolicyKey: "Recreate", maxUnavailableKey: "1"},
+			expected: &tritonappsv1alpha1.WorkloadStrategy{MaxUnavailable: &maxUnavailable},
+		},
+		{
+			name:     "no image pull secrets",
+			data:     map[string]string{imagePullSecretsKey: ""},
+			expected: &tritonappsv1alpha1.WorkloadStrategy{ImagePullSecrets: []corev1.LocalObjectReference{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: setting.DefaultsConfigMap},
+				Data:       tt.data,
+			}
+			if got := workloadStrategyFromConfigMap(cm, logrus.NewEntry(logrus.New())); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -25,9 +25,8 @@ import (
 	"github.com/triton-io/triton/pkg/indexer"
 	"k8s.io/client-go/rest"
 	"reflect"
+	"sort"
 	"strings"
-	"sync"
-	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -38,6 +37,7 @@ import (
 	"github.com/triton-io/triton/pkg/kube/types/workload"
 	"github.com/triton-io/triton/pkg/services/deployflow"
 	"github.com/triton-io/triton/pkg/setting"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -137,6 +137,9 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	err := ctrl.NewControllerManagedBy(mgr).
 		For(&tritonappsv1alpha1.DeployFlow{}).
 		Owns(&kruiseappsv1alpha1.CloneSet{}).
+		Owns(&kruiseappsv1alpha1.StatefulSet{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&kruiseappsv1alpha1.DaemonSet{}).
 		//Watches(&source.Kind{Type: &kruiseappsv1alpha1.CloneSet{}}, &handler.EnqueueRequestForOwner{}, builder.WithPredicates(CloneSetStatusChangedPredicate{})).
 		Complete(r)
 
@@ -208,8 +211,8 @@ func (r *DeployFlowReconciler) doReconcile(ctx context.Context, req reconcile.Re
 		if idl.Finished() {
 			r.postDeploy(idl)
 		}
-		if err := r.DeleteCloneSetWhenActionIsScaleInZero(deploy); err != nil {
-			logger.WithError(err).Error("unable delete cloneSet which replicas is 0 when action is scale in")
+		if err := r.DeleteWorkloadWhenActionIsScaleInZero(deploy); err != nil {
+			logger.WithError(err).Error("unable delete workload which replicas is 0 when action is scale in")
 		}
 	}()
 
@@ -282,6 +285,11 @@ func (r *DeployFlowReconciler) updateDeployStatus(idl *internaldeploy.Deploy) er
 }
 
 func (r *DeployFlowReconciler) process(idl *internaldeploy.Deploy) error {
+	// a blue/green deploy is not paused, and it is switched back when canceled.
+	if idl.BlueGreenStarted() {
+		return r.processBlueGreenDeploy(idl)
+	}
+
 	if err := r.processPausedOrCanceledDeploy(idl); err != nil {
 		return err
 	}
@@ -296,11 +304,16 @@ func (r *DeployFlowReconciler) process(idl *internaldeploy.Deploy) error {
 	case tritonappsv1alpha1.Initializing:
 		return r.processInitializingDeploy(idl)
 	case tritonappsv1alpha1.BatchStarted:
+		if idl.Replan() {
+			r.logger.WithField("deploy", idl).Infof("Remaining batches are planned again, total batches is %d now", idl.Status.Batches)
+		}
 		if !idl.Paused() {
 			return r.processBatch(idl)
 		}
 	case tritonappsv1alpha1.BatchFinished:
 		return r.processBatchFinishedDeploy(idl)
+	case tritonappsv1alpha1.Reverting:
+		return r.processRevertingDeploy(idl)
 	}
 
 	return nil
@@ -317,22 +330,29 @@ func (r *DeployFlowReconciler) processNewDeploy(idl *internaldeploy.Deploy) erro
 func (r *DeployFlowReconciler) processPendingDeploy(idl *internaldeploy.Deploy) error {
 	logger := r.logger.WithField("deploy", idl)
 
-	cs, found, err := fetcher.GetCloneSetInCacheByDeploy(idl.Unwrap(), r.Client)
+	if msg := unsupportedAction(idl); msg != "" {
+		logger.Warn(msg)
+		r.recorder.Event(idl.Unwrap(), corev1.EventTypeWarning, eventReasonUnhealthy, msg)
+		idl.MarkAsFailed()
+		return nil
+	}
+
+	cs, found, err := fetcher.GetWorkloadInCacheByDeploy(idl.Unwrap(), r.Client)
 	if err != nil {
-		logger.WithError(err).Errorf("failed to fetch cloneSet %s", idl.Spec.Application.CloneSetName)
+		logger.WithError(err).Errorf("failed to fetch %s %s", idl.ApplicationType(), idl.Spec.Application.CloneSetName)
 		return err
 	} else if !found {
 		if idl.Spec.Action != setting.Create {
 			idl.MarkAsFailed()
 			return nil
 		}
-		logger.Infof("cloneSet %s does not exist, start to create it", idl.Spec.Application.CloneSetName)
-		err := r.createCloneSet(idl)
+		logger.Infof("%s %s does not exist, start to create it", idl.ApplicationType(), idl.Spec.Application.CloneSetName)
+		err := r.createWorkload(idl)
 		if err != nil {
-			logger.WithError(err).Error("failed to create cloneSet")
+			logger.WithError(err).Errorf("failed to create %s", idl.ApplicationType())
 			return err
 		}
-		logger.Infof("cloneSet created, start to process the deploy")
+		logger.Infof("%s created, start to process the deploy", idl.ApplicationType())
 	} else {
 		if idl.Spec.Action == setting.Create {
 			idl.MarkAsFailed()
@@ -360,15 +380,15 @@ func (r *DeployFlowReconciler) processPendingDeploy(idl *internaldeploy.Deploy)
 		}
 
 		if idl.RevisionChanged() {
-			logger.Infof("cloneSet %s is found, start to update it", idl.Spec.Application.CloneSetName)
-			if err := r.updateCloneSet(idl); err != nil {
-				logger.WithError(err).Error("Failed to update cloneSet")
+			logger.Infof("%s %s is found, start to update it", idl.ApplicationType(), idl.Spec.Application.CloneSetName)
+			if err := r.updateWorkload(idl); err != nil {
+				logger.WithError(err).Errorf("Failed to update %s", idl.ApplicationType())
 				return err
 			}
 		} else {
-			logger.Infof("Taking ownership of cloneSet %s", idl.Spec.Application.CloneSetName)
-			if err := r.takeOwnershipOfCloneSet(idl); err != nil {
-				logger.WithError(err).Errorf("Failed to set owner for cloneSet %s", idl.Spec.Application.CloneSetName)
+			logger.Infof("Taking ownership of %s %s", idl.ApplicationType(), idl.Spec.Application.CloneSetName)
+			if err := r.takeOwnershipOfWorkload(idl); err != nil {
+				logger.WithError(err).Errorf("Failed to set owner for %s %s", idl.ApplicationType(), idl.Spec.Application.CloneSetName)
 				return err
 			}
 		}
@@ -380,8 +400,30 @@ func (r *DeployFlowReconciler) processPendingDeploy(idl *internaldeploy.Deploy)
 	return nil
 }
 
-func lastDeployFinishedOrPaused(cs *kruiseappsv1alpha1.CloneSet, cl client.Client) (finished, paused bool, err error) {
-	deploy, found, err := fetcher.GetDeployInCacheOwningCloneSet(cs, cl)
+// unsupportedAction returns why the action can not be done on the workload, empty if it is supported.
+func unsupportedAction(idl *internaldeploy.Deploy) string {
+	if !idl.InPlace() {
+		return ""
+	}
+
+	// pods of a DaemonSet are scheduled to nodes, they can not be scaled.
+	if idl.Daemon() && !idl.RevisionChanged() {
+		return fmt.Sprintf("Action %s is not supported by %s", idl.Spec.Action, idl.ApplicationType())
+	}
+
+	// pods of an Advanced StatefulSet can not be created next to the old ones, nor be removed by name.
+	switch {
+	case idl.Spec.Action == setting.Restart:
+		return fmt.Sprintf("Action %s is not supported by %s", idl.Spec.Action, idl.ApplicationType())
+	case idl.Spec.Action == setting.ScaleIn && len(idl.NonUpdateStrategy().PodsToDelete) > 0:
+		return fmt.Sprintf("PodsToDelete is not supported by %s, pods of the highest ordinals are removed", idl.ApplicationType())
+	}
+
+	return ""
+}
+
+func lastDeployFinishedOrPaused(cs workload.Object, cl client.Client) (finished, paused bool, err error) {
+	deploy, found, err := fetcher.GetDeployInCacheOwning(cs, cl)
 	if err != nil || !found {
 		return true, false, err
 	}
@@ -390,8 +432,8 @@ func lastDeployFinishedOrPaused(cs *kruiseappsv1alpha1.CloneSet, cl client.Clien
 	return idl.Finished(), idl.Paused(), nil
 }
 
-func abortPausedDeploy(cs *kruiseappsv1alpha1.CloneSet, cl client.Client) error {
-	deploy, _, err := fetcher.GetDeployInCacheOwningCloneSet(cs, cl)
+func abortPausedDeploy(cs workload.Object, cl client.Client) error {
+	deploy, _, err := fetcher.GetDeployInCacheOwning(cs, cl)
 	if err != nil {
 		return err
 	}
@@ -413,12 +455,16 @@ func (r *DeployFlowReconciler) processPausedOrCanceledDeploy(idl *internaldeploy
 		return nil
 	}
 
-	// pause the CloneSet when deploy is canceled.
+	// pause the workload when deploy is canceled.
 	if idl.ShouldCancel() {
+		if idl.RevertOnCancel() {
+			return r.startRevert(idl)
+		}
+
 		//TODO if cancel batch is canary, rollback cloneset
-		logger.Info("Deploy canceled, pause the CloneSet")
-		if err := r.pauseCloneSet(idl); err != nil {
-			logger.WithError(err).Error("Failed to pause CloneSet")
+		logger.Info("Deploy canceled, pause the workload")
+		if err := r.pauseWorkload(idl); err != nil {
+			logger.WithError(err).Error("Failed to pause workload")
 			return err
 		}
 
@@ -436,30 +482,55 @@ func (r *DeployFlowReconciler) processPausedOrCanceledDeploy(idl *internaldeploy
 	if paused != nil && *paused != idl.Paused() {
 
 		if !*paused && idl.CurrentBatchFailed() {
-			logger.Warn("There are failure pods in current batch, you need to fix them before resuming the Deploy")
+			logger.Warn("There are failure pods in current batch, you need to fix them or retry the batch before resuming the Deploy")
+			return nil
+		}
+
+		if !*paused && idl.CurrentBatchAnalysisFailed() {
+			logger.Warn("Analysis of current batch failed, you need to update the Deploy before resuming it")
+			return nil
+		}
+
+		if !*paused && idl.CurrentBatchHookFailed() {
+			logger.Warn("Hook of current batch failed, you need to update the Deploy before resuming it")
+			return nil
+		}
+
+		if !*paused && idl.CurrentBatchTimedOut() {
+			logger.Warn("Current batch exceeded its progress deadline, you need to update the Deploy before resuming it")
 			return nil
 		}
 
-		if err := r.pauseOrResumeCloneSet(idl, *paused); err != nil {
-			logger.WithError(err).Error("Failed to pause or resume CloneSet")
+		if err := r.pauseOrResumeWorkload(idl, *paused); err != nil {
+			logger.WithError(err).Error("Failed to pause or resume workload")
 			return err
 		}
 
 		logger.Infof("Deploy paused is %t", *paused)
 		idl.SetPaused(*paused)
 
+		// run the analysis again after resumed.
+		if a := idl.CurrentBatchAnalysis(); !*paused && a != nil && a.Phase == tritonappsv1alpha1.AnalysisFailed {
+			idl.SetCurrentBatchAnalysis(nil)
+		}
+		// call the failed hooks again and count the progress deadline again after resumed.
+		if !*paused {
+			idl.ResetCurrentBatchFailedHooks()
+			idl.ResumeTimedOutBatch()
+		}
+
 	}
 
 	return nil
 }
 
 func (r *DeployFlowReconciler) ResetReplicasAfterCanceled(idl *internaldeploy.Deploy) error {
-	if idl.Spec.Action == setting.Create {
+	if idl.Spec.Action == setting.Create || idl.Daemon() {
 		return nil
 	}
 	replicas := int(*idl.Spec.Application.Replicas)
 	patchBytes := []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
-	return PatchCloneSet(idl.Unwrap(), patchBytes, r.Client)
+	return PatchWorkload(idl.Unwrap(), patchBytes, r.Client)
 }
 
 func (r *DeployFlowReconciler) processInitializingDeploy(idl *internaldeploy.Deploy) error {
@@ -467,8 +538,8 @@ func (r *DeployFlowReconciler) processInitializingDeploy(idl *internaldeploy.Dep
 
 	// do not move forward until fields like updateRevision, updatedReplicas...
 	// in .status is updated
-	if !CloneSetStatusSynced(idl.Unwrap(), r.Client) {
-		logger.Info("CloneSet status is not updated yet, skip and wait for next try.")
+	if !WorkloadStatusSynced(idl.Unwrap(), r.Client) {
+		logger.Infof("%s status is not updated yet, skip and wait for next try.", idl.ApplicationType())
 
 		return nil
 	}
@@ -477,44 +548,89 @@ func (r *DeployFlowReconciler) processInitializingDeploy(idl *internaldeploy.Dep
 		return err
 	}
 
+	if idl.BlueGreen() != nil {
+		logger.Info("Deploy initialized, start the blue/green deploy")
+		return r.startBlueGreen(idl)
+	}
+
 	logger.Info("Deploy initialized")
 	idl.StartBatch()
 
 	return nil
 }
 
-func CloneSetStatusSynced(deploy *tritonappsv1alpha1.DeployFlow, cl client.Client) bool {
-	cs, found, err := fetcher.GetCloneSetInCacheOwnedByDeploy(deploy, cl)
+func WorkloadStatusSynced(deploy *tritonappsv1alpha1.DeployFlow, cl client.Client) bool {
+	obj, found, err := fetcher.GetWorkloadInCacheOwnedByDeploy(deploy, cl)
 	if err != nil || !found {
 		return false
 	}
 
-	return cs.GetGeneration() == cs.Status.ObservedGeneration
+	return workload.Synced(obj)
 }
 
 func (r *DeployFlowReconciler) populateReplicasStatus(idl *internaldeploy.Deploy) error {
 	logger := r.logger.WithField("deploy", idl)
 
-	cs, found, err := fetcher.GetCloneSetInCacheOwnedByDeploy(idl.Unwrap(), r.Client)
+	obj, found, err := fetcher.GetWorkloadInCacheOwnedByDeploy(idl.Unwrap(), r.Client)
 	if err != nil || !found {
-		logger.WithError(err).Error("unable to fetch CloneSet")
-		return fmt.Errorf("unable to fetch CloneSet: %w", err)
+		logger.WithError(err).Errorf("unable to fetch %s", idl.ApplicationType())
+		return fmt.Errorf("unable to fetch %s: %w", idl.ApplicationType(), err)
 	}
 
-	idl.DeployFlow.Status.AvailableReplicas = cs.Status.AvailableReplicas
-	idl.DeployFlow.Status.UpdatedReadyReplicas = cs.Status.UpdatedReadyReplicas
-	idl.DeployFlow.Status.UpdatedReplicas = cs.Status.UpdatedReplicas
-	idl.DeployFlow.Status.Replicas = cs.Status.Replicas
-	idl.DeployFlow.Status.UpdateRevision = cs.Status.UpdateRevision
+	status := workload.StatusOf(obj)
+	if idl.InPlace() {
+		if status.UpdatedReadyReplicas, err = r.countUpdatedReadyPods(idl, status.UpdateRevision); err != nil {
+			return err
+		}
+	}
+	// pods of a Deployment are updated if they belong to its new ReplicaSet.
+	if d, ok := obj.(*appsv1.Deployment); ok {
+		rs, err := fetcher.GetNewReplicaSetInCache(d, r.Client)
+		if err != nil {
+			return fmt.Errorf("unable to fetch new replicaset: %w", err)
+		}
+		if rs != nil {
+			status.UpdateRevision = rs.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+			status.UpdatedReadyReplicas = rs.Status.ReadyReplicas
+		}
+	}
+
+	idl.DeployFlow.Status.AvailableReplicas = status.AvailableReplicas
+	idl.DeployFlow.Status.UpdatedReadyReplicas = status.UpdatedReadyReplicas
+	idl.DeployFlow.Status.UpdatedReplicas = status.UpdatedReplicas
+	idl.DeployFlow.Status.Replicas = status.Replicas
+	idl.DeployFlow.Status.UpdateRevision = status.UpdateRevision
 
 	return nil
 }
 
+// countUpdatedReadyPods counts the ready pods of the update revision, which is not reported by an Advanced StatefulSet.
+func (r *DeployFlowReconciler) countUpdatedReadyPods(idl *internaldeploy.Deploy, revision string) (int32, error) {
+	pods, err := r.listPods(idl)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int32
+	for _, p := range pods {
+		if p.Labels[appsv1.ControllerRevisionHashLabelKey] == revision && internalpod.FromPod(p).Ready() {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
 func (r *DeployFlowReconciler) processBatch(idl *internaldeploy.Deploy) error {
 	if err := r.populateReplicasStatus(idl); err != nil {
 		return err
 	}
 
+	// the deploy times out in any phase of current batch, ex: a pending batch waiting to move forward.
+	return r.processBefore(idl, deployDeadline, r.processCurrentBatch)
+}
+
+func (r *DeployFlowReconciler) processCurrentBatch(idl *internaldeploy.Deploy) error {
 	switch idl.CurrentBatchPhase() {
 	case tritonappsv1alpha1.BatchPending:
 		// the first pending batch should be processed no matter MoveForward is true or false
@@ -522,20 +638,36 @@ func (r *DeployFlowReconciler) processBatch(idl *internaldeploy.Deploy) error {
 			return r.processNewBatch(idl)
 		}
 	case tritonappsv1alpha1.BatchSmoking:
-		return r.processSmokingBatch(idl)
+		return r.processWithDeadline(idl, r.processSmokingBatch)
 	case tritonappsv1alpha1.BatchSmoked:
 		// move forward if:
 		// 1. it is not a canary
 		// 2. it is a canary and MoveForward is true
+		// 3. it is a canary and the analysis is successful
+		if idl.CurrentBatchIsCanary() && idl.Shadowing() != nil && idl.CurrentBatchSmoked() {
+			return r.startShadowing(idl)
+		}
 		if !idl.CurrentBatchIsCanary() || idl.MoveForward() {
 			return r.processSmokedBatch(idl)
 		}
+		if idl.Analysis() != nil {
+			return r.processAnalyzedBatch(idl, r.processSmokedBatch)
+		}
+	case tritonappsv1alpha1.BatchShadowing:
+		// the analysis of shadowing, or the one of the deploy, must be successful before the batch is pulled in.
+		return r.processWithDeadline(idl, func(idl *internaldeploy.Deploy) error {
+			return r.processAnalyzedBatch(idl, r.processShadowingBatch)
+		})
 	case tritonappsv1alpha1.BatchBaking:
 		// move forward if:
 		// 1. it is not a canary
 		// 2. it is a canary and MoveForward is true
-		if !idl.CurrentBatchIsCanary() || idl.MoveForward() {
-			return r.processBakingBatch(idl)
+		// 3. it is a canary and the analysis is successful
+		// in all cases, the analysis must be successful if any.
+		if !idl.CurrentBatchIsCanary() || idl.MoveForward() || idl.Analysis() != nil {
+			return r.processWithDeadline(idl, func(idl *internaldeploy.Deploy) error {
+				return r.processAnalyzedBatch(idl, r.processBakingBatch)
+			})
 		}
 	case tritonappsv1alpha1.BatchBaked:
 		return r.processFinishedBatch(idl)
@@ -546,6 +678,16 @@ func (r *DeployFlowReconciler) processBatch(idl *internaldeploy.Deploy) error {
 	return nil
 }
 
+// processAnalyzedBatch calls process once the analysis of current batch is successful.
+func (r *DeployFlowReconciler) processAnalyzedBatch(idl *internaldeploy.Deploy, process func(*internaldeploy.Deploy) error) error {
+	passed, err := r.analyze(idl)
+	if err != nil || !passed {
+		return err
+	}
+
+	return process(idl)
+}
+
 func (r *DeployFlowReconciler) processBatchFinishedDeploy(idl *internaldeploy.Deploy) error {
 	logger := r.logger.WithField("deploy", idl)
 
@@ -567,26 +709,22 @@ func (r *DeployFlowReconciler) processBatchFinishedDeploy(idl *internaldeploy.De
 
 	// if a deployfolw has no change and has pod not ready, patch the cloneset use scale strategy
 	if idl.NoChangedDeploy() && len(idl.GetNotContainerReadyPods()) != 0 {
-		// update cloneset to
-		pods := idl.GetNotContainerReadyPods()
-		patchBytes := []byte(fmt.Sprintf(`{"spec":{"scaleStrategy":{"podsToDelete":%v}}}`, pods))
-		err := PatchCloneSet(idl.Unwrap(), patchBytes, r.Client)
-		if err != nil {
-			logger.Errorf("patch cloneset %v failed, error is %v", idl.Unwrap().Spec.Application.CloneSetName, err)
+		if err := r.recreatePods(idl, idl.GetNotContainerReadyPods()); err != nil {
+			logger.Errorf("recreate pods of %s %v failed, error is %v", idl.ApplicationType(), idl.Unwrap().Spec.Application.CloneSetName, err)
 			return err
 		}
 
 		statusBytes := []byte(fmt.Sprintf(`{"status":{"phase":"%s"}`, tritonappsv1alpha1.Initializing))
-		_, err = deployflow.PatchDeployStatus(idl.Namespace, idl.Name, r.reader, r.Client, statusBytes)
+		_, err := deployflow.PatchDeployStatus(idl.Namespace, idl.Name, r.reader, r.Client, statusBytes)
 		if err != nil {
 			logger.Errorf("patch deployflow %v failed, error is %v", idl.Name, err)
 			return err
 		}
 	}
 
-	if idl.Status.AvailableReplicas != *idl.Spec.Application.Replicas ||
-		*idl.Spec.Application.Replicas != idl.Status.Replicas ||
-		*idl.Spec.Application.Replicas != idl.Status.UpdatedReplicas {
+	if idl.Status.AvailableReplicas != idl.DesiredReplicas() ||
+		idl.DesiredReplicas() != idl.Status.Replicas ||
+		idl.DesiredReplicas() != idl.Status.UpdatedReplicas {
 		logger.Warn("Final state mismatch, skip and retry later")
 		return fmt.Errorf("final state mismatch")
 	}
@@ -604,10 +742,32 @@ func (r *DeployFlowReconciler) processNewBatch(idl *internaldeploy.Deploy) error
 
 	logger.Info("Start to process new batch")
 
+	if passed, err := r.runHooks(idl, tritonappsv1alpha1.PreBatch, idl.PreBatchHooks()); err != nil || !passed {
+		return err
+	}
+
+	// pods of the highest ordinals are replaced in place, pull them out before they are removed.
+	// the nodes whose pods are replaced by a DaemonSet can not be told beforehand.
+	if idl.InPlace() && !idl.Daemon() && (idl.Spec.Action == setting.Update || idl.Spec.Action == setting.Rollback) {
+		if _, err := r.pullOutOldPods(idl); err != nil {
+			logger.WithError(err).Error("Failed to pull out old pods")
+			return err
+		}
+	}
+
 	startedAt := metav1.Now()
+	// pods of a DaemonSet are created with it, they are all populated into the batch.
+	if idl.Daemon() && idl.Spec.Action == setting.Create {
+		obj, found, err := fetcher.GetWorkloadInCacheOwnedByDeploy(idl.Unwrap(), r.Client)
+		if err != nil || !found {
+			logger.WithError(err).Errorf("unable to fetch %s", idl.ApplicationType())
+			return fmt.Errorf("unable to fetch %s: %w", idl.ApplicationType(), err)
+		}
+		startedAt = obj.GetCreationTimestamp()
+	}
 
-	if err := r.processCloneSet(idl); err != nil {
-		logger.WithError(err).Error("failed to process cloneSet")
+	if err := r.processWorkload(idl); err != nil {
+		logger.WithError(err).Error("failed to process workload")
 		return err
 	}
 
@@ -622,9 +782,19 @@ func (r *DeployFlowReconciler) processSmokingBatch(idl *internaldeploy.Deploy) e
 
 	logger.Info("Processing smoking batch.")
 
+	if err := r.syncSteppedDeployment(idl); err != nil {
+		logger.WithError(err).Error("Failed to step the deployment")
+		return err
+	}
+
 	if idl.CurrentBatchFailed() {
-		logger.Info("Current batch failed, pause the CloneSet")
-		if err := r.pauseCloneSet(idl); err != nil {
+		if p := idl.FailurePolicy(); p != tritonappsv1alpha1.FailurePause {
+			logger.Infof("Current batch failed, failure policy is %s", p)
+			return r.failCurrentBatch(idl)
+		}
+
+		logger.Info("Current batch failed, pause the workload")
+		if err := r.pauseWorkload(idl); err != nil {
 			return err
 		}
 
@@ -633,6 +803,17 @@ func (r *DeployFlowReconciler) processSmokingBatch(idl *internaldeploy.Deploy) e
 		return nil
 	}
 
+	if pods := idl.RecordCurrentBatchFailedPods(); len(pods) > 0 {
+		logger.Warnf("Pods %v failed, they are tolerated in current batch", pods)
+		r.recorder.Event(idl.Unwrap(), corev1.EventTypeWarning, eventReasonUnhealthy, fmt.Sprintf("Failed pods %v are tolerated in batch %d", pods, idl.CurrentBatchNumber()))
+	}
+
+	if idl.ReplaceFailedPods() {
+		if replaced, err := r.replaceFailedPods(idl); err != nil || replaced {
+			return err
+		}
+	}
+
 	if idl.CurrentBatchIsContainersReady() {
 		return r.processContainersReadyBatch(idl)
 	}
@@ -640,6 +821,28 @@ func (r *DeployFlowReconciler) processSmokingBatch(idl *internaldeploy.Deploy) e
 	return nil
 }
 
+// replaceFailedPods deletes failed pods in current batch, they will be created again by the CloneSet.
+// It returns true if any pod is deleted.
+func (r *DeployFlowReconciler) replaceFailedPods(idl *internaldeploy.Deploy) (bool, error) {
+	logger := r.logger.WithField("deploy", idl)
+
+	replaced := false
+	for _, p := range idl.CurrentBatchPods() {
+		if p.Phase != setting.PodFailed || idl.FailedPodAccepted(p.Name) {
+			continue
+		}
+
+		logger.Infof("Replacing failed pod %s", p.Name)
+		if err := DeletePod(idl.Namespace, p.Name, r.Client); err != nil {
+			logger.WithError(err).Errorf("Failed to delete pod %s", p.Name)
+			return replaced, err
+		}
+		replaced = true
+	}
+
+	return replaced, nil
+}
+
 // processSmokedBatch enables new pods by:
 // 1. set pod readiness gate to True.
 // 2. when all pods are ready, pull in from registry.
@@ -663,7 +866,7 @@ func (r *DeployFlowReconciler) processFinishedBatch(idl *internaldeploy.Deploy)
 	batch := idl.CurrentBatchInfo()
 	// if mode is auto and current batch is canary, do not wait batch interval
 	if !(idl.Auto() && idl.CurrentBatchIsCanary()) {
-		if time.Since(batch.FinishedAt.Time) < time.Duration(idl.BatchIntervalSeconds())*time.Second {
+		if time.Since(batch.FinishedAt.Time) < time.Duration(idl.CurrentBatchIntervalSeconds())*time.Second {
 			logger.Info("batch time interval not reached, sleep 3 seconds and try again")
 			return terrors.NewTimeIntervalNotReachedError(3 * time.Second)
 		}
@@ -705,6 +908,10 @@ func (r *DeployFlowReconciler) processContainersReadyBatch(idl *internaldeploy.D
 			//return err
 		}
 	}
+	if err := r.routeBatch(idl); err != nil {
+		logger.WithError(err).Error("failed to route requests")
+		return err
+	}
 
 	logger.Info("Marking current batch as smoked")
 	idl.MarkCurrentBatchAsSmoked()
@@ -724,10 +931,10 @@ func (r *DeployFlowReconciler) prepareToBakeBatch(idl *internaldeploy.Deploy) er
 			return r.setPodReadinessGates(idl)
 		}
 		// pull in new pods
-		//if err := r.pullIn(idl); err != nil {
-		//	logger.WithError(err).Error("failed to pull in new pods")
-		//	return err
-		//}
+		if err := r.pullIn(idl); err != nil {
+			logger.WithError(err).Error("failed to pull in new pods")
+			return err
+		}
 	} else {
 		logger.Info("skip pulling in")
 	}
@@ -740,6 +947,11 @@ func (r *DeployFlowReconciler) prepareToBakeBatch(idl *internaldeploy.Deploy) er
 func (r *DeployFlowReconciler) processBakingBatch(idl *internaldeploy.Deploy) error {
 	logger := r.logger.WithField("deploy", idl)
 
+	if remaining := idl.BakeRemaining(idl.CurrentBatchInfo()); remaining > 0 {
+		logger.Infof("Batch %d is baking, %s left", idl.CurrentBatchNumber(), remaining.Round(time.Second))
+		return terrors.NewBakingNotFinishedError(remaining)
+	}
+
 	logger.Info("Baking is done, remove old pods if any and mark current batch as baked")
 
 	// pull out old pods
@@ -748,6 +960,10 @@ func (r *DeployFlowReconciler) processBakingBatch(idl *internaldeploy.Deploy) er
 		return err
 	}
 
+	if passed, err := r.runHooks(idl, tritonappsv1alpha1.PostBatch, idl.PostBatchHooks()); err != nil || !passed {
+		return err
+	}
+
 	logger.Infof("Batch %d is finished", idl.CurrentBatchNumber())
 	idl.MarkCurrentBatchAsFinished()
 
@@ -755,60 +971,199 @@ func (r *DeployFlowReconciler) processBakingBatch(idl *internaldeploy.Deploy) er
 }
 
 func (r *DeployFlowReconciler) pullOut(idl *internaldeploy.Deploy) error {
-	logger := log.WithField("deploy", idl.Name)
+	logger := r.logger.WithField("deploy", idl)
 
 	if idl.Spec.Action == setting.Create {
 		return nil
 	}
+	// old pods are pulled out and replaced when the batch is started.
+	if idl.InPlace() && (idl.Spec.Action == setting.Update || idl.Spec.Action == setting.Rollback) {
+		return nil
+	}
+	// the patch is relative to the replicas observed in each pass, the batch may be requeued after it, ex: by post-batch hooks.
+	if removedAt := idl.CurrentBatchRemovedAt(); !removedAt.IsZero() {
+		return nil
+	}
 
 	if idl.Spec.Action == setting.Restart || (idl.Spec.Action == setting.ScaleIn && len(idl.NonUpdateStrategy().PodsToDelete) > 0) {
-		if err := r.pullOutPodsForRestart(idl); err != nil {
+		pvcs, err := r.pullOutPodsForRestart(idl)
+		if err != nil {
 			logger.WithError(err).Error("Failed to pull out pods for restart")
 			return err
 		}
+
+		logger.Info("Start to pull out old pods.")
+		if err := r.processWorkload(idl); err != nil {
+			return err
+		}
+		idl.MarkCurrentBatchAsRemoved()
+		r.deletePVCs(idl, pvcs)
+		return nil
+	}
+
+	// old pods are removed by CloneSet, deregister them before it happens.
+	var pods []string
+	if idl.Spec.Action == setting.Update || idl.Spec.Action == setting.Rollback || idl.Spec.Action == setting.ScaleIn {
+		ptd, err := r.pullOutOldPods(idl)
+		if err != nil {
+			logger.WithError(err).Error("Failed to pull out old pods")
+			return err
+		}
+		pods = podNames(ptd)
+	}
+
+	pvcs, err := r.retainPVCs(idl, pods)
+	if err != nil {
+		logger.WithError(err).Error("Failed to apply PVC retention policy")
+		return err
 	}
 
 	logger.Info("Start to pull out old pods.")
 
-	return r.processCloneSet(idl)
+	if err := r.processWorkloadWithPodsToDelete(idl, pods); err != nil {
+		return err
+	}
+	idl.MarkCurrentBatchAsRemoved()
+	r.deletePVCs(idl, pvcs)
+	return nil
 }
-func (r *DeployFlowReconciler) pullOutPodsForRestart(idl *internaldeploy.Deploy) error {
-	logger := log.WithField("deploy", idl.Name)
 
-	ptd, err := r.getPodsForDeletion(idl)
+// pullOutPodsForRestart deletes the pulled out pods, and returns their PVCs to delete after the workload is patched.
+func (r *DeployFlowReconciler) pullOutPodsForRestart(idl *internaldeploy.Deploy) ([]string, error) {
+	logger := r.logger.WithField("deploy", idl)
+
+	ptd, err := r.pullOutOldPods(idl)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	pvcs, err := r.retainPVCs(idl, podNames(ptd))
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []client.DeleteOption
+	if s := idl.GracefulPeriodSeconds(); s > 0 {
+		opts = append(opts, client.GracePeriodSeconds(int64(s)))
 	}
 
-	batchSize := idl.CurrentBatchSize()
-	var p string
-	for i := 0; i < batchSize; i++ {
-		if len(ptd) == 0 {
-			break
+	for _, p := range ptd {
+		logger.Infof("Deleting pod %s", p.Name)
+		if err := DeletePod(idl.Namespace, p.Name, r.Client, opts...); err != nil {
+			logger.WithError(err).Errorf("Failed to delete pod %s", p.Name)
+		}
+	}
+
+	return pvcs, nil
+}
+
+// pullOutOldPods deregisters the old pods which will be deleted in current batch, and waits until they are drained.
+func (r *DeployFlowReconciler) pullOutOldPods(idl *internaldeploy.Deploy) ([]tritonappsv1alpha1.PodInfo, error) {
+	logger := r.logger.WithField("deploy", idl)
+
+	if pulledOutAt := idl.CurrentBatchPulledOutAt(); pulledOutAt.IsZero() {
+		ptd, err := r.getPodsForDeletion(idl)
+		if err != nil {
+			return nil, err
+		}
+
+		if batchSize := idl.CurrentBatchSize(); len(ptd) > batchSize {
+			ptd = ptd[:batchSize]
 		}
-		p, ptd = ptd[0], ptd[1:]
-		logger.Infof("Deleting pod %s", p)
-		if err := DeletePod(idl.Namespace, p, r.Client); err != nil {
-			logger.WithError(err).Errorf("Failed to delete pod %s", p)
+
+		if err := r.deregisterPods(idl, ptd); err != nil {
+			return nil, err
 		}
+		idl.SetPulledOut(podNames(ptd))
 	}
 
-	return nil
+	ptd, err := r.getPulledOutPods(idl)
+	if err != nil {
+		return nil, err
+	}
+
+	drained, err := r.podsDrained(idl, ptd)
+	if err != nil {
+		return nil, err
+	}
+	if !drained {
+		if time.Since(idl.CurrentBatchPulledOutAt().Time) < drainTimeout {
+			logger.Info("Old pods are not drained yet, checking again")
+			return nil, terrors.NewInstanceNotDrainedError(time.Second)
+		}
+		logger.Warnf("Timeout waiting for pods %s to be drained, remove them anyway", strings.Join(podNames(ptd), ", "))
+	}
+
+	if err := r.removeDisabledPods(idl, ptd); err != nil {
+		return nil, err
+	}
+
+	return ptd, nil
 }
 
-func (r *DeployFlowReconciler) getPodsForDeletion(idl *internaldeploy.Deploy) ([]string, error) {
+// getPulledOutPods returns the pulled out pods which still exist.
+func (r *DeployFlowReconciler) getPulledOutPods(idl *internaldeploy.Deploy) ([]tritonappsv1alpha1.PodInfo, error) {
+	return r.getExistingPods(idl, idl.CurrentBatchPulledOutPods())
+}
+
+// getExistingPods returns the pods of the given names which still exist.
+func (r *DeployFlowReconciler) getExistingPods(idl *internaldeploy.Deploy, names []string) ([]tritonappsv1alpha1.PodInfo, error) {
+	pods := make([]tritonappsv1alpha1.PodInfo, 0, len(names))
+	for _, n := range names {
+		p, found, err := fetcher.GetPodInCache(idl.Namespace, n, r.Client)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+
+		ip := internalpod.FromPod(p)
+		pods = append(pods, tritonappsv1alpha1.PodInfo{
+			Name:  ip.Name,
+			IP:    ip.GetPodIP(),
+			Port:  ip.GetAppPort(),
+			Phase: string(ip.GetPhase()),
+		})
+	}
+
+	return pods, nil
+}
+
+func (r *DeployFlowReconciler) getPodsForDeletion(idl *internaldeploy.Deploy) ([]tritonappsv1alpha1.PodInfo, error) {
 	s := workload.GetDefaultSelector(idl.Spec.Application.AppID, idl.Spec.Application.GroupID)
 	pods := &corev1.PodList{}
 	if err := r.List(context.TODO(), pods, client.InNamespace(idl.Namespace), client.MatchingLabelsSelector{Selector: s.AsSelector()}); err != nil {
 		return nil, errors.Wrap(err, "failed to fetch pods")
 	}
 
+	// keep the order stable, so that the same pods are picked in every try.
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+	// an Advanced StatefulSet removes or updates the pods of the highest ordinals first.
+	if idl.InPlace() {
+		sort.SliceStable(pods.Items, func(i, j int) bool {
+			return podOrdinal(&pods.Items[i]) > podOrdinal(&pods.Items[j])
+		})
+	}
+
 	newPods := sets.NewString(idl.Status.Pods...)
 	ptd := sets.NewString(idl.NonUpdateStrategy().PodsToDelete...)
+	// old pods pulled out in previous batches are kept by a Deployment until it is resumed in current batch.
+	pulledOut := sets.NewString()
+	if idl.Stepped() {
+		for _, c := range idl.Status.Conditions {
+			if c.Batch != idl.CurrentBatchNumber() {
+				pulledOut.Insert(c.PulledOutPods...)
+			}
+		}
+	}
 
-	readyPods := make([]string, 0, len(pods.Items))
-	notReadyPods := make([]string, 0, len(pods.Items))
-	for _, p := range pods.Items {
+	readyPods := make([]tritonappsv1alpha1.PodInfo, 0, len(pods.Items))
+	notReadyPods := make([]tritonappsv1alpha1.PodInfo, 0, len(pods.Items))
+	for i := range pods.Items {
+		p := &pods.Items[i]
 		if !p.DeletionTimestamp.IsZero() {
 			continue
 		}
@@ -817,15 +1172,21 @@ func (r *DeployFlowReconciler) getPodsForDeletion(idl *internaldeploy.Deploy) ([
 			if !ptd.Has(p.Name) {
 				continue
 			}
-		} else if newPods.Has(p.Name) {
+		} else if newPods.Has(p.Name) || pulledOut.Has(p.Name) {
 			continue
 		}
 
-		ip := internalpod.FromPod(&p)
+		ip := internalpod.FromPod(p)
+		pi := tritonappsv1alpha1.PodInfo{
+			Name:  ip.Name,
+			IP:    ip.GetPodIP(),
+			Port:  ip.GetAppPort(),
+			Phase: string(ip.GetPhase()),
+		}
 		if ip.Ready() {
-			readyPods = append(readyPods, ip.Name)
+			readyPods = append(readyPods, pi)
 		} else {
-			notReadyPods = append(notReadyPods, p.Name)
+			notReadyPods = append(notReadyPods, pi)
 		}
 	}
 
@@ -838,7 +1199,7 @@ func (r *DeployFlowReconciler) checkBakingGate(idl *internaldeploy.Deploy) error
 	if !idl.SkipPullIn() {
 		logger.Info("Checking if all pods are pulled in")
 
-		if time.Since(idl.CurrentBatchPullInAt().Time) > 20*time.Second {
+		if time.Since(idl.CurrentBatchPullInAt().Time) > idl.PullInTimeout() {
 			pods := idl.CurrentBatchPods()
 			failedPods := make([]string, 0, len(pods))
 			for i := range pods {
@@ -849,7 +1210,7 @@ func (r *DeployFlowReconciler) checkBakingGate(idl *internaldeploy.Deploy) error
 			}
 
 			if len(failedPods) > 0 {
-				logger.Infof("Timeout waiting for all pods to be enabled in nacos, failed pods are %s", strings.Join(failedPods, ", "))
+				logger.Infof("Timeout waiting for all pods to be enabled, failed pods are %s", strings.Join(failedPods, ", "))
 
 				batch := idl.CurrentBatchInfo()
 				batch.Pods = pods
@@ -862,7 +1223,11 @@ func (r *DeployFlowReconciler) checkBakingGate(idl *internaldeploy.Deploy) error
 			return nil
 		}
 
-		enabledPods, ok := r.isAllInstancesUp(idl)
+		enabledPods, ok, err := r.isAllInstancesUp(idl)
+		if err != nil {
+			logger.WithError(err).Error("failed to check instances status")
+			return err
+		}
 		if len(enabledPods) > 0 {
 			ep := sets.NewString(enabledPods...)
 			pods := idl.CurrentBatchPods()
@@ -904,52 +1269,42 @@ func (r *DeployFlowReconciler) setPodReadinessGates(idl *internaldeploy.Deploy)
 	return nil
 }
 
-func (r *DeployFlowReconciler) isAllInstancesUp(idl *internaldeploy.Deploy) ([]string, bool) {
+// isAllInstancesUp asks the traffic provider which pods in current batch are enabled,
+// it returns the enabled pods, and whether all pods are pulled in or failed.
+func (r *DeployFlowReconciler) isAllInstancesUp(idl *internaldeploy.Deploy) ([]string, bool, error) {
 	logger := r.logger.WithField("deploy", idl)
 	logger.Info("Checking instances status")
 
-	var wg sync.WaitGroup
 	pods := idl.CurrentBatchPods()
-	var finishedPods int32
-	mu := &sync.Mutex{}
-	enabledPods := make([]string, 0, len(pods))
-
+	finishedPods := 0
+	podsToCheck := make([]tritonappsv1alpha1.PodInfo, 0, len(pods))
 	for i := range pods {
-		wg.Add(1)
-		go func(i int) {
-			defer wg.Done()
-
-			ip := pods[i].IP
-			phase := pods[i].Phase
-			podLogger := logger.WithField("ip", ip).WithField("name", pods[i].Name)
-
-			if pods[i].PullInStatus == setting.PodPullInSucceeded || phase == setting.PodFailed {
-				atomic.AddInt32(&finishedPods, 1)
-				return
-			}
-
-			if phase != setting.PodReady {
-				podLogger.Warnf("Exception: pod is in an unexpected phase %s", pods[i].Phase)
-				return
-			}
+		if pods[i].PullInStatus == setting.PodPullInSucceeded || pods[i].Phase == setting.PodFailed {
+			finishedPods++
+			continue
+		}
+		podsToCheck = append(podsToCheck, pods[i])
+	}
 
-			// TODO replace with readinessGate
-			//if r.nacosClient.IsInstanceEnabled(idl.Spec.AppName, ip) {
-			podLogger.Info("All pods in current batch pull in nacos success")
+	if len(podsToCheck) == 0 {
+		return nil, true, nil
+	}
 
-			mu.Lock()
-			enabledPods = append(enabledPods, pods[i].Name)
-			mu.Unlock()
+	provider, err := r.trafficProvider(idl)
+	if err != nil {
+		return nil, false, err
+	}
 
-			atomic.AddInt32(&finishedPods, 1)
-			return
-			//}
-		}(i)
+	enabledPods, err := provider.IsEnabled(idl, podsToCheck)
+	if err != nil {
+		return nil, false, err
 	}
 
-	wg.Wait()
+	if len(enabledPods) > 0 {
+		logger.Infof("Pods %s are pulled in", strings.Join(enabledPods, ", "))
+	}
 
-	return enabledPods, finishedPods == int32(len(pods))
+	return enabledPods, finishedPods+len(enabledPods) == len(pods), nil
 }
 
 func (r *DeployFlowReconciler) registerPods(idl *internaldeploy.Deploy) error {
@@ -960,56 +1315,63 @@ func (r *DeployFlowReconciler) registerPods(idl *internaldeploy.Deploy) error {
 		return fmt.Errorf("current batch is missing, batches are %v", idl.Status.Conditions)
 	}
 
-	var lastErr error
-	for i := range batch.Pods {
-		logger.Infof("Start to register pod %s.", batch.Pods[i].Name)
-		//if err := r.gatewayClient.Add(idl.Spec.AppID, batch.Pods[i].IP, batch.Pods[i].Port); err != nil {
-		//	logger.WithError(err).Errorf("failed to resister pod %s", batch.Pods[i].Name)
-		//	lastErr = err
-		//}
-		logger.Infof("Success register pod %s, podIp is %s", batch.Pods[i].Name, batch.Pods[i].IP)
+	provider, err := r.trafficProvider(idl)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Start to register pods %s.", strings.Join(podNames(batch.Pods), ", "))
+	if err := provider.Register(idl, batch.Pods); err != nil {
+		logger.WithError(err).Error("failed to register pods")
+		return err
 	}
 
-	return lastErr
+	return nil
 }
 
 func (r *DeployFlowReconciler) postDeploy(idl *internaldeploy.Deploy) {
 	logger := r.logger.WithField("deploy", idl)
 	logger.Info("Deploy is finished, releasing resources")
 
-	if err := r.removeCloneSetOwnerWithRetry(idl); err != nil {
-		logger.WithError(err).Error("Failed to remove CloneSet owner")
+	if err := r.removeWorkloadOwnerWithRetry(idl); err != nil {
+		logger.WithError(err).Error("Failed to remove workload owner")
+	}
+	if err := r.removeCanaryRoute(idl); err != nil {
+		logger.WithError(err).Error("Failed to remove canary route")
+	}
+	if err := r.removeShadowing(idl); err != nil {
+		logger.WithError(err).Error("Failed to remove shadowing")
 	}
 }
 
-// DeleteCloneSetWhenActionIsScaleInZero handle zero replicas cloneset
-func (r *DeployFlowReconciler) DeleteCloneSetWhenActionIsScaleInZero(dl *tritonappsv1alpha1.DeployFlow) error {
+// DeleteWorkloadWhenActionIsScaleInZero handle zero replicas workload
+func (r *DeployFlowReconciler) DeleteWorkloadWhenActionIsScaleInZero(dl *tritonappsv1alpha1.DeployFlow) error {
 
 	idl := internaldeploy.FromDeploy(dl)
 
 	if idl.Spec.Action == setting.ScaleIn && idl.Finished() && *idl.Spec.Application.Replicas == 0 {
-		// get cloneSet owned by deployflow
-		cs, found, err := fetcher.GetCloneSetInCacheByDeploy(dl, r.Client)
+		// get workload owned by deployflow
+		obj, found, err := fetcher.GetWorkloadInCacheByDeploy(dl, r.Client)
 		if err != nil || !found {
-			r.logger.Errorf("failed to found cloneSet %s, error is %v", idl.Spec.Application.CloneSetName, err)
-			return fmt.Errorf("failed to found cloneSet %s, error is %v", idl.Spec.Application.CloneSetName, err)
+			r.logger.Errorf("failed to found %s %s, error is %v", idl.ApplicationType(), idl.Spec.Application.CloneSetName, err)
+			return fmt.Errorf("failed to found %s %s, error is %v", idl.ApplicationType(), idl.Spec.Application.CloneSetName, err)
 		}
-		err = r.Client.Delete(context.TODO(), cs)
+		err = r.Client.Delete(context.TODO(), obj)
 		if err != nil {
-			r.logger.Errorf("failed to delete cloneSet %s", idl.Spec.Application.CloneSetName)
+			r.logger.Errorf("failed to delete %s %s", idl.ApplicationType(), idl.Spec.Application.CloneSetName)
 			return err
 		}
 	}
 	return nil
 }
 
-func DeletePod(ns, name string, cl client.Client) error {
+func DeletePod(ns, name string, cl client.Client, opts ...client.DeleteOption) error {
 	err := cl.Delete(context.TODO(), &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: ns,
 			Name:      name,
 		},
-	})
+	}, opts...)
 
 	return client.IgnoreNotFound(err)
 }
@@ -0,0 +1,333 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployflow
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	"github.com/sirupsen/logrus"
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	terrors "github.com/triton-io/triton/pkg/errors"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	"github.com/triton-io/triton/pkg/setting"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func newTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = kruiseappsv1alpha1.AddToScheme(scheme)
+	_ = tritonappsv1alpha1.AddToScheme(scheme)
+	return scheme
+}
+
+func newTestReconciler(scheme *runtime.Scheme, objs ...runtime.Object) *DeployFlowReconciler {
+	cl := fake.NewFakeClientWithScheme(scheme, objs...)
+	return &DeployFlowReconciler{
+		Client:   cl,
+		Scheme:   scheme,
+		reader:   cl,
+		logger:   logrus.NewEntry(logrus.New()),
+		recorder: record.NewFakeRecorder(10),
+	}
+}
+
+// TestProcessReplansStartedDeploy checks that a started deploy of 20 replicas in batches of 5 is planned again
+// once its strategy is changed, even if it is paused before batch 3.
+func TestProcessReplansStartedDeploy(t *testing.T) {
+	tests := []struct {
+		name      string
+		batchSize string
+		steps     []tritonappsv1alpha1.BatchStep
+		replanned bool
+		batches   int
+		current   int
+	}{
+		{name: "unchanged", batchSize: "5", batches: 4, current: 5},
+		{name: "same batch size in percentage", batchSize: "25%", batches: 4, current: 5},
+		{name: "smaller batch size", batchSize: "2", replanned: true, batches: 7, current: 2},
+		{
+			// 60% of 20 is 12, 2 pods are left to the step after 10 pods are finished.
+			name:      "steps set",
+			batchSize: "5",
+			steps:     []tritonappsv1alpha1.BatchStep{{Replicas: intstr.FromString("60%")}, {Replicas: intstr.FromString("100%")}},
+			replanned: true,
+			batches:   4,
+			current:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var replicas int32 = 20
+			paused := true
+			batchSize := intstr.Parse(tt.batchSize)
+			idl := internaldeploy.FromDeploy(&tritonappsv1alpha1.DeployFlow{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app-update"},
+				Spec: tritonappsv1alpha1.DeployFlowSpec{
+					Action:      setting.Update,
+					Application: &tritonappsv1alpha1.ApplicationSpec{Replicas: &replicas},
+					UpdateStrategy: &tritonappsv1alpha1.DeployUpdateStrategy{
+						BaseStrategy: tritonappsv1alpha1.BaseStrategy{BatchSize: &batchSize, Steps: tt.steps, Paused: &paused},
+					},
+				},
+				Status: tritonappsv1alpha1.DeployFlowStatus{
+					Phase:             tritonappsv1alpha1.BatchStarted,
+					Paused:            true,
+					ReplicasToProcess: replicas,
+					Batches:           4,
+					FinishedBatches:   2,
+					FinishedReplicas:  10,
+					Conditions: []tritonappsv1alpha1.BatchCondition{
+						{Batch: 1, BatchSize: 5, Phase: tritonappsv1alpha1.BatchBaked},
+						{Batch: 2, BatchSize: 5, Phase: tritonappsv1alpha1.BatchBaked},
+						{Batch: 3, BatchSize: 5, Phase: tritonappsv1alpha1.BatchPending},
+					},
+				},
+			})
+
+			r := newTestReconciler(newTestScheme())
+			if err := r.process(idl); err != nil {
+				t.Fatal(err)
+			}
+
+			if idl.Status.Batches != tt.batches || idl.CurrentBatchSize() != tt.current {
+				t.Errorf("expected %d batches and batch 3 of %d pods, got %d and %d", tt.batches, tt.current, idl.Status.Batches, idl.CurrentBatchSize())
+			}
+			if replanned := len(idl.Status.Replans) == 1; replanned != tt.replanned {
+				t.Errorf("expected replanned %t, got replans %+v", tt.replanned, idl.Status.Replans)
+			}
+			if !idl.Paused() {
+				t.Error("expected the deploy to stay paused")
+			}
+		})
+	}
+}
+
+// TestBakingBatchWaitsForBakeTime checks that old pods are left alone until a batch pulled in 20 seconds ago is baked.
+func TestBakingBatchWaitsForBakeTime(t *testing.T) {
+	var canaryBakeSeconds int32 = 120
+
+	tests := []struct {
+		name     string
+		canary   bool
+		expected time.Duration
+	}{
+		{name: "batch", expected: 40 * time.Second},
+		{name: "canary batch", canary: true, expected: 100 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var replicas int32 = 10
+			idl := internaldeploy.FromDeploy(&tritonappsv1alpha1.DeployFlow{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app-update"},
+				Spec: tritonappsv1alpha1.DeployFlowSpec{
+					Action:         setting.Update,
+					Application:    &tritonappsv1alpha1.ApplicationSpec{Replicas: &replicas},
+					UpdateStrategy: &tritonappsv1alpha1.DeployUpdateStrategy{BakeSeconds: 60, CanaryBakeSeconds: &canaryBakeSeconds},
+				},
+				Status: tritonappsv1alpha1.DeployFlowStatus{
+					ReplicasToProcess: replicas,
+					Batches:           2,
+					Conditions: []tritonappsv1alpha1.BatchCondition{{
+						Batch:      1,
+						BatchSize:  5,
+						Canary:     tt.canary,
+						Phase:      tritonappsv1alpha1.BatchBaking,
+						PulledInAt: metav1.NewTime(time.Now().Add(-20 * time.Second)),
+					}},
+				},
+			})
+
+			// no workload exists, the batch would fail if old pods are pulled out.
+			r := newTestReconciler(newTestScheme())
+			err := r.processBakingBatch(idl)
+			e, ok := err.(terrors.RequeueError)
+			if !ok {
+				t.Fatalf("expected the batch to be requeued, got %v", err)
+			}
+			if after := e.RequeueAfter(); after > tt.expected || after < tt.expected-time.Second {
+				t.Errorf("expected to be requeued after %s, got %s", tt.expected, after)
+			}
+			if idl.CurrentBatchPhase() != tritonappsv1alpha1.BatchBaking || idl.Status.FinishedBatches != 0 {
+				t.Errorf("expected the batch to stay %s, got %s", tritonappsv1alpha1.BatchBaking, idl.CurrentBatchPhase())
+			}
+		})
+	}
+}
+
+// TestSmokingBatchToleratesFailedPods checks that a smoking batch of 4 pods moves on with 1 failed pod tolerated,
+// and the deploy is paused once more pods fail.
+func TestSmokingBatchToleratesFailedPods(t *testing.T) {
+	tests := []struct {
+		name   string
+		failed int
+		paused bool
+	}{
+		{name: "no failed pod"},
+		{name: "failed pod tolerated", failed: 1},
+		{name: "failed pods exceeding the tolerance", failed: 2, paused: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var replicas int32 = 8
+			pods := make([]tritonappsv1alpha1.PodInfo, 0, 4)
+			for i := 0; i < 4; i++ {
+				p := tritonappsv1alpha1.PodInfo{Name: fmt.Sprintf("app-%d", i), Phase: setting.ContainersReady}
+				if i >= 4-tt.failed {
+					p.Phase = setting.PodFailed
+				}
+				pods = append(pods, p)
+			}
+			deploy := &tritonappsv1alpha1.DeployFlow{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app-update", UID: "uid"},
+				Spec: tritonappsv1alpha1.DeployFlowSpec{
+					Action:      setting.Update,
+					Application: &tritonappsv1alpha1.ApplicationSpec{CloneSetName: "app", Replicas: &replicas},
+					UpdateStrategy: &tritonappsv1alpha1.DeployUpdateStrategy{
+						BaseStrategy: tritonappsv1alpha1.BaseStrategy{MaxFailedReplicas: 1},
+					},
+				},
+				Status: tritonappsv1alpha1.DeployFlowStatus{
+					ReplicasToProcess: replicas,
+					Batches:           2,
+					Conditions: []tritonappsv1alpha1.BatchCondition{
+						{Batch: 1, BatchSize: 4, Phase: tritonappsv1alpha1.BatchSmoking, Pods: pods},
+					},
+				},
+			}
+			cs := &kruiseappsv1alpha1.CloneSet{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+				Spec:       kruiseappsv1alpha1.CloneSetSpec{Replicas: &replicas},
+			}
+			scheme := newTestScheme()
+			_ = controllerutil.SetControllerReference(deploy, cs, scheme)
+			r := newTestReconciler(scheme, cs)
+			idl := internaldeploy.FromDeploy(deploy)
+
+			if err := r.processSmokingBatch(idl); err != nil {
+				t.Fatal(err)
+			}
+
+			got := &kruiseappsv1alpha1.CloneSet{}
+			if err := r.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "app"}, got); err != nil {
+				t.Fatal(err)
+			}
+			if idl.Paused() != tt.paused || got.Spec.UpdateStrategy.Paused != tt.paused {
+				t.Errorf("expected the deploy and the CloneSet paused %t, got %t and %t", tt.paused, idl.Paused(), got.Spec.UpdateStrategy.Paused)
+			}
+			if tt.paused {
+				if idl.CurrentBatchPhase() != tritonappsv1alpha1.BatchSmoking {
+					t.Errorf("expected the batch to stay %s, got %s", tritonappsv1alpha1.BatchSmoking, idl.CurrentBatchPhase())
+				}
+				return
+			}
+
+			if idl.CurrentBatchPhase() != tritonappsv1alpha1.BatchSmoked {
+				t.Errorf("expected the batch to be %s, got %s", tritonappsv1alpha1.BatchSmoked, idl.CurrentBatchPhase())
+			}
+			if failed := idl.CurrentBatchInfo().FailedPods; len(failed) != tt.failed {
+				t.Errorf("expected %d failed pods recorded, got %v", tt.failed, failed)
+			}
+			// a warning is recorded for the tolerated pods.
+			if events := len(r.recorder.(*record.FakeRecorder).Events); events != tt.failed {
+				t.Errorf("expected %d events, got %d", tt.failed, events)
+			}
+		})
+	}
+}
+
+// TestPendingBatchTimesOut checks that a deploy of 60 seconds waiting at pending batch 2 is paused once it is late,
+// and the batch stays pending to be started after resumed.
+func TestPendingBatchTimesOut(t *testing.T) {
+	tests := []struct {
+		name     string
+		started  time.Duration
+		timedOut bool
+	}{
+		{name: "deadline not reached", started: 30 * time.Second},
+		{name: "deadline exceeded", started: 2 * time.Minute, timedOut: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var replicas int32 = 10
+			deploy := &tritonappsv1alpha1.DeployFlow{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app-update", UID: "uid"},
+				Spec: tritonappsv1alpha1.DeployFlowSpec{
+					Action:         setting.Update,
+					Application:    &tritonappsv1alpha1.ApplicationSpec{CloneSetName: "app", Replicas: &replicas},
+					UpdateStrategy: &tritonappsv1alpha1.DeployUpdateStrategy{BaseStrategy: tritonappsv1alpha1.BaseStrategy{TimeoutSeconds: 60}},
+				},
+				Status: tritonappsv1alpha1.DeployFlowStatus{
+					Phase:             tritonappsv1alpha1.BatchStarted,
+					StartedAt:         metav1.NewTime(time.Now().Add(-tt.started)),
+					ReplicasToProcess: replicas,
+					Batches:           2,
+					FinishedBatches:   1,
+					FinishedReplicas:  5,
+					Conditions: []tritonappsv1alpha1.BatchCondition{
+						{Batch: 1, BatchSize: 5, Phase: tritonappsv1alpha1.BatchBaked},
+						{Batch: 2, BatchSize: 5, Phase: tritonappsv1alpha1.BatchPending},
+					},
+				},
+			}
+			cs := &kruiseappsv1alpha1.CloneSet{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+				Spec:       kruiseappsv1alpha1.CloneSetSpec{Replicas: &replicas},
+			}
+			scheme := newTestScheme()
+			_ = controllerutil.SetControllerReference(deploy, cs, scheme)
+			r := newTestReconciler(scheme, cs)
+			idl := internaldeploy.FromDeploy(deploy)
+
+			err := r.process(idl)
+			if !tt.timedOut {
+				// a manual deploy waits for batch 2 to be desired, and wakes up at the deadline.
+				e, ok := err.(terrors.RequeueError)
+				if !ok {
+					t.Fatalf("expected the deploy to be requeued, got %v", err)
+				}
+				if after := e.RequeueAfter(); after > 31*time.Second || after < 30*time.Second {
+					t.Errorf("expected to be requeued after 31s, got %s", after)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if idl.Paused() != tt.timedOut || idl.CurrentBatchTimedOut() != tt.timedOut {
+				t.Errorf("expected the deploy paused and timed out %t, got %t and %t", tt.timedOut, idl.Paused(), idl.CurrentBatchTimedOut())
+			}
+			if idl.CurrentBatchNumber() != 2 || idl.CurrentBatchPhase() != tritonappsv1alpha1.BatchPending {
+				t.Errorf("expected batch 2 to stay %s, got batch %d %s", tritonappsv1alpha1.BatchPending, idl.CurrentBatchNumber(), idl.CurrentBatchPhase())
+			}
+		})
+	}
+}
@@ -2,8 +2,15 @@ package deployflow
 
 const (
 	// event reasons
-	eventReasonDeployed  = "Deployed"
-	eventReasonUnhealthy = "Unhealthy"
+	eventReasonDeployed     = "Deployed"
+	eventReasonUnhealthy    = "Unhealthy"
+	eventReasonAnalysis     = "AnalysisFailed"
+	eventReasonWebhook      = "WebhookFailed"
+	eventReasonRollback     = "Rollback"
+	eventReasonTimeout      = "ProgressDeadlineExceeded"
+	eventReasonRevert       = "Revert"
+	eventReasonBlueGreen    = "BlueGreen"
+	eventReasonPVCRetention = "PVCRetentionFailed"
 
 	// event messages
 	eventMessageDeployed = "DeployFlow is finished successfully"
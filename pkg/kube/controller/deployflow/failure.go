@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployflow
+
+import (
+	"fmt"
+
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	"github.com/triton-io/triton/pkg/kube/fetcher"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	"github.com/triton-io/triton/pkg/kube/types/workload"
+	"github.com/triton-io/triton/pkg/services/deployflow"
+	"github.com/triton-io/triton/pkg/setting"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// failCurrentBatch stops the deploy when current batch fails, the deploy is rolled back if the failure policy is Rollback,
+// or aborted if it is Abort, otherwise it is marked as failed.
+func (r *DeployFlowReconciler) failCurrentBatch(idl *internaldeploy.Deploy) error {
+	logger := r.logger.WithField("deploy", idl)
+
+	if err := r.pauseWorkload(idl); err != nil {
+		logger.WithError(err).Error("Failed to pause workload")
+		return err
+	}
+
+	switch idl.FailurePolicy() {
+	case tritonappsv1alpha1.FailureRollback:
+		created, err := r.rollback(idl)
+		if err != nil {
+			logger.WithError(err).Error("Failed to roll back the deploy")
+			return err
+		}
+		if !created {
+			logger.Warn("No successful deploy to roll back to, pause the deploy")
+			r.recorder.Event(idl.Unwrap(), corev1.EventTypeWarning, eventReasonRollback, "No successful deploy to roll back to, the deploy is paused")
+			idl.MarkAsPaused()
+			return nil
+		}
+
+		logger.Infof("Marking deploy as failed, it is rolled back by %s", idl.Status.RollbackBy)
+		idl.MarkCurrentBatchAsFailed()
+		idl.MarkAsFailed()
+	case tritonappsv1alpha1.FailureAbort:
+		logger.Info("Aborting deploy due to batch failure")
+		idl.MarkCurrentBatchAsFailed()
+		idl.MarkAsAborted()
+	default:
+		logger.Info("Marking current batch and deploy as failed")
+		idl.MarkCurrentBatchAsFailed()
+		idl.MarkAsFailed()
+	}
+
+	return nil
+}
+
+// rollback creates a deploy to roll back to the previous successful revision, it returns false if there is no such revision.
+func (r *DeployFlowReconciler) rollback(idl *internaldeploy.Deploy) (bool, error) {
+	logger := r.logger.WithField("deploy", idl)
+
+	if idl.Status.RollbackBy != "" {
+		return true, nil
+	}
+
+	// the rollback deploy may be created in a previous reconcile whose status update failed.
+	if name, found, err := r.getRollbackDeploy(idl); err != nil {
+		return false, err
+	} else if found {
+		idl.Status.RollbackBy = name
+		return true, nil
+	}
+
+	last, found, err := deployflow.LastSuccessfulDeploy(idl.Namespace, idl.GetCloneSetName(), idl.Name, r.Client)
+	if err != nil || !found {
+		return false, err
+	}
+
+	obj, found, err := fetcher.GetWorkloadInCacheByDeploy(idl.Unwrap(), r.Client)
+	if err != nil {
+		return false, err
+	}
+	var replicas *int32
+	if found {
+		replicas = workload.SpecReplicas(obj)
+	}
+
+	annotations := map[string]string{setting.RollbackOfAnnotation: idl.Name}
+	updated, _, err := deployflow.RollbackTo(last.DeepCopy(), replicas, rollbackStrategy(idl), annotations, r.Client, logger)
+	if err != nil {
+		return false, err
+	}
+
+	idl.Status.RollbackBy = updated.Name
+	r.recorder.Event(idl.Unwrap(), corev1.EventTypeNormal, eventReasonRollback, fmt.Sprintf("Rolling back to deploy %s by %s", last.Name, updated.Name))
+
+	return true, nil
+}
+
+func (r *DeployFlowReconciler) getRollbackDeploy(idl *internaldeploy.Deploy) (string, bool, error) {
+	deploys, err := fetcher.GetDeploysInCache(fetcher.DeployFilter{Namespace: idl.Namespace, CloneSetName: idl.GetCloneSetName(), PageSize: 10000}, r.Client)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, d := range deploys {
+		if d.GetAnnotations()[setting.RollbackOfAnnotation] == idl.Name {
+			return d.Name, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// rollbackStrategy returns the strategy of a rollback, it rolls back automatically in batches of the failed deploy,
+// without canary, analysis and hooks.
+func rollbackStrategy(idl *internaldeploy.Deploy) *tritonappsv1alpha1.DeployUpdateStrategy {
+	s := idl.UpdateStrategy().DeepCopy()
+	s.Paused = nil
+	s.Canceled = false
+	s.Mode = tritonappsv1alpha1.Auto
+	s.Canary = 0
+	s.Stage = ""
+	s.Analysis = nil
+	s.PreBatchHooks = nil
+	s.PostBatchHooks = nil
+	s.FailurePolicy = tritonappsv1alpha1.FailurePause
+
+	return s
+}
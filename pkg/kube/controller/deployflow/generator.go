@@ -17,59 +17,198 @@ limitations under the License.
 package deployflow
 
 import (
+	"fmt"
+
 	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
 	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	"github.com/triton-io/triton/pkg/kube/types/workload"
 	"github.com/triton-io/triton/pkg/setting"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-func generate(idl *internaldeploy.Deploy) *kruiseappsv1alpha1.CloneSet {
+// generate returns the workload of the application type with no replicas, the update strategy of ws works for a CloneSet only.
+func generate(idl *internaldeploy.Deploy, ws *tritonappsv1alpha1.WorkloadStrategy) (workload.Object, error) {
+	switch idl.ApplicationType() {
+	case tritonappsv1alpha1.ApplicationTypeCloneSet:
+		return generateCloneSet(idl, ws), nil
+	case tritonappsv1alpha1.ApplicationTypeStatefulSet:
+		return generateStatefulSet(idl, ws), nil
+	case tritonappsv1alpha1.ApplicationTypeDeployment:
+		return generateDeployment(idl, ws), nil
+	case tritonappsv1alpha1.ApplicationTypeDaemonSet:
+		return generateDaemonSet(idl, ws), nil
+	default:
+		return nil, fmt.Errorf("unknown application type %q", idl.ApplicationType())
+	}
+}
+
+func generateCloneSet(idl *internaldeploy.Deploy, ws *tritonappsv1alpha1.WorkloadStrategy) *kruiseappsv1alpha1.CloneSet {
 	// always hold the create, let Deploy controller to make progress
 	var replicas int32 = 0
 
-	template := idl.Spec.Application.Template
-	template.Labels = idl.GetCloneSetLabels()
-	template.Spec.ImagePullSecrets = getImagePullSecrets()
-	template.Spec.ReadinessGates = getReadinessGates()
-
 	return &kruiseappsv1alpha1.CloneSet{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      idl.GetCloneSetName(),
-			Namespace: idl.Namespace,
-			Labels:    idl.GetCloneSetLabels(),
+			Name:        idl.GetCloneSetName(),
+			Namespace:   idl.Namespace,
+			Labels:      idl.GetWorkloadLabels(),
+			Annotations: idl.GetWorkloadAnnotations(),
 		},
 		Spec: kruiseappsv1alpha1.CloneSetSpec{
-			Replicas:       &replicas,
-			Selector:       &metav1.LabelSelector{MatchLabels: idl.GetCloneSetLabels()},
-			Template:       template,
-			UpdateStrategy: getDefaultStrategy(),
+			Replicas:             &replicas,
+			Selector:             &metav1.LabelSelector{MatchLabels: idl.GetCloneSetLabels()},
+			Template:             podTemplate(idl, ws),
+			VolumeClaimTemplates: idl.Spec.Application.VolumeClaimTemplates,
+			UpdateStrategy:       getDefaultStrategy(ws),
+		},
+	}
+}
+
+// generateStatefulSet returns an Advanced StatefulSet whose pods are recreated in an update,
+// so that the pods of a batch can be told by their creation time.
+func generateStatefulSet(idl *internaldeploy.Deploy, ws *tritonappsv1alpha1.WorkloadStrategy) *kruiseappsv1alpha1.StatefulSet {
+	var replicas, partition int32 = 0, 0
+	maxUnavailable := intstr.FromString("100%")
+
+	return &kruiseappsv1alpha1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        idl.GetCloneSetName(),
+			Namespace:   idl.Namespace,
+			Labels:      idl.GetWorkloadLabels(),
+			Annotations: idl.GetWorkloadAnnotations(),
+		},
+		Spec: kruiseappsv1alpha1.StatefulSetSpec{
+			Replicas:             &replicas,
+			Selector:             &metav1.LabelSelector{MatchLabels: idl.GetCloneSetLabels()},
+			Template:             podTemplate(idl, ws),
+			VolumeClaimTemplates: idl.Spec.Application.VolumeClaimTemplates,
+			ServiceName:          idl.GetCloneSetName(),
+			// pods of a batch are started at once, the DeployFlow controls the progress.
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			UpdateStrategy: kruiseappsv1alpha1.StatefulSetUpdateStrategy{
+				Type: appsv1.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &kruiseappsv1alpha1.RollingUpdateStatefulSetStrategy{
+					Partition: &partition,
+					// all pods above the partition are updated at once.
+					MaxUnavailable:  &maxUnavailable,
+					PodUpdatePolicy: kruiseappsv1alpha1.RecreatePodUpdateStrategyType,
+				},
+			},
+		},
+	}
+}
+
+// generateDeployment returns a Deployment which never removes an available pod before its replacement is available,
+// the maxSurge is set to the batch size when a batch is started.
+func generateDeployment(idl *internaldeploy.Deploy, ws *tritonappsv1alpha1.WorkloadStrategy) *appsv1.Deployment {
+	var replicas int32 = 0
+	maxSurge, maxUnavailable := intstr.FromInt(1), intstr.FromInt(0)
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        idl.GetCloneSetName(),
+			Namespace:   idl.Namespace,
+			Labels:      idl.GetWorkloadLabels(),
+			Annotations: idl.GetWorkloadAnnotations(),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: idl.GetCloneSetLabels()},
+			Template: podTemplate(idl, ws),
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{
+					MaxSurge:       &maxSurge,
+					MaxUnavailable: &maxUnavailable,
+				},
+			},
+		},
+	}
+}
+
+// generateDaemonSet returns an Advanced DaemonSet whose pods are recreated in an update, the partition is decreased
+// when a batch is started.
+func generateDaemonSet(idl *internaldeploy.Deploy, ws *tritonappsv1alpha1.WorkloadStrategy) *kruiseappsv1alpha1.DaemonSet {
+	var partition int32 = 0
+	maxUnavailable := intstr.FromString("100%")
+
+	return &kruiseappsv1alpha1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        idl.GetCloneSetName(),
+			Namespace:   idl.Namespace,
+			Labels:      idl.GetWorkloadLabels(),
+			Annotations: idl.GetWorkloadAnnotations(),
+		},
+		Spec: kruiseappsv1alpha1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: idl.GetCloneSetLabels()},
+			Template: podTemplate(idl, ws),
+			UpdateStrategy: kruiseappsv1alpha1.DaemonSetUpdateStrategy{
+				Type: kruiseappsv1alpha1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &kruiseappsv1alpha1.RollingUpdateDaemonSet{
+					Type: kruiseappsv1alpha1.StandardRollingUpdateType,
+					// all pods above the partition are updated at once.
+					MaxUnavailable: &maxUnavailable,
+					Partition:      &partition,
+				},
+			},
 		},
 	}
 }
 
-func getDefaultStrategy() kruiseappsv1alpha1.CloneSetUpdateStrategy {
+func podTemplate(idl *internaldeploy.Deploy, ws *tritonappsv1alpha1.WorkloadStrategy) corev1.PodTemplateSpec {
+	template := idl.Spec.Application.Template
+	template.Labels = idl.GetPodLabels()
+	template.Annotations = idl.GetPodAnnotations()
+	template.Spec.ImagePullSecrets = getImagePullSecrets(ws)
+	template.Spec.ReadinessGates = getReadinessGates(ws)
+
+	return template
+}
+
+func getDefaultStrategy(ws *tritonappsv1alpha1.WorkloadStrategy) kruiseappsv1alpha1.CloneSetUpdateStrategy {
 	maxSurge := intstr.FromString("60%")
 	maxUnavailable := intstr.FromInt(0)
+	if ws.MaxSurge != nil {
+		maxSurge = *ws.MaxSurge
+	}
+	if ws.MaxUnavailable != nil {
+		maxUnavailable = *ws.MaxUnavailable
+	}
 
 	return kruiseappsv1alpha1.CloneSetUpdateStrategy{
+		// ReCreate is used by CloneSet if it is empty.
+		Type:           kruiseappsv1alpha1.CloneSetUpdateStrategyType(ws.PodUpdatePolicy),
 		MaxSurge:       &maxSurge,
 		MaxUnavailable: &maxUnavailable,
 		Paused:         false,
 	}
 }
 
-func getImagePullSecrets() []corev1.LocalObjectReference {
+func getImagePullSecrets(ws *tritonappsv1alpha1.WorkloadStrategy) []corev1.LocalObjectReference {
+	if ws.ImagePullSecrets != nil {
+		return ws.ImagePullSecrets
+	}
+
 	return []corev1.LocalObjectReference{
 		{Name: setting.HarborCred},
 	}
 }
 
-func getReadinessGates() []corev1.PodReadinessGate {
-	return []corev1.PodReadinessGate{
+// getReadinessGates returns apps.triton.io/ready and the extra readiness gates of ws.
+func getReadinessGates(ws *tritonappsv1alpha1.WorkloadStrategy) []corev1.PodReadinessGate {
+	gates := []corev1.PodReadinessGate{
 		{
 			ConditionType: setting.PodReadinessGate,
 		},
 	}
+	for _, g := range ws.ReadinessGates {
+		if g.ConditionType != setting.PodReadinessGate {
+			gates = append(gates, g)
+		}
+	}
+
+	return gates
 }
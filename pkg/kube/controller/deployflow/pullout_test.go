@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployflow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	"github.com/triton-io/triton/pkg/setting"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// newBlockedHookServer returns a hook server which always fails, so that a batch is requeued after it is pulled out.
+func newBlockedHookServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+}
+
+// newScaleInDeploy returns a ScaleIn deploy from 5 to 1 replicas in Baking phase of its first batch of 2 pods,
+// and the CloneSet owned by it.
+func newScaleInDeploy(scheme *runtime.Scheme, hookURL string) (*tritonappsv1alpha1.DeployFlow, *kruiseappsv1alpha1.CloneSet) {
+	var replicas, target int32 = 5, 1
+	deploy := &tritonappsv1alpha1.DeployFlow{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app-scale-in", UID: "uid"},
+		Spec: tritonappsv1alpha1.DeployFlowSpec{
+			Action: setting.ScaleIn,
+			Application: &tritonappsv1alpha1.ApplicationSpec{
+				AppID:        1,
+				GroupID:      1,
+				AppName:      "app",
+				CloneSetName: "app",
+				Replicas:     &target,
+			},
+			NonUpdateStrategy: &tritonappsv1alpha1.DeployNonUpdateStrategy{
+				BaseStrategy: tritonappsv1alpha1.BaseStrategy{
+					PostBatchHooks: []tritonappsv1alpha1.Webhook{{Name: "gate", URL: hookURL}},
+				},
+			},
+		},
+		Status: tritonappsv1alpha1.DeployFlowStatus{
+			Replicas: replicas,
+			Conditions: []tritonappsv1alpha1.BatchCondition{
+				{Batch: 1, BatchSize: 2, Phase: tritonappsv1alpha1.BatchBaking},
+			},
+		},
+	}
+	cs := &kruiseappsv1alpha1.CloneSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+		Spec:       kruiseappsv1alpha1.CloneSetSpec{Replicas: &replicas},
+	}
+	_ = controllerutil.SetControllerReference(deploy, cs, scheme)
+
+	return deploy, cs
+}
+
+// TestBlockedPostBatchHookScalesInOnce checks that a scale in batch requeued by a blocked post-batch hook
+// does not scale the workload in again.
+func TestBlockedPostBatchHookScalesInOnce(t *testing.T) {
+	server := newBlockedHookServer()
+	defer server.Close()
+
+	scheme := newTestScheme()
+	deploy, cs := newScaleInDeploy(scheme, server.URL)
+	r := newTestReconciler(scheme, deploy.DeepCopy(), cs)
+	idl := internaldeploy.FromDeploy(deploy)
+
+	for i := 0; i < 3; i++ {
+		if err := r.processBakingBatch(idl); err == nil {
+			t.Fatalf("pass %d: expected the batch to be blocked by the hook", i)
+		}
+
+		got := &kruiseappsv1alpha1.CloneSet{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "app"}, got); err != nil {
+			t.Fatal(err)
+		}
+		if *got.Spec.Replicas != 3 {
+			t.Fatalf("pass %d: expected replicas 3, got %d", i, *got.Spec.Replicas)
+		}
+		// the replicas status is refreshed from the workload in every reconcile.
+		idl.Status.Replicas = *got.Spec.Replicas
+	}
+
+	if idl.CurrentBatchPhase() != tritonappsv1alpha1.BatchBaking {
+		t.Errorf("expected batch to stay Baking, got %s", idl.CurrentBatchPhase())
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployflow
+
+import (
+	"context"
+	"fmt"
+
+	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	"github.com/triton-io/triton/pkg/kube/fetcher"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	"github.com/triton-io/triton/pkg/kube/types/workload"
+	"github.com/triton-io/triton/pkg/setting"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;update;patch;delete
+
+// getPVCRetentionPolicy returns the policy of the pods removed by current deploy, WhenDeleted is used if the workload
+// is scaled in to zero, since it is deleted after that. The policy of the deploy is used if set, otherwise the one
+// recorded in the workload.
+func getPVCRetentionPolicy(idl *internaldeploy.Deploy, obj workload.Object) tritonappsv1alpha1.PersistentVolumeClaimRetentionPolicyType {
+	policy := idl.Spec.Application.PersistentVolumeClaimRetentionPolicy
+	if policy == nil {
+		policy = workload.PVCRetentionPolicyOf(obj)
+	}
+	if policy == nil {
+		return ""
+	}
+
+	if idl.Spec.Application.Replicas != nil && *idl.Spec.Application.Replicas == 0 {
+		return policy.WhenDeleted
+	}
+	return policy.WhenScaled
+}
+
+// retainPVCs applies the retention policy to the PVCs of the pods which are going to be removed by a scale in.
+// PVCs to retain are released from the workload at once, since a CloneSet deletes the PVCs of a removed pod with it,
+// and a released PVC is harmless if the scale in is canceled. PVCs to delete are returned, they should be deleted only
+// after the workload is patched, so that no pod left running loses its PVC.
+func (r *DeployFlowReconciler) retainPVCs(idl *internaldeploy.Deploy, pods []string) ([]string, error) {
+	if idl.Spec.Action != setting.ScaleIn || len(pods) == 0 {
+		return nil, nil
+	}
+
+	obj, found, err := fetcher.GetWorkloadInCacheOwnedByDeploy(idl.Unwrap(), r.Client)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	templates := workload.VolumeClaimTemplates(obj)
+	if len(templates) == 0 {
+		return nil, nil
+	}
+	policy := getPVCRetentionPolicy(idl, obj)
+	if policy == "" {
+		return nil, nil
+	}
+
+	names := make(map[string]bool, len(templates))
+	for _, t := range templates {
+		names[t.Name] = true
+	}
+
+	var pvcs []string
+	for _, p := range pods {
+		pod, found, err := fetcher.GetPodInCache(idl.Namespace, p, r.Client)
+		if err != nil {
+			return nil, err
+		} else if !found {
+			continue
+		}
+
+		for _, v := range pod.Spec.Volumes {
+			// the volume of a PVC template is named after the template.
+			if v.PersistentVolumeClaim != nil && names[v.Name] {
+				pvcs = append(pvcs, v.PersistentVolumeClaim.ClaimName)
+			}
+		}
+	}
+
+	if policy == tritonappsv1alpha1.DeletePersistentVolumeClaimRetentionPolicyType {
+		return pvcs, nil
+	}
+
+	for _, pvc := range pvcs {
+		r.logger.WithField("deploy", idl).Infof("Apply %s policy to PVC %s", policy, pvc)
+		if err := r.applyPVCPolicy(idl.Namespace, pvc, policy); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// deletePVCs deletes the PVCs returned by retainPVCs once the workload is patched. It is called once in a batch,
+// a failure is reported by an event instead of requeuing the batch.
+func (r *DeployFlowReconciler) deletePVCs(idl *internaldeploy.Deploy, pvcs []string) {
+	for _, pvc := range pvcs {
+		r.logger.WithField("deploy", idl).Infof("Apply %s policy to PVC %s", tritonappsv1alpha1.DeletePersistentVolumeClaimRetentionPolicyType, pvc)
+		if err := r.applyPVCPolicy(idl.Namespace, pvc, tritonappsv1alpha1.DeletePersistentVolumeClaimRetentionPolicyType); err != nil {
+			r.logger.WithField("deploy", idl).WithError(err).Errorf("Failed to delete PVC %s", pvc)
+			r.recorder.Event(idl.Unwrap(), corev1.EventTypeWarning, eventReasonPVCRetention, fmt.Sprintf("Failed to delete PVC %s: %s", pvc, err))
+		}
+	}
+}
+
+func (r *DeployFlowReconciler) applyPVCPolicy(ns, name string, policy tritonappsv1alpha1.PersistentVolumeClaimRetentionPolicyType) error {
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: name}, pvc); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	switch policy {
+	case tritonappsv1alpha1.DeletePersistentVolumeClaimRetentionPolicyType:
+		// the PVC is kept by its protection finalizer until the pod is gone.
+		return client.IgnoreNotFound(r.Delete(context.TODO(), pvc))
+	case tritonappsv1alpha1.RetainPersistentVolumeClaimRetentionPolicyType:
+		if len(pvc.OwnerReferences) == 0 && pvc.Labels[kruiseappsv1alpha1.CloneSetInstanceID] == "" {
+			return nil
+		}
+		// a CloneSet deletes the PVCs of a removed instance, and the PVCs it owns are collected with it.
+		pvc.OwnerReferences = nil
+		delete(pvc.Labels, kruiseappsv1alpha1.CloneSetInstanceID)
+		return r.Update(context.TODO(), pvc)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployflow
+
+import (
+	"context"
+	"testing"
+
+	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	"github.com/triton-io/triton/pkg/setting"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestPVCRetentionPolicyOnScaleIn(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		// deleted is whether the PVC is deleted, it is released from the CloneSet otherwise.
+		deleted bool
+	}{
+		{name: "delete", policy: `{"whenScaled":"Delete"}`, deleted: true},
+		{name: "retain", policy: `{"whenScaled":"Retain"}`, deleted: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newBlockedHookServer()
+			defer server.Close()
+
+			scheme := newTestScheme()
+			deploy, cs := newScaleInDeploy(scheme, server.URL)
+			cs.Annotations = map[string]string{setting.PVCRetentionPolicyAnnotation: tt.policy}
+			cs.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "data"}}}
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "app-a",
+					Labels:    map[string]string{setting.AppIDLabel: "1", setting.GroupIDLabel: "1"},
+				},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{{
+						Name: "data",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-app-a"},
+						},
+					}},
+				},
+			}
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       "default",
+					Name:            "data-app-a",
+					Labels:          map[string]string{kruiseappsv1alpha1.CloneSetInstanceID: "a"},
+					OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(cs, kruiseappsv1alpha1.SchemeGroupVersion.WithKind("CloneSet"))},
+				},
+			}
+
+			r := newTestReconciler(scheme, deploy.DeepCopy(), cs, pod, pvc)
+			idl := internaldeploy.FromDeploy(deploy)
+
+			if err := r.processBakingBatch(idl); err == nil {
+				t.Fatal("expected the batch to be blocked by the hook")
+			}
+
+			got := &corev1.PersistentVolumeClaim{}
+			err := r.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "data-app-a"}, got)
+			if tt.deleted {
+				if !apierrors.IsNotFound(err) {
+					t.Fatalf("expected PVC to be deleted, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.OwnerReferences) != 0 || got.Labels[kruiseappsv1alpha1.CloneSetInstanceID] != "" {
+				t.Errorf("expected PVC to be released, got owners %v, labels %v", got.OwnerReferences, got.Labels)
+			}
+		})
+	}
+}
+
+func TestGetPVCRetentionPolicy(t *testing.T) {
+	var replicas, zero int32 = 2, 0
+	cs := &kruiseappsv1alpha1.CloneSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{setting.PVCRetentionPolicyAnnotation: `{"whenScaled":"Retain","whenDeleted":"Delete"}`},
+		},
+	}
+	deploy := func(r *int32, p *tritonappsv1alpha1.PersistentVolumeClaimRetentionPolicy) *internaldeploy.Deploy {
+		return internaldeploy.FromDeploy(&tritonappsv1alpha1.DeployFlow{
+			Spec: tritonappsv1alpha1.DeployFlowSpec{
+				Action:      setting.ScaleIn,
+				Application: &tritonappsv1alpha1.ApplicationSpec{Replicas: r, PersistentVolumeClaimRetentionPolicy: p},
+			},
+		})
+	}
+
+	tests := []struct {
+		name string
+		idl  *internaldeploy.Deploy
+		obj  *kruiseappsv1alpha1.CloneSet
+		want tritonappsv1alpha1.PersistentVolumeClaimRetentionPolicyType
+	}{
+		{"scaled in", deploy(&replicas, nil), cs, tritonappsv1alpha1.RetainPersistentVolumeClaimRetentionPolicyType},
+		{"scaled in to zero", deploy(&zero, nil), cs, tritonappsv1alpha1.DeletePersistentVolumeClaimRetentionPolicyType},
+		{"deploy policy first", deploy(&replicas, &tritonappsv1alpha1.PersistentVolumeClaimRetentionPolicy{
+			WhenScaled: tritonappsv1alpha1.DeletePersistentVolumeClaimRetentionPolicyType,
+		}), cs, tritonappsv1alpha1.DeletePersistentVolumeClaimRetentionPolicyType},
+		{"not set", deploy(&replicas, nil), &kruiseappsv1alpha1.CloneSet{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getPVCRetentionPolicy(tt.idl, tt.obj); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
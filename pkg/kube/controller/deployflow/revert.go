@@ -0,0 +1,360 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployflow
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	terrors "github.com/triton-io/triton/pkg/errors"
+	"github.com/triton-io/triton/pkg/kube/fetcher"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	internalpod "github.com/triton-io/triton/pkg/kube/types/pod"
+	"github.com/triton-io/triton/pkg/kube/types/workload"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// startRevert moves a canceled deploy to Reverting, the old pods pulled out in current batch are pulled in again.
+// A revert works for a CloneSet only, see Deploy.Revertable, other workloads never reach here.
+func (r *DeployFlowReconciler) startRevert(idl *internaldeploy.Deploy) error {
+	logger := r.logger.WithField("deploy", idl)
+
+	cs, found, err := fetcher.GetCloneSetInCacheOwnedByDeploy(idl.Unwrap(), r.Client)
+	if err != nil || !found {
+		logger.WithError(err).Error("unable to fetch CloneSet")
+		return fmt.Errorf("unable to fetch CloneSet: %w", err)
+	}
+
+	// the CloneSet may be paused by a failed batch.
+	if err := r.resumeWorkload(idl); err != nil {
+		logger.WithError(err).Error("Failed to resume workload")
+		return err
+	}
+
+	pods, err := r.getPulledOutPods(idl)
+	if err != nil {
+		return err
+	}
+	if len(pods) > 0 {
+		logger.Infof("Pulling in old pods %s again", strings.Join(podNames(pods), ", "))
+		if err := r.pullInPods(idl, pods); err != nil {
+			logger.WithError(err).Error("Failed to pull in old pods")
+			return err
+		}
+	}
+
+	logger.Infof("Deploy canceled, start to revert %d updated pods", cs.Status.UpdatedReplicas)
+	idl.StartRevert(int(cs.Status.UpdatedReplicas))
+	r.recorder.Event(idl.Unwrap(), corev1.EventTypeNormal, eventReasonRevert,
+		fmt.Sprintf("Reverting %d pods of revision %s", cs.Status.UpdatedReplicas, cs.Status.UpdateRevision))
+
+	return nil
+}
+
+// processRevertingDeploy reverts updated pods in batches, every batch goes through:
+// 1. Pending: scale out the CloneSet, pods of the previous revision are created since the partition is raised.
+// 2. Smoking: wait for new pods to be ContainersReady, and then pull them in.
+// 3. Smoked: wait for new pods to be enabled by the traffic provider.
+// 4. Baking: pull out the updated pods, and remove them by scaling in the CloneSet.
+// 5. Baked: wait for the updated pods to be removed.
+// It relies on the partition of a CloneSet, so only a CloneSet is reverted.
+func (r *DeployFlowReconciler) processRevertingDeploy(idl *internaldeploy.Deploy) error {
+	logger := r.logger.WithField("deploy", idl)
+
+	cs, found, err := fetcher.GetCloneSetInCacheOwnedByDeploy(idl.Unwrap(), r.Client)
+	if err != nil {
+		logger.WithError(err).Error("unable to fetch CloneSet")
+		return fmt.Errorf("unable to fetch CloneSet: %w", err)
+	} else if !found {
+		logger.Warn("CloneSet is gone, nothing to revert")
+		idl.FinishRevert()
+		return nil
+	}
+
+	if cs.GetGeneration() != cs.Status.ObservedGeneration {
+		logger.Info("CloneSet status is not updated yet, checking again")
+		return terrors.NewRevertInProgressError(time.Second)
+	}
+
+	switch idl.Status.Revert.Phase {
+	case tritonappsv1alpha1.BatchPending:
+		return r.startRevertBatch(idl, cs)
+	case tritonappsv1alpha1.BatchSmoking:
+		return r.processRevertSmokingBatch(idl, cs)
+	case tritonappsv1alpha1.BatchSmoked:
+		return r.processRevertSmokedBatch(idl)
+	case tritonappsv1alpha1.BatchBaking:
+		return r.processRevertBakingBatch(idl, cs)
+	case tritonappsv1alpha1.BatchBaked:
+		if cs.Status.Replicas != *idl.Spec.Application.Replicas {
+			logger.Info("Updated pods are not removed yet, checking again")
+			return terrors.NewRevertInProgressError(time.Second)
+		}
+		return r.startRevertBatch(idl, cs)
+	}
+
+	return nil
+}
+
+func (r *DeployFlowReconciler) startRevertBatch(idl *internaldeploy.Deploy, cs *kruiseappsv1alpha1.CloneSet) error {
+	logger := r.logger.WithField("deploy", idl)
+
+	replicas := int(*idl.Spec.Application.Replicas)
+	updated := int(cs.Status.UpdatedReplicas)
+	current := int(*cs.Spec.Replicas)
+
+	if updated == 0 {
+		// remove the surplus pods if any, and keep all pods in the previous revision.
+		patchBytes := []byte(fmt.Sprintf(`{"spec":{"replicas":%d,"updateStrategy":{"partition":%d}}}`, replicas, replicas))
+		if err := PatchWorkload(idl.Unwrap(), patchBytes, r.Client); err != nil {
+			return err
+		}
+
+		logger.Info("All updated pods are reverted, the deploy is canceled")
+		idl.FinishRevert()
+		r.recorder.Event(idl.Unwrap(), corev1.EventTypeNormal, eventReasonRevert, "All updated pods are reverted")
+		return nil
+	}
+
+	// the surplus pods are created in the canceled batch, remove them without creating pods of the previous revision.
+	if surplus := current - replicas; surplus > 0 {
+		batchSize := min(surplus, updated)
+		idl.StartRevertBatch(batchSize, replicas-(updated-batchSize), tritonappsv1alpha1.BatchBaking)
+		logger.Infof("Revert batch %d is started, removing %d surplus updated pods", idl.Status.Revert.Batch, batchSize)
+		return nil
+	}
+
+	// the partition keeps the updated pods as they are, so that the pods created are of the previous revision.
+	batchSize := min(idl.RevertBatchSize(), updated)
+	partition := replicas - (updated - batchSize)
+	patchBytes := []byte(fmt.Sprintf(`{"spec":{"replicas":%d,"updateStrategy":{"partition":%d}}}`, replicas+batchSize, partition))
+	if err := PatchWorkload(idl.Unwrap(), patchBytes, r.Client); err != nil {
+		return err
+	}
+
+	idl.StartRevertBatch(batchSize, partition, tritonappsv1alpha1.BatchSmoking)
+	logger.Infof("Revert batch %d is started, creating %d pods of the previous revision", idl.Status.Revert.Batch, batchSize)
+
+	return nil
+}
+
+func (r *DeployFlowReconciler) processRevertSmokingBatch(idl *internaldeploy.Deploy, cs *kruiseappsv1alpha1.CloneSet) error {
+	logger := r.logger.WithField("deploy", idl)
+	rv := idl.Status.Revert
+
+	pods, err := r.listPods(idl)
+	if err != nil {
+		return err
+	}
+
+	newPods := make([]tritonappsv1alpha1.PodInfo, 0, rv.BatchSize)
+	ready := true
+	for _, p := range pods {
+		if p.Labels[appsv1.ControllerRevisionHashLabelKey] == cs.Status.UpdateRevision || p.CreationTimestamp.Before(&rv.BatchStartedAt) {
+			continue
+		}
+
+		ip := internalpod.FromPod(p)
+		if ip.Failed() {
+			logger.Warnf("Pod %s of the previous revision failed, it needs to be fixed to continue the revert", p.Name)
+		}
+		ready = ready && ip.ContainersReady()
+		newPods = append(newPods, tritonappsv1alpha1.PodInfo{
+			Name:  ip.Name,
+			IP:    ip.GetPodIP(),
+			Port:  ip.GetAppPort(),
+			Phase: string(ip.GetPhase()),
+		})
+	}
+
+	if len(newPods) < rv.BatchSize || !ready {
+		logger.Info("Pods of the previous revision are not ready yet, checking again")
+		return terrors.NewRevertInProgressError(time.Second)
+	}
+
+	if !idl.SkipPullIn() {
+		for _, p := range newPods {
+			if err := internalpod.SetPodReadinessGate(idl.Namespace, p.Name, r.Client); err != nil {
+				logger.WithError(err).Errorf("Failed to update pod %s", p.Name)
+				return err
+			}
+		}
+
+		logger.Infof("Pulling in pods %s", strings.Join(podNames(newPods), ", "))
+		if err := r.pullInPods(idl, newPods); err != nil {
+			logger.WithError(err).Error("Failed to pull in pods")
+			return err
+		}
+	}
+
+	rv.Pods = newPods
+	rv.PulledInAt = metav1.Now()
+	rv.Phase = tritonappsv1alpha1.BatchSmoked
+
+	return nil
+}
+
+func (r *DeployFlowReconciler) processRevertSmokedBatch(idl *internaldeploy.Deploy) error {
+	logger := r.logger.WithField("deploy", idl)
+	rv := idl.Status.Revert
+
+	if !idl.SkipPullIn() && time.Since(rv.PulledInAt.Time) < idl.PullInTimeout() {
+		provider, err := r.trafficProvider(idl)
+		if err != nil {
+			return err
+		}
+
+		enabled, err := provider.IsEnabled(idl, rv.Pods)
+		if err != nil {
+			return err
+		}
+		if len(enabled) < len(rv.Pods) {
+			logger.Info("Not all pods of the previous revision are pulled in, checking again")
+			return terrors.NewInstanceNotUpError(500 * time.Millisecond)
+		}
+	}
+
+	rv.Phase = tritonappsv1alpha1.BatchBaking
+
+	return nil
+}
+
+func (r *DeployFlowReconciler) processRevertBakingBatch(idl *internaldeploy.Deploy, cs *kruiseappsv1alpha1.CloneSet) error {
+	logger := r.logger.WithField("deploy", idl)
+	rv := idl.Status.Revert
+
+	if rv.PulledOutAt.IsZero() {
+		ptd, err := r.getUpdatedPodsForDeletion(idl, cs)
+		if err != nil {
+			return err
+		}
+		if len(ptd) > rv.BatchSize {
+			ptd = ptd[:rv.BatchSize]
+		}
+
+		if err := r.deregisterPods(idl, ptd); err != nil {
+			return err
+		}
+		rv.PulledOutPods = podNames(ptd)
+		rv.PulledOutAt = metav1.Now()
+	}
+
+	ptd, err := r.getExistingPods(idl, rv.PulledOutPods)
+	if err != nil {
+		return err
+	}
+
+	drained, err := r.podsDrained(idl, ptd)
+	if err != nil {
+		return err
+	}
+	if !drained {
+		if time.Since(rv.PulledOutAt.Time) < drainTimeout {
+			logger.Info("Updated pods are not drained yet, checking again")
+			return terrors.NewInstanceNotDrainedError(time.Second)
+		}
+		logger.Warnf("Timeout waiting for pods %s to be drained, remove them anyway", strings.Join(podNames(ptd), ", "))
+	}
+
+	if err := r.removeDisabledPods(idl, ptd); err != nil {
+		return err
+	}
+
+	patchBytes, err := withPodsToDelete(
+		[]byte(fmt.Sprintf(`{"spec":{"replicas":%d,"updateStrategy":{"partition":%d}}}`, *idl.Spec.Application.Replicas, rv.Partition)),
+		rv.PulledOutPods)
+	if err != nil {
+		return err
+	}
+	if err := PatchWorkload(idl.Unwrap(), patchBytes, r.Client); err != nil {
+		return err
+	}
+
+	logger.Infof("Revert batch %d is finished", rv.Batch)
+	idl.FinishRevertBatch()
+
+	return nil
+}
+
+// getUpdatedPodsForDeletion returns the updated pods to be removed in a revert, not ready pods come first.
+func (r *DeployFlowReconciler) getUpdatedPodsForDeletion(idl *internaldeploy.Deploy, cs *kruiseappsv1alpha1.CloneSet) ([]tritonappsv1alpha1.PodInfo, error) {
+	pods, err := r.listPods(idl)
+	if err != nil {
+		return nil, err
+	}
+
+	readyPods := make([]tritonappsv1alpha1.PodInfo, 0, len(pods))
+	notReadyPods := make([]tritonappsv1alpha1.PodInfo, 0, len(pods))
+	for _, p := range pods {
+		if p.Labels[appsv1.ControllerRevisionHashLabelKey] != cs.Status.UpdateRevision {
+			continue
+		}
+
+		ip := internalpod.FromPod(p)
+		pi := tritonappsv1alpha1.PodInfo{
+			Name:  ip.Name,
+			IP:    ip.GetPodIP(),
+			Port:  ip.GetAppPort(),
+			Phase: string(ip.GetPhase()),
+		}
+		if ip.Ready() {
+			readyPods = append(readyPods, pi)
+		} else {
+			notReadyPods = append(notReadyPods, pi)
+		}
+	}
+
+	return append(notReadyPods, readyPods...), nil
+}
+
+// listPods returns the pods of the application which are not being deleted, sorted by name.
+func (r *DeployFlowReconciler) listPods(idl *internaldeploy.Deploy) ([]*corev1.Pod, error) {
+	s := workload.GetDefaultSelector(idl.Spec.Application.AppID, idl.Spec.Application.GroupID)
+	pods := &corev1.PodList{}
+	if err := r.List(context.TODO(), pods, client.InNamespace(idl.Namespace), client.MatchingLabelsSelector{Selector: s.AsSelector()}); err != nil {
+		return nil, fmt.Errorf("failed to fetch pods: %w", err)
+	}
+
+	// keep the order stable, so that the same pods are picked in every try.
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+
+	ps := make([]*corev1.Pod, 0, len(pods.Items))
+	for i := range pods.Items {
+		if pods.Items[i].DeletionTimestamp.IsZero() {
+			ps = append(ps, &pods.Items[i])
+		}
+	}
+
+	return ps, nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployflow
+
+import (
+	"strconv"
+	"strings"
+
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	internalpod "github.com/triton-io/triton/pkg/kube/types/pod"
+	"github.com/triton-io/triton/pkg/routing"
+	"github.com/triton-io/triton/pkg/setting"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// routeBatch labels the pods in current batch, routes the matched requests to the canary batch,
+// and shifts the weight of current batch to the updated pods.
+func (r *DeployFlowReconciler) routeBatch(idl *internaldeploy.Deploy) error {
+	rt := idl.Routing()
+	c := idl.CurrentBatchInfo()
+	if rt == nil || c == nil {
+		return nil
+	}
+	weight := idl.BatchWeight(c)
+	if !c.Canary && weight == nil {
+		return nil
+	}
+
+	logger := r.logger.WithField("deploy", idl)
+	if weight == nil {
+		pods := idl.CurrentBatchPods()
+		logger.Infof("Routing matched requests to pods %s", strings.Join(podNames(pods), ", "))
+		for _, p := range pods {
+			if err := internalpod.SetPodLabel(idl.Namespace, p.Name, setting.CanaryLabel, "true", r.Client); err != nil {
+				return err
+			}
+		}
+	} else {
+		logger.Infof("Shifting %d%% of requests to the updated pods", *weight)
+		if err := r.labelPods(idl); err != nil {
+			return err
+		}
+	}
+
+	if err := routing.New(r.Client, idl.Unwrap(), rt).WithWeight(weight).Ensure(); err != nil {
+		return err
+	}
+	idl.SetWeight(weight)
+
+	return nil
+}
+
+// labelPods labels the pods in batches as canary ones, and the pods not updated yet as stable ones.
+func (r *DeployFlowReconciler) labelPods(idl *internaldeploy.Deploy) error {
+	updated := sets.NewString()
+	for _, c := range idl.Status.Conditions {
+		updated.Insert(podNames(c.Pods)...)
+	}
+
+	pods, err := r.listPods(idl)
+	if err != nil {
+		return err
+	}
+	for _, p := range pods {
+		value := strconv.FormatBool(updated.Has(p.Name))
+		if p.Labels[setting.CanaryLabel] == value {
+			continue
+		}
+		if err := internalpod.SetPodLabel(idl.Namespace, p.Name, setting.CanaryLabel, value, r.Client); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeCanaryRoute restores the stable route, removes the canary route and the labels of pods,
+// so that all pods are served through the stable Service.
+func (r *DeployFlowReconciler) removeCanaryRoute(idl *internaldeploy.Deploy) error {
+	rt := idl.Routing()
+	if rt == nil {
+		return nil
+	}
+
+	if err := routing.New(r.Client, idl.Unwrap(), rt).Remove(); err != nil {
+		return err
+	}
+
+	return r.unlabelPods(idl)
+}
+
+// unlabelPods removes the canary labels of pods.
+func (r *DeployFlowReconciler) unlabelPods(idl *internaldeploy.Deploy) error {
+	pods, err := r.listPods(idl)
+	if err != nil {
+		return err
+	}
+	for _, p := range pods {
+		if _, ok := p.Labels[setting.CanaryLabel]; !ok {
+			continue
+		}
+		if err := internalpod.SetPodLabel(idl.Namespace, p.Name, setting.CanaryLabel, "", r.Client); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	return nil
+}
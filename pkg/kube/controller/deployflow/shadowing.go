@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployflow
+
+import (
+	"strings"
+	"time"
+
+	terrors "github.com/triton-io/triton/pkg/errors"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	internalpod "github.com/triton-io/triton/pkg/kube/types/pod"
+	"github.com/triton-io/triton/pkg/routing"
+	"github.com/triton-io/triton/pkg/setting"
+)
+
+// startShadowing labels the pods in the canary batch, and mirrors requests sent to the stable Service to them
+// before they are pulled in.
+func (r *DeployFlowReconciler) startShadowing(idl *internaldeploy.Deploy) error {
+	logger := r.logger.WithField("deploy", idl)
+
+	pods := idl.CurrentBatchPods()
+	for _, p := range pods {
+		if err := internalpod.SetPodLabel(idl.Namespace, p.Name, setting.CanaryLabel, "true", r.Client); err != nil {
+			return err
+		}
+	}
+
+	s := idl.Shadowing()
+	if err := routing.NewMirror(r.Client, idl.Unwrap(), s).Ensure(); err != nil {
+		logger.WithError(err).Error("failed to mirror requests")
+		return err
+	}
+
+	logger.Infof("Mirroring requests of service %s to pods %s", s.Service, strings.Join(podNames(pods), ", "))
+	idl.MarkCurrentBatchAsShadowing()
+
+	return nil
+}
+
+// processShadowingBatch stops the mirror once the shadowing is done, and pulls in the batch as a smoked one.
+func (r *DeployFlowReconciler) processShadowingBatch(idl *internaldeploy.Deploy) error {
+	logger := r.logger.WithField("deploy", idl)
+
+	pulledInAt := idl.CurrentBatchPullInAt()
+	if pulledInAt.IsZero() {
+		if remaining := idl.ShadowRemaining(idl.CurrentBatchInfo()); remaining > 0 {
+			logger.Infof("Batch %d is shadowing, %s left", idl.CurrentBatchNumber(), remaining.Round(time.Second))
+			return terrors.NewShadowingNotFinishedError(remaining)
+		}
+		// without an analysis, the canary waits to be moved forward as a smoked one.
+		if !idl.MoveForward() && idl.BatchAnalysis() == nil {
+			return nil
+		}
+
+		logger.Info("Shadowing is done, stop mirroring requests")
+		if err := routing.NewMirror(r.Client, idl.Unwrap(), idl.Shadowing()).Stop(); err != nil {
+			logger.WithError(err).Error("failed to stop mirroring requests")
+			return err
+		}
+	}
+
+	return r.processSmokedBatch(idl)
+}
+
+// removeShadowing stops the mirror and removes the canary Service and the labels of pods.
+func (r *DeployFlowReconciler) removeShadowing(idl *internaldeploy.Deploy) error {
+	s := idl.Shadowing()
+	if s == nil {
+		return nil
+	}
+
+	if err := routing.NewMirror(r.Client, idl.Unwrap(), s).Remove(); err != nil {
+		return err
+	}
+
+	return r.unlabelPods(idl)
+}
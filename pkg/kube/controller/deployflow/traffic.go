@@ -0,0 +1,170 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployflow
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	"github.com/triton-io/triton/pkg/traffic"
+)
+
+func init() {
+	flag.StringVar(&defaultTrafficProvider, "traffic-provider", defaultTrafficProvider,
+		fmt.Sprintf("Default traffic provider used to pull pods in and out, candidates are %s. "+
+			"It can be overridden by .spec.updateStrategy.trafficProvider of a DeployFlow.", strings.Join(traffic.Providers(), ", ")))
+	flag.DurationVar(&drainTimeout, "traffic-drain-timeout", drainTimeout, "Max time to wait for old pods to be drained before deleting them.")
+}
+
+var (
+	defaultTrafficProvider = traffic.Readiness
+	drainTimeout           = 30 * time.Second
+)
+
+// trafficProvider returns the provider specified in the DeployFlow, fallback to the default one.
+func (r *DeployFlowReconciler) trafficProvider(idl *internaldeploy.Deploy) (traffic.Provider, error) {
+	name := idl.TrafficProvider()
+	if name == "" {
+		name = defaultTrafficProvider
+	}
+
+	return traffic.New(name, r.Client)
+}
+
+// pullIn registers pods in current batch to the traffic provider.
+func (r *DeployFlowReconciler) pullIn(idl *internaldeploy.Deploy) error {
+	return r.pullInPods(idl, idl.CurrentBatchPods())
+}
+
+// pullInPods registers the pods to the traffic provider, and enables them if the provider can switch them on.
+func (r *DeployFlowReconciler) pullInPods(idl *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) error {
+	if len(pods) == 0 {
+		return nil
+	}
+
+	provider, err := r.trafficProvider(idl)
+	if err != nil {
+		return err
+	}
+
+	if err := provider.Register(idl, pods); err != nil {
+		return err
+	}
+
+	if switcher, ok := provider.(traffic.Switcher); ok {
+		return switcher.Enable(idl, pods)
+	}
+
+	return nil
+}
+
+// deregisterPods stops the traffic to pods which are going to be deleted,
+// pods are disabled instead if the provider can switch them off, and deregistered after they are drained.
+func (r *DeployFlowReconciler) deregisterPods(idl *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) error {
+	if len(pods) == 0 {
+		return nil
+	}
+
+	provider, err := r.trafficProvider(idl)
+	if err != nil {
+		return err
+	}
+
+	logger := r.logger.WithField("deploy", idl)
+	if switcher, ok := provider.(traffic.Switcher); ok {
+		logger.Infof("Disabling pods %s", strings.Join(podNames(pods), ", "))
+		return switcher.Disable(idl, pods)
+	}
+
+	logger.Infof("Deregistering pods %s", strings.Join(podNames(pods), ", "))
+	return provider.Deregister(idl, pods)
+}
+
+// removeDisabledPods deregisters the pods disabled in deregisterPods.
+func (r *DeployFlowReconciler) removeDisabledPods(idl *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) error {
+	if len(pods) == 0 {
+		return nil
+	}
+
+	provider, err := r.trafficProvider(idl)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := provider.(traffic.Switcher); !ok {
+		return nil
+	}
+
+	r.logger.WithField("deploy", idl).Infof("Deregistering pods %s", strings.Join(podNames(pods), ", "))
+	return provider.Deregister(idl, pods)
+}
+
+// podsDrained returns true if the provider stops the traffic to the pods.
+func (r *DeployFlowReconciler) podsDrained(idl *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) (bool, error) {
+	if len(pods) == 0 {
+		return true, nil
+	}
+
+	provider, err := r.trafficProvider(idl)
+	if err != nil {
+		return false, err
+	}
+
+	// traffic is stopped synchronously in Deregister.
+	drainer, ok := provider.(traffic.Drainer)
+	if !ok {
+		return true, nil
+	}
+
+	return drainer.Drained(idl, pods)
+}
+
+// withPodsToDelete adds .spec.scaleStrategy.podsToDelete into the patch, so that the pods pulled out
+// are exactly the ones removed by CloneSet.
+func withPodsToDelete(patchBytes []byte, pods []string) ([]byte, error) {
+	if len(patchBytes) == 0 || len(pods) == 0 {
+		return patchBytes, nil
+	}
+
+	patch := map[string]interface{}{}
+	if err := json.Unmarshal(patchBytes, &patch); err != nil {
+		return nil, err
+	}
+
+	spec, ok := patch["spec"].(map[string]interface{})
+	if !ok {
+		spec = map[string]interface{}{}
+		patch["spec"] = spec
+	}
+	spec["scaleStrategy"] = map[string]interface{}{"podsToDelete": pods}
+
+	return json.Marshal(patch)
+}
+
+func podNames(pods []tritonappsv1alpha1.PodInfo) []string {
+	names := make([]string, 0, len(pods))
+	for i := range pods {
+		names = append(names, pods[i].Name)
+	}
+
+	return names
+}
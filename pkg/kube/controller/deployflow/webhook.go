@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployflow
+
+import (
+	"fmt"
+	"time"
+
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	terrors "github.com/triton-io/triton/pkg/errors"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	"github.com/triton-io/triton/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const hookRetryInterval = 5 * time.Second
+
+// runHooks calls the hooks of current batch in order, it returns true once all of them succeed.
+// Succeeded hooks are never called again in the same batch.
+func (r *DeployFlowReconciler) runHooks(idl *internaldeploy.Deploy, stage tritonappsv1alpha1.WebhookStage, hooks []tritonappsv1alpha1.Webhook) (bool, error) {
+	logger := r.logger.WithField("deploy", idl)
+
+	for i := range hooks {
+		h := &hooks[i]
+		status := idl.CurrentBatchHook(stage, h.Name)
+		if status.Succeeded {
+			continue
+		}
+
+		logger.Infof("Calling %s hook %s", stage, h.Name)
+		err := webhook.Call(h, hookPayload(idl, stage))
+		status.Attempts++
+		status.CalledAt = metav1.Now()
+		if err == nil {
+			status.Succeeded = true
+			status.Message = ""
+			idl.SetCurrentBatchHook(status)
+			continue
+		}
+
+		logger.WithError(err).Warnf("%s hook %s failed, attempts: %d", stage, h.Name, status.Attempts)
+		status.Message = err.Error()
+		r.recorder.Event(idl.Unwrap(), corev1.EventTypeWarning, eventReasonWebhook, fmt.Sprintf("%s hook %s failed: %s", stage, h.Name, err))
+
+		if h.FailurePolicy == "" || h.FailurePolicy == tritonappsv1alpha1.WebhookBlock || status.Attempts <= h.Retries {
+			idl.SetCurrentBatchHook(status)
+			return false, terrors.NewWebhookFailedError(hookRetryInterval)
+		}
+
+		status.ObservedGeneration = idl.Generation
+		idl.SetCurrentBatchHook(status)
+		return false, r.failHook(idl, h)
+	}
+
+	return true, nil
+}
+
+// failHook pauses the deploy or fails current batch according to the failure policy of the hook.
+func (r *DeployFlowReconciler) failHook(idl *internaldeploy.Deploy, h *tritonappsv1alpha1.Webhook) error {
+	logger := r.logger.WithField("deploy", idl)
+
+	if h.FailurePolicy == tritonappsv1alpha1.WebhookFail {
+		logger.Infof("Current batch failed due to failure of hook %s", h.Name)
+		return r.failCurrentBatch(idl)
+	}
+
+	if err := r.pauseWorkload(idl); err != nil {
+		logger.WithError(err).Error("Failed to pause workload")
+		return err
+	}
+
+	logger.Infof("Paused deploy due to failure of hook %s", h.Name)
+	idl.MarkAsPaused()
+
+	return nil
+}
+
+func hookPayload(idl *internaldeploy.Deploy, stage tritonappsv1alpha1.WebhookStage) *webhook.Payload {
+	app := idl.Spec.Application
+	p := &webhook.Payload{
+		Stage:          stage,
+		Namespace:      idl.Namespace,
+		Name:           idl.Name,
+		Action:         idl.Spec.Action,
+		AppID:          app.AppID,
+		GroupID:        app.GroupID,
+		AppName:        app.AppName,
+		CloneSetName:   app.CloneSetName,
+		UpdateRevision: idl.GetUpdateRevision(),
+		Batches:        idl.Status.Batches,
+	}
+	if c := idl.CurrentBatchInfo(); c != nil {
+		p.Batch = c.Batch
+		p.BatchSize = c.BatchSize
+		p.Canary = c.Canary
+		p.Pods = c.Pods
+	}
+
+	return p
+}
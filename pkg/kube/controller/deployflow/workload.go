@@ -0,0 +1,366 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployflow
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	"github.com/triton-io/triton/pkg/kube/fetcher"
+	internalpod "github.com/triton-io/triton/pkg/kube/types/pod"
+	"github.com/triton-io/triton/pkg/kube/types/workload"
+	"github.com/triton-io/triton/pkg/setting"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch
+
+// podDeletionCostAnnotation makes the ReplicaSet controller remove the pods of lower cost first when it is scaled in.
+const podDeletionCostAnnotation = "controller.kubernetes.io/pod-deletion-cost"
+
+// getWorkload reads the workload of the deploy from the API server, ex: a CloneSet, an Advanced StatefulSet or a Deployment.
+func (r *DeployFlowReconciler) getWorkload(idl *internaldeploy.Deploy) (workload.Object, error) {
+	obj, err := workload.New(idl.ApplicationType())
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.reader.Get(context.TODO(), types.NamespacedName{Namespace: idl.Namespace, Name: idl.Spec.Application.CloneSetName}, obj)
+	return obj, err
+}
+
+func (r *DeployFlowReconciler) removeWorkloadOwner(idl *internaldeploy.Deploy) error {
+	obj, err := r.getWorkload(idl)
+	if err != nil {
+		return err
+	}
+
+	if !metav1.IsControlledBy(obj, idl.Unwrap()) {
+		return nil
+	}
+
+	// TODO: does the workload have other owners?
+	obj.SetOwnerReferences(nil)
+
+	return r.Update(context.TODO(), obj)
+}
+
+func (r *DeployFlowReconciler) setWorkloadOwner(idl *internaldeploy.Deploy) error {
+	obj, err := r.getWorkload(idl)
+	if err != nil {
+		return err
+	}
+
+	err = controllerutil.SetControllerReference(idl.Unwrap(), obj, r.Scheme)
+	if err != nil {
+		return err
+	}
+
+	return r.Update(context.TODO(), obj)
+}
+
+func (r *DeployFlowReconciler) removeWorkloadOwnerWithRetry(idl *internaldeploy.Deploy) error {
+	if err := r.removeWorkloadOwner(idl); err != nil {
+		if apierrors.IsConflict(err) {
+			return r.removeWorkloadOwner(idl)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *DeployFlowReconciler) takeOwnershipOfWorkload(idl *internaldeploy.Deploy) error {
+	obj, err := r.getWorkload(idl)
+	if err != nil {
+		return err
+	}
+
+	//obj.SetOwnerReferences(nil)
+
+	err = controllerutil.SetControllerReference(idl.Unwrap(), obj, r.Scheme)
+	if err != nil {
+		return err
+	}
+
+	// resume the workload when taking ownership
+	setPaused(obj, false)
+
+	return r.Update(context.TODO(), obj)
+}
+
+func (r *DeployFlowReconciler) pauseWorkload(idl *internaldeploy.Deploy) error {
+	return r.pauseOrResumeWorkload(idl, true)
+}
+
+func (r *DeployFlowReconciler) resumeWorkload(idl *internaldeploy.Deploy) error {
+	return r.pauseOrResumeWorkload(idl, false)
+}
+
+func (r *DeployFlowReconciler) pauseOrResumeWorkload(idl *internaldeploy.Deploy, paused bool) error {
+	action := "pause"
+	if !paused {
+		action = "resume"
+	}
+
+	klog.V(4).Infof("Start to %s workload.", action)
+
+	patchBytes := []byte(fmt.Sprintf(`{"spec":{"updateStrategy":{"paused":%t}}}`, paused))
+	if idl.InPlace() {
+		patchBytes = []byte(fmt.Sprintf(`{"spec":{"updateStrategy":{"rollingUpdate":{"paused":%t}}}}`, paused))
+	}
+	if idl.Stepped() {
+		// a Deployment in an update is resumed by the batches only, see syncSteppedDeployment.
+		if !paused && (idl.Spec.Action == setting.Update || idl.Spec.Action == setting.Rollback) {
+			return nil
+		}
+		patchBytes = []byte(fmt.Sprintf(`{"spec":{"paused":%t}}`, paused))
+	}
+
+	return PatchWorkload(idl.Unwrap(), patchBytes, r.Client)
+}
+
+// processWorkload updates the workload to make progress.
+func (r *DeployFlowReconciler) processWorkload(idl *internaldeploy.Deploy) error {
+	klog.V(4).Info("Start to process a workload.")
+
+	patchBytes := idl.GetPatchBytes()
+	if len(patchBytes) == 0 {
+		return nil
+	}
+
+	klog.Infof("Update workload, patchBytes %s", string(patchBytes))
+	return PatchWorkload(idl.Unwrap(), patchBytes, r.Client)
+}
+
+// processWorkloadWithPodsToDelete works like processWorkload, and the given pods will be removed first if the replicas is decreased.
+// An Advanced StatefulSet always removes the pods of the highest ordinals, which are the ones picked in getPodsForDeletion.
+// A Deployment removes the given pods first by their deletion cost, which is honored since Kubernetes 1.21.
+func (r *DeployFlowReconciler) processWorkloadWithPodsToDelete(idl *internaldeploy.Deploy, pods []string) error {
+	if idl.InPlace() {
+		return r.processWorkload(idl)
+	}
+	if idl.Stepped() {
+		for _, p := range pods {
+			err := internalpod.SetPodAnnotation(idl.Namespace, p, podDeletionCostAnnotation, strconv.Itoa(math.MinInt32), r.Client)
+			if client.IgnoreNotFound(err) != nil {
+				return err
+			}
+		}
+		return r.processWorkload(idl)
+	}
+
+	klog.V(4).Info("Start to process a workload.")
+
+	patchBytes, err := withPodsToDelete(idl.GetPatchBytes(), pods)
+	if err != nil {
+		return err
+	}
+	if len(patchBytes) == 0 {
+		return nil
+	}
+
+	klog.Infof("Update workload, patchBytes %s", string(patchBytes))
+	return PatchWorkload(idl.Unwrap(), patchBytes, r.Client)
+}
+
+func (r *DeployFlowReconciler) createWorkload(idl *internaldeploy.Deploy) error {
+	ws, err := r.getWorkloadStrategy(idl)
+	if err != nil {
+		return err
+	}
+	obj, err := generateWorkload(idl, ws, r.Scheme)
+	if err != nil {
+		return err
+	}
+
+	r.logger.WithField("deploy", idl).Infof("Creating %s %s", idl.ApplicationType(), obj.GetName())
+	return r.Create(context.TODO(), obj)
+}
+
+func (r *DeployFlowReconciler) updateWorkload(idl *internaldeploy.Deploy) error {
+	ws, err := r.getWorkloadStrategy(idl)
+	if err != nil {
+		return err
+	}
+	obj, err := generateWorkload(idl, ws, r.Scheme)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := r.getWorkload(idl)
+	if err != nil {
+		return err
+	}
+
+	obj.SetResourceVersion(tmp.GetResourceVersion())
+
+	// always hold the update, let Deploy controller to make progress
+	replicas := idl.Spec.Application.Replicas
+	if idl.Daemon() {
+		desired := workload.StatusOf(tmp).Replicas
+		replicas = &desired
+	}
+	holdUpdate(obj, replicas)
+
+	r.logger.WithField("deploy", idl).Infof("Updating %s %s", idl.ApplicationType(), obj.GetName())
+	return r.Update(context.TODO(), obj)
+}
+
+// holdUpdate sets the replicas, and the partition to the replicas, so that no pod is updated until a batch is started.
+func holdUpdate(obj workload.Object, replicas *int32) {
+	switch o := obj.(type) {
+	case *kruiseappsv1alpha1.CloneSet:
+		o.Spec.Replicas = replicas
+		p := intstr.FromInt(int(*replicas))
+		o.Spec.UpdateStrategy.Partition = &p
+	case *kruiseappsv1alpha1.StatefulSet:
+		o.Spec.Replicas = replicas
+		partition := *replicas
+		o.Spec.UpdateStrategy.RollingUpdate.Partition = &partition
+	case *appsv1.Deployment:
+		// no ReplicaSet is created for the new template until the Deployment is resumed.
+		o.Spec.Replicas = replicas
+		o.Spec.Paused = true
+	case *kruiseappsv1alpha1.DaemonSet:
+		// the replicas of a DaemonSet is the number of nodes it is scheduled to.
+		partition := *replicas
+		o.Spec.UpdateStrategy.RollingUpdate.Partition = &partition
+		o.Spec.UpdateStrategy.RollingUpdate.Selector = nil
+	}
+}
+
+func setPaused(obj workload.Object, paused bool) {
+	switch o := obj.(type) {
+	case *kruiseappsv1alpha1.CloneSet:
+		o.Spec.UpdateStrategy.Paused = paused
+	case *kruiseappsv1alpha1.StatefulSet:
+		if o.Spec.UpdateStrategy.RollingUpdate != nil {
+			o.Spec.UpdateStrategy.RollingUpdate.Paused = paused
+		}
+	case *appsv1.Deployment:
+		o.Spec.Paused = paused
+	case *kruiseappsv1alpha1.DaemonSet:
+		if o.Spec.UpdateStrategy.RollingUpdate != nil {
+			o.Spec.UpdateStrategy.RollingUpdate.Paused = &paused
+		}
+	}
+}
+
+func generateWorkload(idl *internaldeploy.Deploy, ws *tritonappsv1alpha1.WorkloadStrategy, scheme *runtime.Scheme) (workload.Object, error) {
+	obj, err := generate(idl, ws)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = controllerutil.SetControllerReference(idl.Unwrap(), obj, scheme)
+	return obj, nil
+}
+
+func PatchWorkload(deploy *tritonappsv1alpha1.DeployFlow, patchBytes []byte, cl client.Client) error {
+	obj, _, _ := fetcher.GetWorkloadInCacheOwnedByDeploy(deploy, cl)
+	if obj == nil {
+		return nil
+	}
+
+	target, err := workload.New(deploy.Spec.Application.ApplicationType)
+	if err != nil {
+		return err
+	}
+	target.SetNamespace(deploy.Namespace)
+	target.SetName(deploy.Spec.Application.CloneSetName)
+
+	return cl.Patch(context.TODO(), target, client.RawPatch(types.MergePatchType, patchBytes))
+}
+
+// recreatePods removes the pods, they are created again by the workload.
+func (r *DeployFlowReconciler) recreatePods(idl *internaldeploy.Deploy, pods []string) error {
+	if idl.InPlace() || idl.Stepped() {
+		for _, p := range pods {
+			if err := DeletePod(idl.Namespace, p, r.Client); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	patchBytes, err := withPodsToDelete([]byte(`{}`), pods)
+	if err != nil {
+		return err
+	}
+
+	return PatchWorkload(idl.Unwrap(), patchBytes, r.Client)
+}
+
+// syncSteppedDeployment pauses the Deployment once the pods of current batch are created, and resumes it if it is paused
+// before that, ex: the deploy is paused and resumed in the middle of a batch.
+func (r *DeployFlowReconciler) syncSteppedDeployment(idl *internaldeploy.Deploy) error {
+	if !idl.Stepped() || idl.Spec.Action != setting.Update && idl.Spec.Action != setting.Rollback {
+		return nil
+	}
+
+	obj, found, err := fetcher.GetWorkloadInCacheOwnedByDeploy(idl.Unwrap(), r.Client)
+	if err != nil || !found {
+		return err
+	}
+	d := obj.(*appsv1.Deployment)
+
+	rs, err := fetcher.GetNewReplicaSetInCache(d, r.Client)
+	if err != nil {
+		return err
+	}
+	paused := rs != nil && rs.Spec.Replicas != nil && *rs.Spec.Replicas >= idl.SteppedReplicas()
+	if paused == d.Spec.Paused {
+		return nil
+	}
+
+	klog.Infof("Deployment %s is stepped to %d replicas, paused is %t", d.Name, idl.SteppedReplicas(), paused)
+	return PatchWorkload(idl.Unwrap(), []byte(fmt.Sprintf(`{"spec":{"paused":%t}}`, paused)), r.Client)
+}
+
+// podOrdinal returns the ordinal of a pod of an Advanced StatefulSet, -1 if the name has no ordinal.
+func podOrdinal(p *corev1.Pod) int {
+	i := strings.LastIndex(p.Name, "-")
+	if i < 0 {
+		return -1
+	}
+	ordinal, err := strconv.Atoi(p.Name[i+1:])
+	if err != nil {
+		return -1
+	}
+
+	return ordinal
+}
@@ -0,0 +1,38 @@
+package fetcher
+
+import (
+	"context"
+	"sort"
+
+	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch
+
+// GetControllerRevisionsInCache returns the ControllerRevisions controlled by the CloneSet, the newest revision comes first.
+func GetControllerRevisionsInCache(cs *kruiseappsv1alpha1.CloneSet, cl client.Client) ([]*appsv1.ControllerRevision, error) {
+	opts := []client.ListOption{client.InNamespace(cs.Namespace)}
+	if cs.Spec.Selector != nil {
+		opts = append(opts, client.MatchingLabels(cs.Spec.Selector.MatchLabels))
+	}
+
+	crs := &appsv1.ControllerRevisionList{}
+	if err := cl.List(context.TODO(), crs, opts...); err != nil {
+		return nil, err
+	}
+
+	res := make([]*appsv1.ControllerRevision, 0, len(crs.Items))
+	for i := range crs.Items {
+		if metav1.IsControlledBy(&crs.Items[i], cs) {
+			res = append(res, &crs.Items[i])
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Revision > res[j].Revision
+	})
+
+	return res, nil
+}
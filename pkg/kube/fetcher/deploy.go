@@ -8,8 +8,6 @@ import (
 	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
 	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
 	"github.com/triton-io/triton/pkg/services/base"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -25,20 +23,7 @@ func GetDeployInCache(ns, name string, cl client.Client) (*tritonappsv1alpha1.De
 }
 
 func GetDeployInCacheOwningCloneSet(cs *kruiseappsv1alpha1.CloneSet, cl client.Client) (*tritonappsv1alpha1.DeployFlow, bool, error) {
-	deployRef := metav1.GetControllerOf(cs)
-	if deployRef == nil {
-		return nil, false, nil
-	}
-
-	deploy := &tritonappsv1alpha1.DeployFlow{}
-	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: cs.Namespace, Name: deployRef.Name}, deploy); err != nil {
-		if apierrors.IsNotFound(err) {
-			return nil, false, nil
-		}
-		return nil, false, err
-	}
-
-	return deploy, true, nil
+	return GetDeployInCacheOwning(cs, cl)
 }
 
 type DeployFilter struct {
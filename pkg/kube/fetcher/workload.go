@@ -0,0 +1,80 @@
+package fetcher
+
+import (
+	"context"
+
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	"github.com/triton-io/triton/pkg/kube/types/workload"
+	"github.com/triton-io/triton/pkg/services/base"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetWorkloadInCache returns the workload of the application type, ex: a CloneSet, an Advanced StatefulSet or a Deployment.
+func GetWorkloadInCache(ns, name, applicationType string, cl client.Client) (workload.Object, bool, error) {
+	obj, err := workload.New(applicationType)
+	if err != nil {
+		return nil, false, err
+	}
+
+	found, err := GetResourceInCache(ns, name, obj, cl)
+	if err != nil || !found {
+		obj = nil
+	}
+
+	return obj, found, err
+}
+
+func GetWorkloadInCacheByDeploy(deploy *tritonappsv1alpha1.DeployFlow, cl client.Client) (workload.Object, bool, error) {
+	return GetWorkloadInCache(deploy.Namespace, deploy.Spec.Application.CloneSetName, deploy.Spec.Application.ApplicationType, cl)
+}
+
+func GetWorkloadInCacheOwnedByDeploy(deploy *tritonappsv1alpha1.DeployFlow, cl client.Client) (workload.Object, bool, error) {
+	obj, found, err := GetWorkloadInCacheByDeploy(deploy, cl)
+	if obj != nil {
+		if !metav1.IsControlledBy(obj, deploy) {
+			obj = nil
+			found = false
+		}
+	}
+	return obj, found, err
+}
+
+// GetDeployInCacheOwning returns the DeployFlow controlling the workload.
+func GetDeployInCacheOwning(obj metav1.Object, cl client.Client) (*tritonappsv1alpha1.DeployFlow, bool, error) {
+	deployRef := metav1.GetControllerOf(obj)
+	if deployRef == nil {
+		return nil, false, nil
+	}
+
+	deploy := &tritonappsv1alpha1.DeployFlow{}
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: obj.GetNamespace(), Name: deployRef.Name}, deploy); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	return deploy, true, nil
+}
+
+// GetNewReplicaSetInCache returns the ReplicaSet of the Deployment with the same pod template, nil if it is not created yet.
+func GetNewReplicaSetInCache(d *appsv1.Deployment, cl client.Client) (*appsv1.ReplicaSet, error) {
+	rsList := &appsv1.ReplicaSetList{}
+	if err := cl.List(context.TODO(), rsList, client.InNamespace(d.Namespace), client.MatchingLabels(d.Spec.Selector.MatchLabels)); err != nil {
+		return nil, err
+	}
+
+	// Only include those whose ControllerRef matches the Deployment.
+	owned := make([]*appsv1.ReplicaSet, 0, len(rsList.Items))
+	for i := range rsList.Items {
+		if metav1.IsControlledBy(&rsList.Items[i], d) {
+			owned = append(owned, &rsList.Items[i])
+		}
+	}
+
+	return base.FindNewReplicaSet(d, owned), nil
+}
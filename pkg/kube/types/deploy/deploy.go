@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"time"
 
 	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
 	"github.com/triton-io/triton/pkg/kube/types/workload"
@@ -21,26 +22,33 @@ var weightedBatchPhase = map[tritonappsv1alpha1.BatchPhase]uint32{
 	"":                                  0,
 	tritonappsv1alpha1.BatchSmoking:     1,
 	tritonappsv1alpha1.BatchSmoked:      2,
-	tritonappsv1alpha1.BatchBaking:      3,
-	tritonappsv1alpha1.BatchBaked:       4,
+	tritonappsv1alpha1.BatchShadowing:   3,
+	tritonappsv1alpha1.BatchBaking:      4,
+	tritonappsv1alpha1.BatchBaked:       5,
 	tritonappsv1alpha1.BatchSmokeFailed: 10,
 	tritonappsv1alpha1.BatchBakeFailed:  10,
 }
 
 var weightedDeployPhase = map[tritonappsv1alpha1.DeployPhase]uint32{
-	"":                               0,
-	tritonappsv1alpha1.Pending:       1,
-	tritonappsv1alpha1.Initializing:  2,
-	tritonappsv1alpha1.BatchStarted:  3,
-	tritonappsv1alpha1.BatchFinished: 4,
-	tritonappsv1alpha1.Success:       10,
-	tritonappsv1alpha1.Failed:        10,
-	tritonappsv1alpha1.Aborted:       10,
-	tritonappsv1alpha1.Canceled:      10,
+	"":                                  0,
+	tritonappsv1alpha1.Pending:          1,
+	tritonappsv1alpha1.Initializing:     2,
+	tritonappsv1alpha1.BatchStarted:     3,
+	tritonappsv1alpha1.BlueGreenStarted: 3,
+	tritonappsv1alpha1.BatchFinished:    4,
+	tritonappsv1alpha1.Reverting:        5,
+	tritonappsv1alpha1.Success:          10,
+	tritonappsv1alpha1.Failed:           10,
+	tritonappsv1alpha1.Aborted:          10,
+	tritonappsv1alpha1.Canceled:         10,
 }
 
 const (
 	Separator = '/'
+
+	defaultPullInTimeout  = 20 * time.Second
+	defaultScaleDownDelay = 600 * time.Second
+	defaultShadowDuration = 300 * time.Second
 )
 
 // Deploy is the wrapper for tritonappsv1alpha1.DeployFlow type.
@@ -67,10 +75,78 @@ func (d *Deploy) GetCloneSetName() string {
 	return d.Spec.Application.CloneSetName
 }
 
+// ApplicationType returns the type of the workload, a CloneSet if it is not specified.
+func (d *Deploy) ApplicationType() string {
+	if t := d.Spec.Application.ApplicationType; t != "" {
+		return t
+	}
+	return tritonappsv1alpha1.ApplicationTypeCloneSet
+}
+
+// InPlace returns true if the pods of a batch replace the old ones in place instead of being created next to them,
+// it is true for an Advanced StatefulSet whose pods keep their ordinals and volumes, and an Advanced DaemonSet
+// which runs a pod on each node.
+func (d *Deploy) InPlace() bool {
+	return d.ApplicationType() == tritonappsv1alpha1.ApplicationTypeStatefulSet || d.Daemon()
+}
+
+// Daemon returns true if the workload is an Advanced DaemonSet, its replicas is the number of nodes it is scheduled to.
+func (d *Deploy) Daemon() bool {
+	return d.ApplicationType() == tritonappsv1alpha1.ApplicationTypeDaemonSet
+}
+
+// DesiredReplicas returns the replicas once the deploy is finished, it is the replicas in .status for a DaemonSet.
+func (d *Deploy) DesiredReplicas() int32 {
+	if d.Daemon() {
+		return d.Status.Replicas
+	}
+	return *d.Spec.Application.Replicas
+}
+
+// Stepped returns true if the workload has no partition, and an update is made progress by resuming it with maxSurge
+// set to the batch size, then pausing it again once the pods of the batch are created, it is true for a Deployment.
+func (d *Deploy) Stepped() bool {
+	return d.ApplicationType() == tritonappsv1alpha1.ApplicationTypeDeployment
+}
+
+// SteppedReplicas returns the replicas of the new ReplicaSet when the pods of current batch are all created.
+func (d *Deploy) SteppedReplicas() int32 {
+	updated := *d.Spec.Application.Replicas - d.Status.ReplicasToProcess
+	return updated + int32(d.Status.FinishedReplicas+d.CurrentBatchSize())
+}
+
 func (d *Deploy) GetCloneSetLabels() labels.Set {
 
 	return d.getDefaultLabels()
 }
+
+// GetWorkloadLabels returns the labels of the workload, the default labels take precedence over the application labels.
+func (d *Deploy) GetWorkloadLabels() labels.Set {
+	return labels.Merge(d.Spec.Application.ApplicationLabel, d.getDefaultLabels())
+}
+
+// GetWorkloadAnnotations returns the application annotations with the PVC retention policy, the policy is recorded in
+// the workload since a non-update deploy is generated from the workload.
+func (d *Deploy) GetWorkloadAnnotations() labels.Set {
+	annotations := labels.Merge(d.Spec.Application.ApplicationAnnotation, nil)
+	if p := d.Spec.Application.PersistentVolumeClaimRetentionPolicy; p != nil {
+		b, _ := json.Marshal(p)
+		annotations[setting.PVCRetentionPolicyAnnotation] = string(b)
+	}
+	return annotations
+}
+
+// GetPodLabels returns the labels of the pod template, the default labels take precedence over the application labels,
+// which take precedence over the labels of the template.
+func (d *Deploy) GetPodLabels() labels.Set {
+	return labels.Merge(labels.Merge(d.Spec.Application.Template.Labels, d.Spec.Application.ApplicationLabel), d.getDefaultLabels())
+}
+
+// GetPodAnnotations returns the annotations of the pod template, the application annotations take precedence over
+// the annotations of the template.
+func (d *Deploy) GetPodAnnotations() labels.Set {
+	return labels.Merge(d.Spec.Application.Template.Annotations, d.Spec.Application.ApplicationAnnotation)
+}
 func (d *Deploy) getDefaultLabels() labels.Set {
 	return workload.GetDefaultLabels(d.Spec.Application.AppName, d.Spec.Application.CloneSetName, d.Spec.Application.AppID, d.Spec.Application.GroupID)
 }
@@ -104,6 +180,14 @@ func (d *Deploy) Canceled() bool {
 	return d.DeployFlow.Status.Phase == tritonappsv1alpha1.Canceled
 }
 
+func (d *Deploy) Reverting() bool {
+	return d.DeployFlow.Status.Phase == tritonappsv1alpha1.Reverting
+}
+
+func (d *Deploy) BlueGreenStarted() bool {
+	return d.DeployFlow.Status.Phase == tritonappsv1alpha1.BlueGreenStarted
+}
+
 func (d *Deploy) Aborted() bool {
 	return d.DeployFlow.Status.Phase == tritonappsv1alpha1.Aborted
 }
@@ -189,8 +273,10 @@ func (d *Deploy) MoveForward() bool {
 
 	// for an "auto" deploy, if current batch is not canary, always move forward.
 	// if current batch is 2 and current phase is batchPending and canaryEnabled do not move forward.
+	// if the step of previous batch is paused, do not move forward either.
 	if d.Auto() {
-		if !(c.Batch == 2 && c.Phase == tritonappsv1alpha1.BatchPending && d.CanaryEnabled()) {
+		if !(c.Batch == 2 && c.Phase == tritonappsv1alpha1.BatchPending && d.CanaryEnabled()) &&
+			!(c.Phase == tritonappsv1alpha1.BatchPending && d.pausedAfter(c.Batch-1)) {
 			return true
 		}
 
@@ -253,6 +339,151 @@ func (d *Deploy) NonUpdateStrategy() *tritonappsv1alpha1.DeployNonUpdateStrategy
 	return &tritonappsv1alpha1.DeployNonUpdateStrategy{}
 }
 
+// PullInTimeout returns the max time to wait for new pods to be pulled in.
+func (d *Deploy) PullInTimeout() time.Duration {
+	s := d.UpdateStrategy().PullInTimeoutSeconds
+	if !d.RevisionChanged() {
+		s = d.NonUpdateStrategy().PullInTimeoutSeconds
+	}
+	if s > 0 {
+		return time.Duration(s) * time.Second
+	}
+
+	return defaultPullInTimeout
+}
+
+// BakeTime returns the minimum time new pods of a batch serve traffic before old pods are removed.
+func (d *Deploy) BakeTime(canary bool) time.Duration {
+	if !d.RevisionChanged() {
+		return 0
+	}
+
+	s := d.UpdateStrategy()
+	if canary && s.CanaryBakeSeconds != nil {
+		return time.Duration(*s.CanaryBakeSeconds) * time.Second
+	}
+	return time.Duration(s.BakeSeconds) * time.Second
+}
+
+// BakeRemaining returns the remaining bake time of a batch, 0 if it is not baking or the bake time has passed.
+func (d *Deploy) BakeRemaining(c *tritonappsv1alpha1.BatchCondition) time.Duration {
+	if c == nil || c.Phase != tritonappsv1alpha1.BatchBaking || c.PulledInAt.IsZero() {
+		return 0
+	}
+
+	if remaining := d.BakeTime(c.Canary) - time.Since(c.PulledInAt.Time); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// Analysis returns the analysis of an update, nil if no metric is specified.
+func (d *Deploy) Analysis() *tritonappsv1alpha1.Analysis {
+	if !d.RevisionChanged() {
+		return nil
+	}
+
+	a := d.UpdateStrategy().Analysis
+	if a == nil || len(a.Metrics) == 0 {
+		return nil
+	}
+
+	return a
+}
+
+// BatchAnalysis returns the analysis of current batch phase, the one of shadowing is used in the Shadowing phase if any.
+func (d *Deploy) BatchAnalysis() *tritonappsv1alpha1.Analysis {
+	if s := d.Shadowing(); s != nil && s.Analysis != nil && len(s.Analysis.Metrics) > 0 &&
+		d.CurrentBatchPhase() == tritonappsv1alpha1.BatchShadowing {
+		return s.Analysis
+	}
+
+	return d.Analysis()
+}
+
+// Shadowing returns the shadowing of an update with canary enabled, nil if no shadowing is specified.
+func (d *Deploy) Shadowing() *tritonappsv1alpha1.Shadowing {
+	if !d.RevisionChanged() || !d.CanaryEnabled() {
+		return nil
+	}
+
+	return d.UpdateStrategy().Shadowing
+}
+
+// ShadowRemaining returns the time left before requests stop being mirrored to the batch.
+func (d *Deploy) ShadowRemaining(c *tritonappsv1alpha1.BatchCondition) time.Duration {
+	s := d.Shadowing()
+	if s == nil || c == nil || c.ShadowedAt.IsZero() {
+		return 0
+	}
+
+	duration := defaultShadowDuration
+	if s.DurationSeconds != nil {
+		duration = time.Duration(*s.DurationSeconds) * time.Second
+	}
+
+	return duration - time.Since(c.ShadowedAt.Time)
+}
+
+// Routing returns the canary routing of an update, nil if no routing is specified.
+func (d *Deploy) Routing() *tritonappsv1alpha1.CanaryRouting {
+	if !d.RevisionChanged() {
+		return nil
+	}
+
+	return d.UpdateStrategy().Routing
+}
+
+// Weighted returns true if a share of requests is routed to the updated pods by weight.
+// Creates are never weighted since there is no stable pod.
+func (d *Deploy) Weighted() bool {
+	rt := d.Routing()
+	if rt == nil || d.Spec.Action == setting.Create {
+		return false
+	}
+	if rt.Weight > 0 && d.CanaryEnabled() {
+		return true
+	}
+	for _, s := range d.Steps() {
+		if s.Weight != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BatchWeight returns the weight of the batch, nil if traffic is not weighted yet.
+// The last batch takes all requests, and a batch without a weight keeps the one of the previous batch.
+func (d *Deploy) BatchWeight(c *tritonappsv1alpha1.BatchCondition) *int32 {
+	if c == nil || !d.Weighted() {
+		return nil
+	}
+
+	var w int32
+	s := d.step(c)
+	switch {
+	case c.Batch >= d.Status.Batches:
+		w = 100
+	case c.Canary:
+		if w = d.Routing().Weight; w == 0 {
+			return nil
+		}
+	case s != nil && s.Weight != nil:
+		w = *s.Weight
+	default:
+		for i := range d.Status.Conditions {
+			if p := &d.Status.Conditions[i]; p.Batch == c.Batch-1 && p.Weight != nil {
+				w = *p.Weight
+				return &w
+			}
+		}
+		return nil
+	}
+
+	return &w
+}
+
 func (d *Deploy) Canary() int {
 	return d.UpdateStrategy().Canary
 }
@@ -264,8 +495,15 @@ func (d *Deploy) BatchSize() *intstr.IntOrString {
 	return d.NonUpdateStrategy().BatchSize
 }
 
+func (d *Deploy) Steps() []tritonappsv1alpha1.BatchStep {
+	if d.RevisionChanged() {
+		return d.UpdateStrategy().Steps
+	}
+	return d.NonUpdateStrategy().Steps
+}
+
 func (d *Deploy) BatchSizeNum() int32 {
-	batchSize, err := intstr.GetValueFromIntOrPercent(d.BatchSize(), int(*d.Spec.Application.Replicas), true)
+	batchSize, err := intstr.GetValueFromIntOrPercent(d.BatchSize(), int(d.DesiredReplicas()), true)
 	if err != nil {
 		return 0
 	}
@@ -287,6 +525,61 @@ func (d *Deploy) ShouldCancel() bool {
 	return d.NonUpdateStrategy().Canceled
 }
 
+// Revertable returns true if updated pods of the deploy can be reverted, it works for update and rollback of a CloneSet only.
+func (d *Deploy) Revertable() bool {
+	// a revert creates the old pods next to the updated ones.
+	return (d.Spec.Action == setting.Update || d.Spec.Action == setting.Rollback) && !d.InPlace() && !d.Stepped()
+}
+
+// RevertOnCancel returns true if updated pods should be reverted when the deploy is canceled, it works for update and rollback only.
+func (d *Deploy) RevertOnCancel() bool {
+	if !d.Revertable() {
+		return false
+	}
+	return d.UpdateStrategy().RevertOnCancel
+}
+
+// BlueGreen returns the blue/green strategy, it works for update and rollback of a CloneSet only.
+func (d *Deploy) BlueGreen() *tritonappsv1alpha1.BlueGreenStrategy {
+	if d.Spec.Action != setting.Update && d.Spec.Action != setting.Rollback || d.InPlace() || d.Stepped() {
+		return nil
+	}
+	return d.UpdateStrategy().BlueGreen
+}
+
+// ScaleDownDelay returns how long the old pods of a blue/green deploy are kept after promotion.
+func (d *Deploy) ScaleDownDelay() time.Duration {
+	if bg := d.BlueGreen(); bg != nil && bg.ScaleDownDelaySeconds != nil {
+		return time.Duration(*bg.ScaleDownDelaySeconds) * time.Second
+	}
+	return defaultScaleDownDelay
+}
+
+// ShouldPromote returns true if traffic of a blue/green deploy should be switched to the new pods.
+func (d *Deploy) ShouldPromote() bool {
+	bg := d.BlueGreen()
+	return bg != nil && (bg.Promoted || bg.AutoPromote)
+}
+
+// ShouldSwitchBack returns true if traffic of a blue/green deploy should be switched back to the old pods,
+// a canceled blue/green deploy is switched back too.
+func (d *Deploy) ShouldSwitchBack() bool {
+	bg := d.BlueGreen()
+	return bg != nil && (bg.SwitchedBack || d.ShouldCancel())
+}
+
+// RevertBatchSize returns the number of pods reverted at a time, it is the size of the largest planned batch.
+func (d *Deploy) RevertBatchSize() int {
+	size := 1
+	for _, b := range d.planBatches(0, 0) {
+		if b.size > size {
+			size = b.size
+		}
+	}
+
+	return size
+}
+
 func (d *Deploy) DesiredBatches() int {
 	if d.RevisionChanged() {
 		return d.UpdateStrategy().Batches
@@ -311,6 +604,91 @@ func (d *Deploy) BatchIntervalSeconds() int32 {
 	return bi
 }
 
+// BatchTimeout returns the progress deadline of a batch, 0 means no deadline.
+func (d *Deploy) BatchTimeout() time.Duration {
+	if d.RevisionChanged() {
+		return time.Duration(d.UpdateStrategy().BatchTimeoutSeconds) * time.Second
+	}
+	return time.Duration(d.NonUpdateStrategy().BatchTimeoutSeconds) * time.Second
+}
+
+// Timeout returns the progress deadline of the deploy, 0 means no deadline.
+func (d *Deploy) Timeout() time.Duration {
+	if d.RevisionChanged() {
+		return time.Duration(d.UpdateStrategy().TimeoutSeconds) * time.Second
+	}
+	return time.Duration(d.NonUpdateStrategy().TimeoutSeconds) * time.Second
+}
+
+func (d *Deploy) GracefulPeriodSeconds() int32 {
+	if d.RevisionChanged() {
+		return d.UpdateStrategy().GracefulPeriodSeconds
+	}
+	return d.NonUpdateStrategy().GracefulPeriodSeconds
+}
+
+// CurrentBatchIntervalSeconds returns the time interval to wait after current batch, the interval of its step is preferred.
+func (d *Deploy) CurrentBatchIntervalSeconds() int32 {
+	if s := d.step(d.CurrentBatchInfo()); s != nil && s.IntervalSeconds > 0 {
+		return s.IntervalSeconds
+	}
+
+	return d.BatchIntervalSeconds()
+}
+
+// FailureTolerance returns the max number of failed pods tolerated in a batch.
+func (d *Deploy) FailureTolerance(batchSize int) int {
+	s := d.UpdateStrategy().BaseStrategy
+	if !d.RevisionChanged() {
+		s = d.NonUpdateStrategy().BaseStrategy
+	}
+
+	tolerance := int(s.MaxFailedReplicas)
+	if s.MaxFailedPercent > 0 {
+		if t := batchSize * int(s.MaxFailedPercent) / 100; s.MaxFailedReplicas == 0 || t < tolerance {
+			tolerance = t
+		}
+	}
+
+	return tolerance
+}
+
+func (d *Deploy) ReplaceFailedPods() bool {
+	if d.RevisionChanged() {
+		return d.UpdateStrategy().ReplaceFailedPods
+	}
+	return d.NonUpdateStrategy().ReplaceFailedPods
+}
+
+func (d *Deploy) TrafficProvider() string {
+	if d.RevisionChanged() {
+		return d.UpdateStrategy().TrafficProvider
+	}
+	return d.NonUpdateStrategy().TrafficProvider
+}
+
+func (d *Deploy) PreBatchHooks() []tritonappsv1alpha1.Webhook {
+	if d.RevisionChanged() {
+		return d.UpdateStrategy().PreBatchHooks
+	}
+	return d.NonUpdateStrategy().PreBatchHooks
+}
+
+func (d *Deploy) PostBatchHooks() []tritonappsv1alpha1.Webhook {
+	if d.RevisionChanged() {
+		return d.UpdateStrategy().PostBatchHooks
+	}
+	return d.NonUpdateStrategy().PostBatchHooks
+}
+
+// FailurePolicy returns the action taken when a batch fails, only updates can be rolled back.
+func (d *Deploy) FailurePolicy() tritonappsv1alpha1.FailurePolicy {
+	if d.RevisionChanged() && d.UpdateStrategy().FailurePolicy != "" {
+		return d.UpdateStrategy().FailurePolicy
+	}
+	return tritonappsv1alpha1.FailurePause
+}
+
 func (d *Deploy) Mode() tritonappsv1alpha1.DeployMode {
 	if d.RevisionChanged() {
 		return d.UpdateStrategy().Mode
@@ -414,13 +792,47 @@ func (d *Deploy) CurrentBatchFinished() bool {
 	return c.Phase == tritonappsv1alpha1.BatchBaked
 }
 
+// CurrentBatchFailed returns true if failed pods in current batch exceed the tolerance.
 func (d *Deploy) CurrentBatchFailed() bool {
 	c := d.CurrentBatchInfo()
 	if c == nil {
 		return false
 	}
 
-	return c.FailedReplicas > 0
+	failed := len(d.CurrentBatchFailedPods())
+	if n := c.FailedReplicas - len(c.AcceptedFailedPods); n > failed {
+		failed = n
+	}
+
+	return failed > d.FailureTolerance(c.BatchSize)
+}
+
+// CurrentBatchFailedPods returns the names of failed pods in current batch, including the recorded ones.
+// The accepted pods are excluded.
+func (d *Deploy) CurrentBatchFailedPods() []string {
+	c := d.CurrentBatchInfo()
+	if c == nil {
+		return nil
+	}
+
+	failed := sets.NewString(c.FailedPods...)
+	for _, p := range c.Pods {
+		if p.Phase == setting.PodFailed {
+			failed.Insert(p.Name)
+		}
+	}
+
+	return failed.Delete(c.AcceptedFailedPods...).List()
+}
+
+// FailedPodAccepted returns true if the failed pod is accepted in current batch.
+func (d *Deploy) FailedPodAccepted(name string) bool {
+	c := d.CurrentBatchInfo()
+	if c == nil {
+		return false
+	}
+
+	return sets.NewString(c.AcceptedFailedPods...).Has(name)
 }
 
 func (d *Deploy) AllBatchFinished() bool {
@@ -488,20 +900,110 @@ func (d *Deploy) CurrentBatchPullInAt() metav1.Time {
 	return c.PulledInAt
 }
 
+func (d *Deploy) CurrentBatchPulledOutAt() metav1.Time {
+	c := d.CurrentBatchInfo()
+	if c == nil {
+		return metav1.Time{}
+	}
+
+	return c.PulledOutAt
+}
+
+func (d *Deploy) CurrentBatchRemovedAt() metav1.Time {
+	c := d.CurrentBatchInfo()
+	if c == nil {
+		return metav1.Time{}
+	}
+
+	return c.RemovedAt
+}
+
+func (d *Deploy) CurrentBatchPulledOutPods() []string {
+	c := d.CurrentBatchInfo()
+	if c == nil {
+		return nil
+	}
+
+	return c.PulledOutPods
+}
+
+func (d *Deploy) CurrentBatchAnalysis() *tritonappsv1alpha1.AnalysisStatus {
+	c := d.CurrentBatchInfo()
+	if c == nil {
+		return nil
+	}
+
+	return c.Analysis
+}
+
+// CurrentBatchAnalysisFailed returns true if the analysis of current batch failed and the spec is not changed since then.
+func (d *Deploy) CurrentBatchAnalysisFailed() bool {
+	a := d.CurrentBatchAnalysis()
+	if a == nil {
+		return false
+	}
+
+	return a.Phase == tritonappsv1alpha1.AnalysisFailed && a.ObservedGeneration == d.Generation
+}
+
+// CurrentBatchTimedOut returns true if current batch exceeded its progress deadline and the spec is not changed since then.
+func (d *Deploy) CurrentBatchTimedOut() bool {
+	c := d.CurrentBatchInfo()
+	if c == nil {
+		return false
+	}
+
+	return !c.TimedOutAt.IsZero() && c.TimedOutGeneration == d.Generation
+}
+
+// CurrentBatchHook returns the status of a hook in current batch, an empty one is returned if it is never called.
+func (d *Deploy) CurrentBatchHook(stage tritonappsv1alpha1.WebhookStage, name string) tritonappsv1alpha1.WebhookStatus {
+	if c := d.CurrentBatchInfo(); c != nil {
+		for _, h := range c.Hooks {
+			if h.Stage == stage && h.Name == name {
+				return h
+			}
+		}
+	}
+
+	return tritonappsv1alpha1.WebhookStatus{Name: name, Stage: stage}
+}
+
+// CurrentBatchHookFailed returns true if the failure policy of a hook in current batch is applied and the spec is not changed since then.
+func (d *Deploy) CurrentBatchHookFailed() bool {
+	c := d.CurrentBatchInfo()
+	if c == nil {
+		return false
+	}
+
+	for _, h := range c.Hooks {
+		if !h.Succeeded && h.ObservedGeneration != 0 && h.ObservedGeneration == d.Generation {
+			return true
+		}
+	}
+
+	return false
+}
+
 // call NextBatchAndPhase only when current state is satisfied!
 func (d *Deploy) NextBatchAndPhase() (int, tritonappsv1alpha1.BatchPhase) {
 	batch := d.CurrentBatchInfo()
 
 	if !d.CurrentBatchIsCanary() {
-		// if mode is auto, move to the end
+		// if mode is auto, move to the end, or the next paused step
 		if d.Auto() {
+			for i, b := range d.planBatches(d.Status.FinishedBatches, d.Status.FinishedReplicas) {
+				if s := d.stepAt(b.step); s != nil && s.Pause {
+					return d.Status.FinishedBatches + i + 1, tritonappsv1alpha1.BatchBaked
+				}
+			}
 			return d.Status.Batches, tritonappsv1alpha1.BatchBaked
 		}
 		return batch.Batch, tritonappsv1alpha1.BatchBaked
 	}
 
 	switch batch.Phase {
-	case tritonappsv1alpha1.BatchSmoked:
+	case tritonappsv1alpha1.BatchSmoked, tritonappsv1alpha1.BatchShadowing:
 		return 1, tritonappsv1alpha1.BatchBaking
 	case tritonappsv1alpha1.BatchBaking:
 		return 1, tritonappsv1alpha1.BatchBaked
@@ -533,6 +1035,7 @@ func (d *Deploy) GetLastApplied() (*tritonappsv1alpha1.ApplicationSpec, error) {
 
 func (d *Deploy) PrepareNewDeploy() {
 	d.updatePhase(tritonappsv1alpha1.Pending, false)
+	d.DeployFlow.Status.RollbackOf = d.GetAnnotations()[setting.RollbackOfAnnotation]
 
 	// batches, _ := d.CalculateBatches()
 	// d.Status.Batches = batches
@@ -551,40 +1054,335 @@ func (d *Deploy) StartBatch() {
 
 			r = *d.Spec.Application.Replicas
 		}
+	} else if d.Daemon() && d.Spec.Action == setting.Create {
+		// pods of a DaemonSet are created with it, all of them are processed though they are counted as updated.
+		r = d.DesiredReplicas()
 	} else {
-		r = int32(math.Abs(float64(*d.Spec.Application.Replicas - d.Status.UpdatedReplicas)))
+		r = int32(math.Abs(float64(d.DesiredReplicas() - d.Status.UpdatedReplicas)))
 	}
 	d.DeployFlow.Status.ReplicasToProcess = r
+	d.DeployFlow.Status.Batches, _, _ = d.calculateBatches()
 	d.MarkAsBatchStarted()
 }
 
-func (d *Deploy) calculateBatches() (batches, batchSize int) {
-	remainingReplicas := int(d.Status.ReplicasToProcess) - d.Status.FinishedReplicas
-	if remainingReplicas == 0 {
-		return d.Status.FinishedBatches, 0
+// GetPatchBytes returns the patch bytes of the CloneSet for current batch
+//  1. if it is a Create, we should increase the replicas
+//  2. if it is a Update in batch pending stage, we should increase the replicas
+//  3. if it is a Update in batch baking stage, we should decrease the replicas and partition
+//  4. if it is a Update in the first batch pending stage, and there are already several updated
+//     replicas (it may happen in a rollback), we should adjust the replicas and partition accordingly。
+//  5. if it is a Update of an Advanced StatefulSet, the partition is decreased by the batch size in batch pending stage,
+//     the pods of the highest ordinals are updated in place.
+//  6. if it is a Update of a Deployment, it is resumed with maxSurge set to the batch size in batch pending stage,
+//     and resumed to remove the rest old pods after the last batch is baked.
+//  7. if it is a Update of an Advanced DaemonSet, the partition is decreased by the batch size in batch pending stage,
+//     the pods on the nodes selected by the step if any are updated in place.
+func (d *Deploy) GetPatchBytes() []byte {
+	if d.Daemon() {
+		return d.getDaemonPatchBytes()
 	}
-
-	batchSize, err := intstr.GetValueFromIntOrPercent(d.BatchSize(), int(*d.Spec.Application.Replicas), true)
-	if err != nil || batchSize == 0 || batchSize >= remainingReplicas {
-		batchSize = remainingReplicas
+	if d.InPlace() && (d.Spec.Action == setting.Update || d.Spec.Action == setting.Rollback) {
+		return d.getInPlacePatchBytes()
 	}
-	if d.Status.FinishedBatches == 0 {
-		fixedSize := batchSize
-		if d.CanaryEnabled() && d.Canary() < remainingReplicas {
-			fixedSize = d.Canary()
-		}
-
-		batches = int(math.Ceil(float64(int(d.Status.ReplicasToProcess)-fixedSize)/float64(batchSize))) + 1
-
-		return batches, fixedSize
+	if d.Stepped() && (d.Spec.Action == setting.Update || d.Spec.Action == setting.Rollback) {
+		return d.getSteppedPatchBytes()
 	}
 
-	batches = int(math.Ceil(float64(remainingReplicas)/float64(batchSize))) + d.Status.FinishedBatches
+	switch d.Spec.Action {
+	case setting.Create:
+		replicas := d.Status.FinishedReplicas + d.CurrentBatchSize()
+		if replicas > int(*d.Spec.Application.Replicas) {
+			// should not happened here, something must be wrong.
+			klog.Errorf("invalid replicas %d of deploy %s, finishedReplicas %d, currentBatchSize %d",
+				replicas, d.Name, d.Status.FinishedReplicas, d.CurrentBatchSize())
+		}
 
-	return batches, batchSize
-}
+		return []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+	case setting.Update, setting.Rollback:
+		switch d.CurrentBatchPhase() {
+		case tritonappsv1alpha1.BatchPending:
+			replicas := int(*d.Spec.Application.Replicas) + d.CurrentBatchSize()
+
+			if d.CurrentBatchNumber() == 1 && d.Status.UpdatedReplicas > 0 {
+				if d.Status.UpdatedReplicas >= *d.Spec.Application.Replicas {
+					return nil
+				}
+				partition := int(*d.Spec.Application.Replicas) - int(d.Status.UpdatedReplicas)
+				return []byte(fmt.Sprintf(`{"spec":{"replicas":%d,"updateStrategy":{"partition":%d}}}`, replicas, partition))
+			}
 
-func (d *Deploy) MarkCurrentBatchAsStarted(startedAt metav1.Time) {
+			return []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+		case tritonappsv1alpha1.BatchBaking:
+			replicas := int(*d.Spec.Application.Replicas)
+			partition := replicas - int(d.Status.UpdatedReplicas)
+			if partition < 0 {
+				// should not happened here, something must be wrong.
+				klog.Errorf("invalid partition %d of deploy %s, finishedReplicas %d, currentBatchSize %d",
+					partition, d.Name, d.Status.FinishedReplicas, d.CurrentBatchSize())
+			}
+
+			return []byte(fmt.Sprintf(`{"spec":{"replicas":%d,"updateStrategy":{"partition":%d}}}`, replicas, partition))
+		}
+	case setting.Restart:
+		switch d.CurrentBatchPhase() {
+		case tritonappsv1alpha1.BatchPending:
+			replicas := int(*d.Spec.Application.Replicas) + d.CurrentBatchSize()
+			return []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+		case tritonappsv1alpha1.BatchBaking:
+			replicas := int(*d.Spec.Application.Replicas)
+			return []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+		}
+	case setting.ScaleOut:
+		switch d.CurrentBatchPhase() {
+		case tritonappsv1alpha1.BatchPending:
+			replicas := int(d.Status.Replicas) + d.CurrentBatchSize()
+			return []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+		}
+	case setting.ScaleIn:
+		switch d.CurrentBatchPhase() {
+		case tritonappsv1alpha1.BatchBaking:
+			replicas := int(d.Status.Replicas) - d.CurrentBatchSize()
+			return []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+		}
+	}
+
+	return nil
+}
+
+func (d *Deploy) getInPlacePatchBytes() []byte {
+	if d.CurrentBatchPhase() != tritonappsv1alpha1.BatchPending {
+		return nil
+	}
+
+	partition := int(*d.Spec.Application.Replicas) - int(d.Status.UpdatedReplicas) - d.CurrentBatchSize()
+	if partition < 0 {
+		partition = 0
+	}
+
+	return []byte(fmt.Sprintf(`{"spec":{"updateStrategy":{"rollingUpdate":{"partition":%d}}}}`, partition))
+}
+
+// getDaemonPatchBytes updates the pods of the batch by the partition, the update is limited to the nodes selected by
+// the step, or all nodes if the selector is removed.
+func (d *Deploy) getDaemonPatchBytes() []byte {
+	if d.Spec.Action != setting.Update && d.Spec.Action != setting.Rollback || d.CurrentBatchPhase() != tritonappsv1alpha1.BatchPending {
+		return nil
+	}
+
+	partition := int(d.DesiredReplicas()) - int(d.Status.UpdatedReplicas) - d.CurrentBatchSize()
+	if partition < 0 {
+		partition = 0
+	}
+
+	selector := []byte("null")
+	if s := d.step(d.CurrentBatchInfo()); s != nil && s.NodeSelector != nil {
+		b, err := json.Marshal(s.NodeSelector)
+		if err != nil {
+			klog.Errorf("invalid node selector of step %d: %v", d.CurrentBatchInfo().Step, err)
+			return nil
+		}
+		selector = b
+	}
+
+	return []byte(fmt.Sprintf(`{"spec":{"updateStrategy":{"rollingUpdate":{"partition":%d,"selector":%s}}}}`, partition, selector))
+}
+
+// getSteppedPatchBytes resumes the Deployment to step a batch. With maxUnavailable of 0, it removes the old pods
+// replaced by the last batch first, then creates the pods of current batch, and waits until they are available.
+func (d *Deploy) getSteppedPatchBytes() []byte {
+	switch d.CurrentBatchPhase() {
+	case tritonappsv1alpha1.BatchPending:
+		return []byte(fmt.Sprintf(`{"spec":{"paused":false,"strategy":{"rollingUpdate":{"maxSurge":%d,"maxUnavailable":0}}}}`, d.CurrentBatchSize()))
+	case tritonappsv1alpha1.BatchBaking:
+		if d.Status.FinishedReplicas+d.CurrentBatchSize() >= int(d.Status.ReplicasToProcess) {
+			return []byte(`{"spec":{"paused":false}}`)
+		}
+	}
+
+	return nil
+}
+
+// BatchPlan is a batch planned for a deploy which is not started yet, it is used to preview the deploy.
+type BatchPlan struct {
+	Batch     int  `json:"batch"`
+	BatchSize int  `json:"batchSize"`
+	Step      int  `json:"step,omitempty"`
+	Canary    bool `json:"canary"`
+	// Weight is the percent of requests routed to the updated pods, nil if traffic is not weighted.
+	Weight *int32 `json:"weight,omitempty"`
+	// Patches are the CloneSet patches applied in each phase of the batch, phases without a patch are omitted.
+	Patches []PhasePatch `json:"patches"`
+}
+
+type PhasePatch struct {
+	Phase tritonappsv1alpha1.BatchPhase `json:"phase"`
+	Patch string                        `json:"patch"`
+}
+
+// Plan runs the batches of the deploy on a copy of it, assuming every batch succeeds.
+// replicas and updatedReplicas are the status of the CloneSet before the deploy is started.
+func (d *Deploy) Plan(replicas, updatedReplicas int32) []BatchPlan {
+	sim := FromDeploy(d.DeepCopy())
+	sim.Status = tritonappsv1alpha1.DeployFlowStatus{
+		Replicas:        replicas,
+		UpdatedReplicas: updatedReplicas,
+	}
+	sim.StartBatch()
+
+	var plans []BatchPlan
+	for !sim.AllBatchFinished() {
+		sim.PrepareNewBatch()
+		c := sim.CurrentBatchInfo()
+		if c == nil || c.BatchSize <= 0 {
+			break
+		}
+		c.Weight = sim.BatchWeight(c)
+		p := BatchPlan{Batch: c.Batch, BatchSize: c.BatchSize, Step: c.Step, Canary: c.Canary, Weight: c.Weight}
+
+		for _, phase := range []tritonappsv1alpha1.BatchPhase{tritonappsv1alpha1.BatchPending, tritonappsv1alpha1.BatchBaking} {
+			// nothing is pulled out in a create.
+			if phase == tritonappsv1alpha1.BatchBaking && sim.Spec.Action == setting.Create {
+				break
+			}
+			c.Phase = phase
+			sim.SetCondition(*c)
+			if patch := sim.GetPatchBytes(); len(patch) > 0 {
+				p.Patches = append(p.Patches, PhasePatch{Phase: phase, Patch: string(patch)})
+			}
+
+			// pods of the batch are created before baking.
+			if phase == tritonappsv1alpha1.BatchPending {
+				switch sim.Spec.Action {
+				case setting.Update, setting.Rollback:
+					sim.Status.UpdatedReplicas += int32(c.BatchSize)
+				case setting.Create, setting.ScaleOut:
+					sim.Status.Replicas += int32(c.BatchSize)
+				}
+			}
+		}
+		if sim.Spec.Action == setting.ScaleIn {
+			sim.Status.Replicas -= int32(c.BatchSize)
+		}
+
+		plans = append(plans, p)
+		sim.Status.FinishedBatches++
+		sim.Status.FinishedReplicas += c.BatchSize
+	}
+
+	return plans
+}
+
+func (d *Deploy) calculateBatches() (batches, batchSize, step int) {
+	return d.calculateBatchesFrom(d.Status.FinishedBatches, d.Status.FinishedReplicas)
+}
+
+// calculateBatchesFrom calculates the total batches, the size and step of the next batch after the given batches and replicas are finished.
+func (d *Deploy) calculateBatchesFrom(finishedBatches, finishedReplicas int) (batches, batchSize, step int) {
+	remainingReplicas := int(d.Status.ReplicasToProcess) - finishedReplicas
+	if remainingReplicas <= 0 {
+		return finishedBatches, 0, 0
+	}
+
+	// pods of a DaemonSet are all created at once, they are processed in one batch.
+	if d.Daemon() && d.Spec.Action == setting.Create {
+		return finishedBatches + 1, remainingReplicas, 0
+	}
+
+	if len(d.Steps()) > 0 {
+		planned := d.planBatches(finishedBatches, finishedReplicas)
+		return finishedBatches + len(planned), planned[0].size, planned[0].step
+	}
+
+	batchSize, err := intstr.GetValueFromIntOrPercent(d.BatchSize(), int(d.DesiredReplicas()), true)
+	if err != nil || batchSize == 0 || batchSize >= remainingReplicas {
+		batchSize = remainingReplicas
+	}
+	if finishedBatches == 0 {
+		fixedSize := batchSize
+		if d.CanaryEnabled() && d.Canary() < remainingReplicas {
+			fixedSize = d.Canary()
+		}
+
+		batches = int(math.Ceil(float64(int(d.Status.ReplicasToProcess)-fixedSize)/float64(batchSize))) + 1
+
+		return batches, fixedSize, 0
+	}
+
+	batches = int(math.Ceil(float64(remainingReplicas)/float64(batchSize))) + finishedBatches
+
+	return batches, batchSize, 0
+}
+
+// plannedBatch is a batch planned by .steps.
+type plannedBatch struct {
+	size int
+	step int
+}
+
+// planBatches plans the remaining batches by .steps, a canary batch is planned first if no batch is finished yet.
+func (d *Deploy) planBatches(finishedBatches, finishedReplicas int) []plannedBatch {
+	total := int(d.Status.ReplicasToProcess)
+	processed := finishedReplicas
+
+	var planned []plannedBatch
+	plan := func(target, step int) {
+		if target > total {
+			target = total
+		}
+		if target <= processed {
+			return
+		}
+		planned = append(planned, plannedBatch{size: target - processed, step: step})
+		processed = target
+	}
+
+	if finishedBatches == 0 && d.CanaryEnabled() {
+		plan(d.Canary(), 0)
+	}
+	for i, s := range d.Steps() {
+		target, err := intstr.GetValueFromIntOrPercent(&s.Replicas, total, true)
+		if err != nil {
+			klog.Errorf("invalid replicas %s of step %d: %v", s.Replicas.String(), i+1, err)
+			continue
+		}
+		plan(target, i+1)
+	}
+	plan(total, 0)
+
+	return planned
+}
+
+// stepAt returns the step by its index starting from 1, nil if it does not exist.
+func (d *Deploy) stepAt(i int) *tritonappsv1alpha1.BatchStep {
+	steps := d.Steps()
+	if i <= 0 || i > len(steps) {
+		return nil
+	}
+
+	return &steps[i-1]
+}
+
+func (d *Deploy) step(c *tritonappsv1alpha1.BatchCondition) *tritonappsv1alpha1.BatchStep {
+	if c == nil {
+		return nil
+	}
+
+	return d.stepAt(c.Step)
+}
+
+// pausedAfter returns true if the step of a batch is paused.
+func (d *Deploy) pausedAfter(batch int) bool {
+	for i := range d.Status.Conditions {
+		if c := &d.Status.Conditions[i]; c.Batch == batch {
+			s := d.step(c)
+			return s != nil && s.Pause
+		}
+	}
+
+	return false
+}
+
+func (d *Deploy) MarkCurrentBatchAsStarted(startedAt metav1.Time) {
 	c := d.CurrentBatchInfo()
 	if c != nil {
 		c.Phase = tritonappsv1alpha1.BatchSmoking
@@ -608,6 +1406,19 @@ func (d *Deploy) MarkCurrentBatchAsSmoked() {
 	d.SetCondition(*c)
 }
 
+// MarkCurrentBatchAsShadowing moves current batch to Shadowing, requests are mirrored to it from now on.
+func (d *Deploy) MarkCurrentBatchAsShadowing() {
+	c := d.CurrentBatchInfo()
+	if c != nil {
+		c.Phase = tritonappsv1alpha1.BatchShadowing
+		c.ShadowedAt = metav1.Now()
+	} else {
+		klog.Errorf("current batch condition is missing, conditions is %v", d.Status.Conditions)
+		return
+	}
+	d.SetCondition(*c)
+}
+
 func (d *Deploy) MarkCurrentBatchAsBaking() {
 	c := d.CurrentBatchInfo()
 	if c != nil {
@@ -633,7 +1444,82 @@ func (d *Deploy) MarkCurrentBatchAsFinished() {
 
 	d.DeployFlow.Status.FinishedBatches += 1
 	d.DeployFlow.Status.FinishedReplicas += c.BatchSize
-	d.DeployFlow.Status.FailedReplicas += c.FailedReplicas
+	d.SyncFailedReplicas()
+}
+
+// SyncFailedReplicas sums up failed replicas of all batches.
+func (d *Deploy) SyncFailedReplicas() {
+	failed := 0
+	for _, c := range d.Status.Conditions {
+		failed += c.FailedReplicas
+	}
+
+	d.DeployFlow.Status.FailedReplicas = failed
+}
+
+// RecordCurrentBatchFailedPods records failed pods in current batch, it returns the pods never recorded before.
+func (d *Deploy) RecordCurrentBatchFailedPods() []string {
+	c := d.CurrentBatchInfo()
+	if c == nil {
+		return nil
+	}
+
+	recorded := sets.NewString(c.FailedPods...)
+	var pods []string
+	for _, p := range d.CurrentBatchFailedPods() {
+		if !recorded.Has(p) {
+			pods = append(pods, p)
+		}
+	}
+	if len(pods) == 0 {
+		return nil
+	}
+
+	c.FailedPods = append(c.FailedPods, pods...)
+	d.SetCondition(*c)
+
+	return pods
+}
+
+// RetryCurrentBatch forgets the failed pods in current batch, they are going to be deleted and created again.
+// It returns the pods to delete.
+func (d *Deploy) RetryCurrentBatch() []string {
+	c := d.CurrentBatchInfo()
+	if c == nil {
+		return nil
+	}
+
+	pods := d.CurrentBatchFailedPods()
+	failed := sets.NewString(pods...)
+
+	ps := make([]tritonappsv1alpha1.PodInfo, 0, len(c.Pods))
+	for _, p := range c.Pods {
+		if !failed.Has(p.Name) {
+			ps = append(ps, p)
+		}
+	}
+	c.Pods = ps
+	c.FailedPods = nil
+	c.FailedReplicas = len(c.AcceptedFailedPods)
+	d.SetCondition(*c)
+	d.SyncFailedReplicas()
+
+	return pods
+}
+
+// AcceptCurrentBatchFailedPods accepts the failed pods in current batch, they are not counted in the tolerance any more.
+// It returns the accepted pods.
+func (d *Deploy) AcceptCurrentBatchFailedPods() []string {
+	c := d.CurrentBatchInfo()
+	if c == nil {
+		return nil
+	}
+
+	pods := d.CurrentBatchFailedPods()
+	c.AcceptedFailedPods = append(c.AcceptedFailedPods, pods...)
+	d.SetCondition(*c)
+
+	return pods
 }
 
 func (d *Deploy) PrepareNewBatch() {
@@ -641,19 +1527,61 @@ func (d *Deploy) PrepareNewBatch() {
 		return
 	}
 
-	batches, batchSize := d.calculateBatches()
+	batches, batchSize, step := d.calculateBatches()
 	d.Status.Batches = batches
 
 	// create new condition
 	c := tritonappsv1alpha1.BatchCondition{
 		Batch:     d.Status.FinishedBatches + 1,
 		BatchSize: batchSize,
+		Step:      step,
 		Canary:    len(d.Status.Conditions) == 0 && d.CanaryEnabled(),
 		Phase:     tritonappsv1alpha1.BatchPending,
 	}
 	d.SetCondition(c)
 }
 
+// Replan calculates the remaining batches again from finished replicas if .batchSize or .steps is changed, current batch
+// is planned again only if it is not started yet, and finished batches are left alone. It returns true if the plan is changed.
+func (d *Deploy) Replan() bool {
+	c := d.CurrentBatchInfo()
+	if c == nil || d.AllBatchFinished() {
+		return false
+	}
+
+	var batches, batchSize, step int
+	switch c.Phase {
+	case tritonappsv1alpha1.BatchPending:
+		batches, batchSize, step = d.calculateBatchesFrom(c.Batch-1, d.Status.FinishedReplicas)
+	case tritonappsv1alpha1.BatchBaked:
+		batches, _, _ = d.calculateBatchesFrom(c.Batch, d.Status.FinishedReplicas)
+		batchSize, step = c.BatchSize, c.Step
+	default:
+		// current batch is in progress, plan the batches after it.
+		batches, _, _ = d.calculateBatchesFrom(c.Batch, d.Status.FinishedReplicas+c.BatchSize)
+		batchSize, step = c.BatchSize, c.Step
+	}
+
+	if batches == d.Status.Batches && batchSize == c.BatchSize && step == c.Step {
+		return false
+	}
+
+	d.DeployFlow.Status.Replans = append(d.DeployFlow.Status.Replans, tritonappsv1alpha1.Replan{
+		Batch:        c.Batch,
+		OldBatches:   d.Status.Batches,
+		Batches:      batches,
+		OldBatchSize: c.BatchSize,
+		BatchSize:    batchSize,
+		ReplannedAt:  metav1.Now(),
+	})
+	d.DeployFlow.Status.Batches = batches
+	c.BatchSize = batchSize
+	c.Step = step
+	d.SetCondition(*c)
+
+	return true
+}
+
 func (d *Deploy) SetPodsStatus(pods []string) {
 	d.DeployFlow.Status.Pods = pods
 }
@@ -668,6 +1596,153 @@ func (d *Deploy) SetPulledInAt() {
 	d.SetCondition(*c)
 }
 
+// SetWeight records the weight routed to the updated pods in current batch.
+func (d *Deploy) SetWeight(weight *int32) {
+	c := d.CurrentBatchInfo()
+	if c == nil {
+		return
+	}
+	c.Weight = weight
+
+	d.SetCondition(*c)
+}
+
+// SetPulledOut records the old pods pulled out in current batch.
+func (d *Deploy) SetPulledOut(pods []string) {
+	c := d.CurrentBatchInfo()
+	if c == nil {
+		return
+	}
+	c.PulledOutAt = metav1.Now()
+	c.PulledOutPods = pods
+
+	d.SetCondition(*c)
+}
+
+// MarkCurrentBatchAsRemoved records that the workload is patched to remove the old pods of current batch.
+func (d *Deploy) MarkCurrentBatchAsRemoved() {
+	c := d.CurrentBatchInfo()
+	if c == nil {
+		return
+	}
+	c.RemovedAt = metav1.Now()
+
+	d.SetCondition(*c)
+}
+
+func (d *Deploy) SetCurrentBatchAnalysis(a *tritonappsv1alpha1.AnalysisStatus) {
+	c := d.CurrentBatchInfo()
+	if c == nil {
+		return
+	}
+	c.Analysis = a
+
+	d.SetCondition(*c)
+}
+
+func (d *Deploy) SetCurrentBatchHook(s tritonappsv1alpha1.WebhookStatus) {
+	c := d.CurrentBatchInfo()
+	if c == nil {
+		return
+	}
+
+	found := false
+	for i := range c.Hooks {
+		if c.Hooks[i].Stage == s.Stage && c.Hooks[i].Name == s.Name {
+			c.Hooks[i] = s
+			found = true
+			break
+		}
+	}
+	if !found {
+		c.Hooks = append(c.Hooks, s)
+	}
+
+	d.SetCondition(*c)
+}
+
+// ResetCurrentBatchFailedHooks removes status of the failed hooks in current batch, so that they are called again with full retries.
+func (d *Deploy) ResetCurrentBatchFailedHooks() {
+	c := d.CurrentBatchInfo()
+	if c == nil || len(c.Hooks) == 0 {
+		return
+	}
+
+	hooks := make([]tritonappsv1alpha1.WebhookStatus, 0, len(c.Hooks))
+	for _, h := range c.Hooks {
+		if h.Succeeded {
+			hooks = append(hooks, h)
+		}
+	}
+	c.Hooks = hooks
+
+	d.SetCondition(*c)
+}
+
+// MarkCurrentBatchAsFailed marks current batch as SmokeFailed or BakeFailed according to its phase.
+func (d *Deploy) MarkCurrentBatchAsFailed() {
+	c := d.CurrentBatchInfo()
+	if c == nil {
+		klog.Errorf("current batch condition is missing, conditions is %v", d.Status.Conditions)
+		return
+	}
+
+	c.Phase = failedBatchPhase(c.Phase)
+	c.FinishedAt = metav1.Now()
+
+	d.SetCondition(*c)
+}
+
+// MarkCurrentBatchAsTimedOut marks current batch as SmokeFailed or BakeFailed since it exceeded its progress deadline,
+// a batch waiting to move forward, ex: a pending one, stays in its phase.
+func (d *Deploy) MarkCurrentBatchAsTimedOut() {
+	c := d.CurrentBatchInfo()
+	if c == nil {
+		klog.Errorf("current batch condition is missing, conditions is %v", d.Status.Conditions)
+		return
+	}
+
+	switch c.Phase {
+	case tritonappsv1alpha1.BatchSmoking, tritonappsv1alpha1.BatchShadowing, tritonappsv1alpha1.BatchBaking:
+		c.Phase = failedBatchPhase(c.Phase)
+	}
+	c.TimedOutAt = metav1.Now()
+	c.TimedOutGeneration = d.Generation
+
+	d.SetCondition(*c)
+}
+
+// ResumeTimedOutBatch moves a timed out batch back to Smoking or Baking, its progress deadline is counted from now.
+func (d *Deploy) ResumeTimedOutBatch() {
+	c := d.CurrentBatchInfo()
+	if c == nil || c.TimedOutAt.IsZero() {
+		return
+	}
+
+	switch c.Phase {
+	case tritonappsv1alpha1.BatchSmokeFailed:
+		c.Phase = tritonappsv1alpha1.BatchSmoking
+	case tritonappsv1alpha1.BatchBakeFailed:
+		c.Phase = tritonappsv1alpha1.BatchBaking
+	}
+	c.TimedOutAt = metav1.Time{}
+	c.TimedOutGeneration = 0
+	c.ResumedAt = metav1.Now()
+
+	d.SetCondition(*c)
+}
+
+func failedBatchPhase(p tritonappsv1alpha1.BatchPhase) tritonappsv1alpha1.BatchPhase {
+	switch {
+	case p == tritonappsv1alpha1.BatchSmokeFailed || p == tritonappsv1alpha1.BatchBakeFailed:
+		return p
+	case weightedBatchPhase[p] < weightedBatchPhase[tritonappsv1alpha1.BatchBaking]:
+		return tritonappsv1alpha1.BatchSmokeFailed
+	default:
+		return tritonappsv1alpha1.BatchBakeFailed
+	}
+}
+
 func (d *Deploy) SetUpdatedAt(updatedAt metav1.Time) {
 	d.DeployFlow.Status.UpdatedAt = updatedAt
 }
@@ -728,6 +1803,98 @@ func (d *Deploy) MarkAsCanceled() {
 	d.updateFinalPhase(tritonappsv1alpha1.Canceled)
 }
 
+// StartRevert moves the deploy to Reverting, replicas is the number of updated replicas to revert.
+func (d *Deploy) StartRevert(replicas int) {
+	d.DeployFlow.Status.Revert = &tritonappsv1alpha1.RevertStatus{
+		Replicas:  replicas,
+		Phase:     tritonappsv1alpha1.BatchPending,
+		StartedAt: metav1.Now(),
+	}
+	d.DeployFlow.Status.Paused = false
+	d.updatePhase(tritonappsv1alpha1.Reverting, false)
+}
+
+// StartRevertBatch starts a new revert batch, pods of the previous revision are created in Smoking phase,
+// or the surplus updated pods are removed directly in Baking phase.
+func (d *Deploy) StartRevertBatch(batchSize, partition int, phase tritonappsv1alpha1.BatchPhase) {
+	rv := d.Status.Revert
+	rv.Batch++
+	rv.BatchSize = batchSize
+	rv.Partition = partition
+	rv.Phase = phase
+	rv.Pods = nil
+	rv.PulledOutPods = nil
+	rv.BatchStartedAt = metav1.Now()
+	rv.PulledInAt = metav1.Time{}
+	rv.PulledOutAt = metav1.Time{}
+}
+
+// FinishRevertBatch marks current revert batch as Baked.
+func (d *Deploy) FinishRevertBatch() {
+	rv := d.Status.Revert
+	rv.Phase = tritonappsv1alpha1.BatchBaked
+	rv.RevertedReplicas += rv.BatchSize
+}
+
+// FinishRevert marks the deploy as Canceled once all updated pods are reverted.
+func (d *Deploy) FinishRevert() {
+	if rv := d.Status.Revert; rv != nil {
+		rv.FinishedAt = metav1.Now()
+	}
+	d.MarkAsCanceled()
+}
+
+// StartBlueGreen moves the deploy to BlueGreenStarted, the new pods are created next to the old ones of the stable revision.
+func (d *Deploy) StartBlueGreen(stable, update string, oldPods []tritonappsv1alpha1.PodInfo) {
+	d.DeployFlow.Status.BlueGreen = &tritonappsv1alpha1.BlueGreenStatus{
+		Phase:          tritonappsv1alpha1.BlueGreenScalingUp,
+		StableRevision: stable,
+		UpdateRevision: update,
+		ActiveRevision: stable,
+		OldPods:        oldPods,
+		StartedAt:      metav1.Now(),
+	}
+	d.DeployFlow.Status.ReplicasToProcess = *d.Spec.Application.Replicas
+	d.updatePhase(tritonappsv1alpha1.BlueGreenStarted, false)
+}
+
+// PreviewBlueGreen records the new pods once they are all ready, traffic still goes to the old pods.
+func (d *Deploy) PreviewBlueGreen(newPods []tritonappsv1alpha1.PodInfo) {
+	bg := d.Status.BlueGreen
+	bg.NewPods = newPods
+	bg.Phase = tritonappsv1alpha1.BlueGreenPreview
+}
+
+// PromoteBlueGreen marks traffic as switched to the new pods.
+func (d *Deploy) PromoteBlueGreen() {
+	bg := d.Status.BlueGreen
+	bg.ActiveRevision = bg.UpdateRevision
+	bg.Phase = tritonappsv1alpha1.BlueGreenPromoted
+	bg.PromotedAt = metav1.Now()
+}
+
+// SwitchBackBlueGreen marks traffic as switched back to the old pods.
+func (d *Deploy) SwitchBackBlueGreen() {
+	bg := d.Status.BlueGreen
+	bg.ActiveRevision = bg.StableRevision
+	bg.Phase = tritonappsv1alpha1.BlueGreenSwitchedBack
+	bg.SwitchedBackAt = metav1.Now()
+}
+
+// ScaleDownBlueGreen marks the old pods as being removed.
+func (d *Deploy) ScaleDownBlueGreen() {
+	d.Status.BlueGreen.Phase = tritonappsv1alpha1.BlueGreenScalingDown
+}
+
+// ScaleDownRemaining returns the time left before the old pods of a promoted blue/green deploy are removed.
+func (d *Deploy) ScaleDownRemaining() time.Duration {
+	bg := d.Status.BlueGreen
+	if bg == nil || bg.PromotedAt.IsZero() {
+		return d.ScaleDownDelay()
+	}
+	return d.ScaleDownDelay() - time.Since(bg.PromotedAt.Time)
+}
+
 func (d *Deploy) MarkAsAborted() {
 	d.updateFinalPhase(tritonappsv1alpha1.Aborted)
 }
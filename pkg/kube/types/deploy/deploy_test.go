@@ -0,0 +1,525 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	"github.com/triton-io/triton/pkg/setting"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// newUpdateDeploy returns an update of the replicas by the strategy, all of the replicas are going to be processed.
+func newUpdateDeploy(replicas int32, s *tritonappsv1alpha1.DeployUpdateStrategy) *Deploy {
+	return FromDeploy(&tritonappsv1alpha1.DeployFlow{
+		Spec: tritonappsv1alpha1.DeployFlowSpec{
+			Action:         setting.Update,
+			Application:    &tritonappsv1alpha1.ApplicationSpec{Replicas: &replicas},
+			UpdateStrategy: s,
+		},
+		Status: tritonappsv1alpha1.DeployFlowStatus{ReplicasToProcess: replicas},
+	})
+}
+
+func newSteps(replicas ...string) []tritonappsv1alpha1.BatchStep {
+	steps := make([]tritonappsv1alpha1.BatchStep, 0, len(replicas))
+	for _, r := range replicas {
+		steps = append(steps, tritonappsv1alpha1.BatchStep{Replicas: intstr.Parse(r)})
+	}
+	return steps
+}
+
+// finishBatches prepares and finishes batches of the deploy one by one, until n batches or all of them are finished.
+func finishBatches(d *Deploy, n int) {
+	for !d.AllBatchFinished() && d.Status.FinishedBatches < n {
+		d.PrepareNewBatch()
+		d.MarkCurrentBatchAsFinished()
+	}
+}
+
+func TestPrepareNewBatchBySteps(t *testing.T) {
+	tests := []struct {
+		name     string
+		replicas int32
+		canary   int
+		steps    []tritonappsv1alpha1.BatchStep
+		expected []plannedBatch
+	}{
+		{
+			// 5% of 20 is 1, the step is skipped since it processes no more pods than the first one.
+			name:     "20 replicas",
+			replicas: 20,
+			steps:    newSteps("1", "5%", "25%", "50%", "100%"),
+			expected: []plannedBatch{{size: 1, step: 1}, {size: 4, step: 3}, {size: 5, step: 4}, {size: 10, step: 5}},
+		},
+		{
+			name:     "500 replicas",
+			replicas: 500,
+			steps:    newSteps("1", "5%", "25%", "50%", "100%"),
+			expected: []plannedBatch{{size: 1, step: 1}, {size: 24, step: 2}, {size: 100, step: 3}, {size: 125, step: 4}, {size: 250, step: 5}},
+		},
+		{
+			name:     "canary first",
+			replicas: 20,
+			canary:   2,
+			steps:    newSteps("1", "5%", "25%", "50%", "100%"),
+			expected: []plannedBatch{{size: 2}, {size: 3, step: 3}, {size: 5, step: 4}, {size: 10, step: 5}},
+		},
+		{
+			// the remaining pods after the last step are processed in a final batch.
+			name:     "steps not reaching all pods",
+			replicas: 10,
+			steps:    newSteps("1", "3"),
+			expected: []plannedBatch{{size: 1, step: 1}, {size: 2, step: 2}, {size: 7}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batchSize := intstr.FromInt(1)
+			d := newUpdateDeploy(tt.replicas, &tritonappsv1alpha1.DeployUpdateStrategy{
+				BaseStrategy: tritonappsv1alpha1.BaseStrategy{BatchSize: &batchSize, Steps: tt.steps},
+				Canary:       tt.canary,
+			})
+
+			var got []plannedBatch
+			for !d.AllBatchFinished() && len(got) <= len(tt.expected) {
+				d.PrepareNewBatch()
+				if d.Status.Batches != len(tt.expected) {
+					t.Errorf("batch %d: expected %d batches, got %d", len(got)+1, len(tt.expected), d.Status.Batches)
+				}
+				c := d.CurrentBatchInfo()
+				if c.Canary != (tt.canary > 0 && c.Batch == 1) {
+					t.Errorf("batch %d: unexpected canary %t", c.Batch, c.Canary)
+				}
+				got = append(got, plannedBatch{size: c.BatchSize, step: c.Step})
+				d.MarkCurrentBatchAsFinished()
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPausedStep(t *testing.T) {
+	steps := newSteps("1", "5", "100%")
+	steps[1].Pause = true
+	steps[1].IntervalSeconds = 30
+	d := newUpdateDeploy(20, &tritonappsv1alpha1.DeployUpdateStrategy{
+		BaseStrategy: tritonappsv1alpha1.BaseStrategy{Mode: tritonappsv1alpha1.Auto, BatchIntervalSeconds: 10, Steps: steps},
+	})
+
+	finishBatches(d, 1)
+	if got := d.CurrentBatchIntervalSeconds(); got != 10 {
+		t.Errorf("expected the interval of batch 1 to be 10, got %d", got)
+	}
+	// an auto deploy stops at the batch of the paused step.
+	if batch, phase := d.NextBatchAndPhase(); batch != 2 || phase != tritonappsv1alpha1.BatchBaked {
+		t.Errorf("expected to move to batch 2 %s, got batch %d %s", tritonappsv1alpha1.BatchBaked, batch, phase)
+	}
+
+	finishBatches(d, 2)
+	if got := d.CurrentBatchIntervalSeconds(); got != 30 {
+		t.Errorf("expected the interval of batch 2 to be 30, got %d", got)
+	}
+
+	d.PrepareNewBatch()
+	if d.MoveForward() {
+		t.Error("expected batch 3 to wait after the paused step")
+	}
+	d.Spec.UpdateStrategy.Batches = 3
+	if !d.MoveForward() {
+		t.Error("expected batch 3 to move forward once it is desired")
+	}
+}
+
+// TestReplan replans an update of 20 replicas in batches of 5, in batch 3 of the given phase.
+func TestReplan(t *testing.T) {
+	tests := []struct {
+		name      string
+		phase     tritonappsv1alpha1.BatchPhase
+		batchSize string
+		steps     []tritonappsv1alpha1.BatchStep
+		replanned bool
+		batches   int
+		current   plannedBatch
+	}{
+		{
+			name:      "unchanged",
+			phase:     tritonappsv1alpha1.BatchPending,
+			batchSize: "5",
+			batches:   4,
+			current:   plannedBatch{size: 5},
+		},
+		{
+			name:      "smaller batch size before current batch starts",
+			phase:     tritonappsv1alpha1.BatchPending,
+			batchSize: "2",
+			replanned: true,
+			batches:   7,
+			current:   plannedBatch{size: 2},
+		},
+		{
+			// current batch is in progress, only the batches after it are planned again.
+			name:      "smaller batch size while current batch is baking",
+			phase:     tritonappsv1alpha1.BatchBaking,
+			batchSize: "2",
+			replanned: true,
+			batches:   6,
+			current:   plannedBatch{size: 5},
+		},
+		{
+			// the last 5 pods are processed in one batch either way.
+			name:      "larger batch size after current batch is baked",
+			phase:     tritonappsv1alpha1.BatchBaked,
+			batchSize: "10",
+			batches:   4,
+			current:   plannedBatch{size: 5},
+		},
+		{
+			name:      "steps set before current batch starts",
+			phase:     tritonappsv1alpha1.BatchPending,
+			batchSize: "5",
+			steps:     newSteps("1", "5%", "25%", "50%", "100%"),
+			replanned: true,
+			batches:   3,
+			current:   plannedBatch{size: 10, step: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batchSize := intstr.FromInt(5)
+			d := newUpdateDeploy(20, &tritonappsv1alpha1.DeployUpdateStrategy{BaseStrategy: tritonappsv1alpha1.BaseStrategy{BatchSize: &batchSize}})
+			finishBatches(d, 2)
+			d.PrepareNewBatch()
+			c := d.CurrentBatchInfo()
+			if c.Phase = tt.phase; c.Phase == tritonappsv1alpha1.BatchBaked {
+				d.MarkCurrentBatchAsFinished()
+			} else {
+				d.SetCondition(*c)
+			}
+
+			batchSize = intstr.Parse(tt.batchSize)
+			d.Spec.UpdateStrategy.Steps = tt.steps
+			if got := d.Replan(); got != tt.replanned {
+				t.Fatalf("expected replanned %t, got %t", tt.replanned, got)
+			}
+
+			c = d.CurrentBatchInfo()
+			if got := (plannedBatch{size: c.BatchSize, step: c.Step}); d.Status.Batches != tt.batches || got != tt.current {
+				t.Errorf("expected %d batches and current batch %+v, got %d and %+v", tt.batches, tt.current, d.Status.Batches, got)
+			}
+			// finished batches are left alone.
+			for _, f := range d.Status.Conditions[:2] {
+				if f.BatchSize != 5 || f.Phase != tritonappsv1alpha1.BatchBaked {
+					t.Errorf("expected finished batch %d to be left alone, got %+v", f.Batch, f)
+				}
+			}
+
+			if !tt.replanned {
+				if len(d.Status.Replans) != 0 {
+					t.Errorf("expected no replan recorded, got %+v", d.Status.Replans)
+				}
+				return
+			}
+			if len(d.Status.Replans) != 1 {
+				t.Fatalf("expected a replan recorded, got %+v", d.Status.Replans)
+			}
+			if r := d.Status.Replans[0]; r.Batch != 3 || r.OldBatches != 4 || r.Batches != tt.batches || r.OldBatchSize != 5 || r.BatchSize != tt.current.size {
+				t.Errorf("unexpected replan %+v", r)
+			}
+		})
+	}
+
+	// nothing is left to plan once all batches are finished.
+	batchSize := intstr.FromInt(5)
+	d := newUpdateDeploy(20, &tritonappsv1alpha1.DeployUpdateStrategy{BaseStrategy: tritonappsv1alpha1.BaseStrategy{BatchSize: &batchSize}})
+	finishBatches(d, 4)
+	batchSize = intstr.FromInt(2)
+	if d.Replan() {
+		t.Errorf("expected a finished deploy not to be replanned, got %+v", d.Status.Replans)
+	}
+}
+
+func TestBakeRemaining(t *testing.T) {
+	var zero, twoMinutes int32 = 0, 120
+	pulledInAgo := func(d time.Duration) metav1.Time {
+		return metav1.NewTime(time.Now().Add(-d))
+	}
+
+	tests := []struct {
+		name              string
+		action            string
+		canaryBakeSeconds *int32
+		condition         *tritonappsv1alpha1.BatchCondition
+		expected          time.Duration
+	}{
+		{
+			name:      "baking",
+			condition: &tritonappsv1alpha1.BatchCondition{Phase: tritonappsv1alpha1.BatchBaking, PulledInAt: pulledInAgo(20 * time.Second)},
+			expected:  40 * time.Second,
+		},
+		{
+			name:      "bake time passed",
+			condition: &tritonappsv1alpha1.BatchCondition{Phase: tritonappsv1alpha1.BatchBaking, PulledInAt: pulledInAgo(90 * time.Second)},
+		},
+		{
+			name:      "not pulled in yet",
+			condition: &tritonappsv1alpha1.BatchCondition{Phase: tritonappsv1alpha1.BatchBaking},
+		},
+		{
+			name:      "not baking",
+			condition: &tritonappsv1alpha1.BatchCondition{Phase: tritonappsv1alpha1.BatchSmoked, PulledInAt: pulledInAgo(20 * time.Second)},
+		},
+		{
+			name:              "canary batch",
+			canaryBakeSeconds: &twoMinutes,
+			condition:         &tritonappsv1alpha1.BatchCondition{Canary: true, Phase: tritonappsv1alpha1.BatchBaking, PulledInAt: pulledInAgo(20 * time.Second)},
+			expected:          100 * time.Second,
+		},
+		{
+			name:              "canary batch without bake time",
+			canaryBakeSeconds: &zero,
+			condition:         &tritonappsv1alpha1.BatchCondition{Canary: true, Phase: tritonappsv1alpha1.BatchBaking, PulledInAt: pulledInAgo(20 * time.Second)},
+		},
+		{
+			name:      "canary batch defaults to bake time",
+			condition: &tritonappsv1alpha1.BatchCondition{Canary: true, Phase: tritonappsv1alpha1.BatchBaking, PulledInAt: pulledInAgo(20 * time.Second)},
+			expected:  40 * time.Second,
+		},
+		{
+			name:      "restart",
+			action:    setting.Restart,
+			condition: &tritonappsv1alpha1.BatchCondition{Phase: tritonappsv1alpha1.BatchBaking, PulledInAt: pulledInAgo(20 * time.Second)},
+		},
+		{
+			name: "no batch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newUpdateDeploy(10, &tritonappsv1alpha1.DeployUpdateStrategy{BakeSeconds: 60, CanaryBakeSeconds: tt.canaryBakeSeconds})
+			if tt.action != "" {
+				d.Spec.Action = tt.action
+			}
+
+			// the remaining time is counted from now, allow the time passed since the condition is built.
+			if got := d.BakeRemaining(tt.condition); got > tt.expected || got < tt.expected-time.Second {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFailureTolerance(t *testing.T) {
+	tests := []struct {
+		name              string
+		action            string
+		maxFailedReplicas int32
+		maxFailedPercent  int32
+		batchSize         int
+		expected          int
+	}{
+		{name: "no tolerance", batchSize: 10},
+		{name: "replicas", maxFailedReplicas: 2, batchSize: 10, expected: 2},
+		{name: "percent rounded down", maxFailedPercent: 25, batchSize: 10, expected: 2},
+		{name: "percent of a small batch", maxFailedPercent: 25, batchSize: 3},
+		{name: "smaller of replicas", maxFailedReplicas: 1, maxFailedPercent: 50, batchSize: 10, expected: 1},
+		{name: "smaller of percent", maxFailedReplicas: 4, maxFailedPercent: 20, batchSize: 10, expected: 2},
+		{name: "non-update strategy", action: setting.ScaleOut, maxFailedReplicas: 3, batchSize: 10, expected: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tritonappsv1alpha1.BaseStrategy{MaxFailedReplicas: tt.maxFailedReplicas, MaxFailedPercent: tt.maxFailedPercent}
+			d := newUpdateDeploy(10, &tritonappsv1alpha1.DeployUpdateStrategy{BaseStrategy: s})
+			if tt.action != "" {
+				// the update strategy is ignored by a non-update deploy.
+				d.Spec.Action = tt.action
+				d.Spec.UpdateStrategy.MaxFailedReplicas = 9
+				d.Spec.NonUpdateStrategy = &tritonappsv1alpha1.DeployNonUpdateStrategy{BaseStrategy: s}
+			}
+
+			if got := d.FailureTolerance(tt.batchSize); got != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestGetPatchBytes patches the workload of 10 replicas for batch 2, after 3 pods are updated in batch 1.
+func TestGetPatchBytes(t *testing.T) {
+	zone := tritonappsv1alpha1.BatchStep{
+		Replicas:     intstr.FromInt(6),
+		NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+	}
+
+	tests := []struct {
+		name            string
+		applicationType string
+		action          string
+		steps           []tritonappsv1alpha1.BatchStep
+		batchSize       int
+		step            int
+		phase           tritonappsv1alpha1.BatchPhase
+		expected        string
+	}{
+		{
+			// pods of the highest ordinals are updated in place by lowering the partition.
+			name:            "statefulset pending",
+			applicationType: tritonappsv1alpha1.ApplicationTypeStatefulSet,
+			batchSize:       3,
+			phase:           tritonappsv1alpha1.BatchPending,
+			expected:        `{"spec":{"updateStrategy":{"rollingUpdate":{"partition":4}}}}`,
+		},
+		{
+			name:            "statefulset partition not below zero",
+			applicationType: tritonappsv1alpha1.ApplicationTypeStatefulSet,
+			batchSize:       8,
+			phase:           tritonappsv1alpha1.BatchPending,
+			expected:        `{"spec":{"updateStrategy":{"rollingUpdate":{"partition":0}}}}`,
+		},
+		{
+			name:            "statefulset baking",
+			applicationType: tritonappsv1alpha1.ApplicationTypeStatefulSet,
+			batchSize:       3,
+			phase:           tritonappsv1alpha1.BatchBaking,
+		},
+		{
+			name:            "deployment pending",
+			applicationType: tritonappsv1alpha1.ApplicationTypeDeployment,
+			batchSize:       3,
+			phase:           tritonappsv1alpha1.BatchPending,
+			expected:        `{"spec":{"paused":false,"strategy":{"rollingUpdate":{"maxSurge":3,"maxUnavailable":0}}}}`,
+		},
+		{
+			// the Deployment stays paused until the pods of the next batch are created.
+			name:            "deployment baking",
+			applicationType: tritonappsv1alpha1.ApplicationTypeDeployment,
+			batchSize:       3,
+			phase:           tritonappsv1alpha1.BatchBaking,
+		},
+		{
+			name:            "deployment baking the last batch",
+			applicationType: tritonappsv1alpha1.ApplicationTypeDeployment,
+			batchSize:       7,
+			phase:           tritonappsv1alpha1.BatchBaking,
+			expected:        `{"spec":{"paused":false}}`,
+		},
+		{
+			name:            "daemonset pending",
+			applicationType: tritonappsv1alpha1.ApplicationTypeDaemonSet,
+			batchSize:       3,
+			phase:           tritonappsv1alpha1.BatchPending,
+			expected:        `{"spec":{"updateStrategy":{"rollingUpdate":{"partition":4,"selector":null}}}}`,
+		},
+		{
+			// the update is limited to the nodes selected by the step of the batch.
+			name:            "daemonset pending with node selector",
+			applicationType: tritonappsv1alpha1.ApplicationTypeDaemonSet,
+			steps:           []tritonappsv1alpha1.BatchStep{zone},
+			batchSize:       3,
+			step:            1,
+			phase:           tritonappsv1alpha1.BatchPending,
+			expected:        `{"spec":{"updateStrategy":{"rollingUpdate":{"partition":4,"selector":{"matchLabels":{"zone":"a"}}}}}}`,
+		},
+		{
+			name:            "daemonset baking",
+			applicationType: tritonappsv1alpha1.ApplicationTypeDaemonSet,
+			batchSize:       3,
+			phase:           tritonappsv1alpha1.BatchBaking,
+		},
+		{
+			// pods of a restart are deleted by the controller.
+			name:            "daemonset restart",
+			applicationType: tritonappsv1alpha1.ApplicationTypeDaemonSet,
+			action:          setting.Restart,
+			batchSize:       3,
+			phase:           tritonappsv1alpha1.BatchPending,
+		},
+		{
+			name:            "cloneset pending",
+			applicationType: tritonappsv1alpha1.ApplicationTypeCloneSet,
+			batchSize:       3,
+			phase:           tritonappsv1alpha1.BatchPending,
+			expected:        `{"spec":{"replicas":13}}`,
+		},
+		{
+			name:            "cloneset baking",
+			applicationType: tritonappsv1alpha1.ApplicationTypeCloneSet,
+			batchSize:       3,
+			phase:           tritonappsv1alpha1.BatchBaking,
+			expected:        `{"spec":{"replicas":10,"updateStrategy":{"partition":7}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newUpdateDeploy(10, &tritonappsv1alpha1.DeployUpdateStrategy{BaseStrategy: tritonappsv1alpha1.BaseStrategy{Steps: tt.steps}})
+			if tt.action != "" {
+				d.Spec.Action = tt.action
+			}
+			d.Spec.Application.ApplicationType = tt.applicationType
+			d.Status.Replicas = 10
+			d.Status.UpdatedReplicas = 3
+			d.Status.FinishedReplicas = 3
+			d.SetCondition(tritonappsv1alpha1.BatchCondition{Batch: 2, BatchSize: tt.batchSize, Step: tt.step, Phase: tt.phase})
+
+			if got := string(d.GetPatchBytes()); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPullInTimeout(t *testing.T) {
+	update := &tritonappsv1alpha1.DeployUpdateStrategy{BaseStrategy: tritonappsv1alpha1.BaseStrategy{PullInTimeoutSeconds: 60}}
+	nonUpdate := &tritonappsv1alpha1.DeployNonUpdateStrategy{BaseStrategy: tritonappsv1alpha1.BaseStrategy{PullInTimeoutSeconds: 30}}
+
+	tests := []struct {
+		name      string
+		action    string
+		update    *tritonappsv1alpha1.DeployUpdateStrategy
+		nonUpdate *tritonappsv1alpha1.DeployNonUpdateStrategy
+		expected  time.Duration
+	}{
+		{name: "update", action: setting.Update, update: update, nonUpdate: nonUpdate, expected: time.Minute},
+		{name: "restart", action: setting.Restart, update: update, nonUpdate: nonUpdate, expected: 30 * time.Second},
+		{name: "scale out without strategy", action: setting.ScaleOut, update: update, expected: defaultPullInTimeout},
+		{name: "update without strategy", action: setting.Update, nonUpdate: nonUpdate, expected: defaultPullInTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newUpdateDeploy(10, tt.update)
+			d.Spec.Action = tt.action
+			d.Spec.NonUpdateStrategy = tt.nonUpdate
+
+			if got := d.PullInTimeout(); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
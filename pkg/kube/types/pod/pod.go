@@ -160,10 +160,57 @@ func (p *Pod) PodReady() bool {
 }
 
 func SetPodReadinessGate(ns, name string, cl client.Client) error {
-	patchBytes := []byte(fmt.Sprintf(`{"status":{"conditions":[{"type":"%s", "status":"True"}]}}`, setting.PodReadinessGate))
+	return setPodReadinessGate(ns, name, corev1.ConditionTrue, cl)
+}
+
+// UnsetPodReadinessGate sets the readiness gate to False, the pod will be removed from endpoints of services.
+func UnsetPodReadinessGate(ns, name string, cl client.Client) error {
+	return setPodReadinessGate(ns, name, corev1.ConditionFalse, cl)
+}
+
+func setPodReadinessGate(ns, name string, status corev1.ConditionStatus, cl client.Client) error {
+	patchBytes := []byte(fmt.Sprintf(`{"status":{"conditions":[{"type":"%s", "status":"%s"}]}}`, setting.PodReadinessGate, status))
 	return patchPodStatus(ns, name, patchBytes, cl)
 }
 
+// HasReadinessGate returns true if the readiness gate of Triton is declared in the pod spec.
+func (p *Pod) HasReadinessGate() bool {
+	for _, g := range p.Spec.ReadinessGates {
+		if g.ConditionType == setting.PodReadinessGate {
+			return true
+		}
+	}
+	return false
+}
+
+// SetPodLabel sets a label of the pod, the label is removed if value is empty.
+func SetPodLabel(ns, name, key, value string, cl client.Client) error {
+	v := "null"
+	if value != "" {
+		v = fmt.Sprintf("%q", value)
+	}
+	patchBytes := []byte(fmt.Sprintf(`{"metadata":{"labels":{%q:%s}}}`, key, v))
+
+	return cl.Patch(context.TODO(), &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      name,
+		},
+	}, client.RawPatch(types.MergePatchType, patchBytes))
+}
+
+// SetPodAnnotation sets an annotation of the pod.
+func SetPodAnnotation(ns, name, key, value string, cl client.Client) error {
+	patchBytes := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, key, value))
+
+	return cl.Patch(context.TODO(), &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      name,
+		},
+	}, client.RawPatch(types.MergePatchType, patchBytes))
+}
+
 func DeletePod(ns, name string, cl client.Client) error {
 	err := cl.Delete(context.TODO(), &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,175 @@
+package workload
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	"github.com/triton-io/triton/pkg/setting"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Object is a workload object driven by a DeployFlow, ex: a CloneSet, an Advanced StatefulSet, a Deployment or an Advanced DaemonSet.
+type Object interface {
+	metav1.Object
+	runtime.Object
+}
+
+// Status is the replicas status of a workload.
+type Status struct {
+	ObservedGeneration int64
+	Replicas           int32
+	UpdatedReplicas    int32
+	// UpdatedReadyReplicas is only reported by a CloneSet, it is left 0 and should be counted from pods for other workloads.
+	UpdatedReadyReplicas int32
+	AvailableReplicas    int32
+	// UpdateRevision is left empty for a Deployment, it is the pod-template-hash of the new ReplicaSet.
+	UpdateRevision string
+}
+
+// New returns an empty workload object of the application type, a CloneSet if the type is empty.
+func New(applicationType string) (Object, error) {
+	switch applicationType {
+	case "", tritonappsv1alpha1.ApplicationTypeCloneSet:
+		return &kruiseappsv1alpha1.CloneSet{}, nil
+	case tritonappsv1alpha1.ApplicationTypeStatefulSet:
+		return &kruiseappsv1alpha1.StatefulSet{}, nil
+	case tritonappsv1alpha1.ApplicationTypeDeployment:
+		return &appsv1.Deployment{}, nil
+	case tritonappsv1alpha1.ApplicationTypeDaemonSet:
+		return &kruiseappsv1alpha1.DaemonSet{}, nil
+	default:
+		return nil, fmt.Errorf("unknown application type %q", applicationType)
+	}
+}
+
+// ApplicationTypeOf returns the application type of the workload object, empty if it is not a workload.
+func ApplicationTypeOf(obj Object) string {
+	switch obj.(type) {
+	case *kruiseappsv1alpha1.CloneSet:
+		return tritonappsv1alpha1.ApplicationTypeCloneSet
+	case *kruiseappsv1alpha1.StatefulSet:
+		return tritonappsv1alpha1.ApplicationTypeStatefulSet
+	case *appsv1.Deployment:
+		return tritonappsv1alpha1.ApplicationTypeDeployment
+	case *kruiseappsv1alpha1.DaemonSet:
+		return tritonappsv1alpha1.ApplicationTypeDaemonSet
+	default:
+		return ""
+	}
+}
+
+// StatusOf returns the replicas status of the workload object.
+func StatusOf(obj Object) Status {
+	switch o := obj.(type) {
+	case *kruiseappsv1alpha1.CloneSet:
+		return Status{
+			ObservedGeneration:   o.Status.ObservedGeneration,
+			Replicas:             o.Status.Replicas,
+			UpdatedReplicas:      o.Status.UpdatedReplicas,
+			UpdatedReadyReplicas: o.Status.UpdatedReadyReplicas,
+			AvailableReplicas:    o.Status.AvailableReplicas,
+			UpdateRevision:       o.Status.UpdateRevision,
+		}
+	case *kruiseappsv1alpha1.StatefulSet:
+		return Status{
+			ObservedGeneration: o.Status.ObservedGeneration,
+			Replicas:           o.Status.Replicas,
+			UpdatedReplicas:    o.Status.UpdatedReplicas,
+			AvailableReplicas:  o.Status.AvailableReplicas,
+			UpdateRevision:     o.Status.UpdateRevision,
+		}
+	case *appsv1.Deployment:
+		return Status{
+			ObservedGeneration: o.Status.ObservedGeneration,
+			Replicas:           o.Status.Replicas,
+			UpdatedReplicas:    o.Status.UpdatedReplicas,
+			AvailableReplicas:  o.Status.AvailableReplicas,
+		}
+	case *kruiseappsv1alpha1.DaemonSet:
+		// a DaemonSet runs a pod on each node it is scheduled to.
+		return Status{
+			ObservedGeneration: o.Status.ObservedGeneration,
+			Replicas:           o.Status.DesiredNumberScheduled,
+			UpdatedReplicas:    o.Status.UpdatedNumberScheduled,
+			AvailableReplicas:  o.Status.NumberAvailable,
+			UpdateRevision:     o.Status.DaemonSetHash,
+		}
+	default:
+		return Status{}
+	}
+}
+
+// SpecReplicas returns the desired replicas of the workload object, nil for a DaemonSet.
+func SpecReplicas(obj Object) *int32 {
+	switch o := obj.(type) {
+	case *kruiseappsv1alpha1.CloneSet:
+		return o.Spec.Replicas
+	case *kruiseappsv1alpha1.StatefulSet:
+		return o.Spec.Replicas
+	case *appsv1.Deployment:
+		return o.Spec.Replicas
+	default:
+		return nil
+	}
+}
+
+// TemplateOf returns the pod template of the workload object.
+func TemplateOf(obj Object) *corev1.PodTemplateSpec {
+	switch o := obj.(type) {
+	case *kruiseappsv1alpha1.CloneSet:
+		return &o.Spec.Template
+	case *kruiseappsv1alpha1.StatefulSet:
+		return &o.Spec.Template
+	case *appsv1.Deployment:
+		return &o.Spec.Template
+	case *kruiseappsv1alpha1.DaemonSet:
+		return &o.Spec.Template
+	default:
+		return nil
+	}
+}
+
+// ApplicationOf returns the app ID, group ID and app name of the workload object, taken from its default labels.
+func ApplicationOf(obj Object) (appID, groupID int, appName string) {
+	ls := obj.GetLabels()
+	appID, _ = strconv.Atoi(ls[setting.AppIDLabel])
+	groupID, _ = strconv.Atoi(ls[setting.GroupIDLabel])
+	return appID, groupID, ls[setting.AppLabel]
+}
+
+// Synced returns true if the status of the workload object is updated for its latest spec.
+func Synced(obj Object) bool {
+	return obj.GetGeneration() == StatusOf(obj).ObservedGeneration
+}
+
+// VolumeClaimTemplates returns the PVC templates of the workload object, nil for a Deployment or a DaemonSet.
+func VolumeClaimTemplates(obj Object) []corev1.PersistentVolumeClaim {
+	switch o := obj.(type) {
+	case *kruiseappsv1alpha1.CloneSet:
+		return o.Spec.VolumeClaimTemplates
+	case *kruiseappsv1alpha1.StatefulSet:
+		return o.Spec.VolumeClaimTemplates
+	default:
+		return nil
+	}
+}
+
+// PVCRetentionPolicyOf returns the PVC retention policy recorded in the annotations of the workload object, nil if it is not set.
+func PVCRetentionPolicyOf(obj Object) *tritonappsv1alpha1.PersistentVolumeClaimRetentionPolicy {
+	v := obj.GetAnnotations()[setting.PVCRetentionPolicyAnnotation]
+	if v == "" {
+		return nil
+	}
+
+	policy := &tritonappsv1alpha1.PersistentVolumeClaimRetentionPolicy{}
+	if err := json.Unmarshal([]byte(v), policy); err != nil {
+		return nil
+	}
+	return policy
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var httpRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1beta1", Kind: "HTTPRoute"}
+
+// httpRouteRouter copies the rules to the stable Service into a canary HTTPRoute attached to the same parents,
+// with the header matches added. Gateway API prefers the rule with more header matches, so matched requests go to canary.
+// Requests are split by the backend weights of the stable HTTPRoute.
+type httpRouteRouter struct{}
+
+var _ router = &httpRouteRouter{}
+
+func (r *httpRouteRouter) ensure(c *Canary, service string) error {
+	headers := c.headerMatches()
+	if len(headers) == 0 {
+		return r.remove(c)
+	}
+
+	stable := newHTTPRoute(c.Owner.Namespace, c.Routing.Route)
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: c.Owner.Namespace, Name: c.Routing.Route}, stable); err != nil {
+		return err
+	}
+
+	stableRules, err := httpRouteStableRules(stable)
+	if err != nil {
+		return err
+	}
+	rules := canaryRules(stableRules, c.Routing.Service, service, headers)
+	if len(rules) == 0 {
+		return fmt.Errorf("httproute %s has no rule to service %s", c.Routing.Route, c.Routing.Service)
+	}
+
+	canary := newHTTPRoute(c.Owner.Namespace, CanaryName(c.Routing.Route))
+	_, err = controllerutil.CreateOrUpdate(context.TODO(), c.Client, canary, func() error {
+		canary.SetLabels(stable.GetLabels())
+		canary.SetOwnerReferences(c.ownerReferences())
+
+		spec := map[string]interface{}{"rules": rules}
+		for _, f := range []string{"parentRefs", "hostnames"} {
+			if v, found, _ := unstructured.NestedFieldCopy(stable.Object, "spec", f); found {
+				spec[f] = v
+			}
+		}
+		return unstructured.SetNestedField(canary.Object, spec, "spec")
+	})
+
+	return err
+}
+
+func (*httpRouteRouter) remove(c *Canary) error {
+	return c.removeObject(newHTTPRoute(c.Owner.Namespace, CanaryName(c.Routing.Route)))
+}
+
+func (*httpRouteRouter) shift(c *Canary, stable, canary string, weight int32) error {
+	route := newHTTPRoute(c.Owner.Namespace, c.Routing.Route)
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: c.Owner.Namespace, Name: c.Routing.Route}, route); err != nil {
+		return err
+	}
+
+	rules, err := httpRouteStableRules(route)
+	if err != nil {
+		return err
+	}
+	annotations := route.GetAnnotations()
+	if _, ok := annotations[stableRulesAnnotation]; !ok {
+		raw, err := json.Marshal(rules)
+		if err != nil {
+			return err
+		}
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[stableRulesAnnotation] = string(raw)
+		route.SetAnnotations(annotations)
+	}
+
+	if err := unstructured.SetNestedSlice(route.Object, weightedRules(rules, c.Routing.Service, stable, canary, weight), "spec", "rules"); err != nil {
+		return err
+	}
+
+	return c.Update(context.TODO(), route)
+}
+
+func (*httpRouteRouter) restore(c *Canary) error {
+	route := newHTTPRoute(c.Owner.Namespace, c.Routing.Route)
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: c.Owner.Namespace, Name: c.Routing.Route}, route); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	annotations := route.GetAnnotations()
+	if _, ok := annotations[stableRulesAnnotation]; !ok {
+		return nil
+	}
+	rules, err := httpRouteStableRules(route)
+	if err != nil {
+		return err
+	}
+	delete(annotations, stableRulesAnnotation)
+	route.SetAnnotations(annotations)
+	if err := unstructured.SetNestedSlice(route.Object, rules, "spec", "rules"); err != nil {
+		return err
+	}
+
+	return c.Update(context.TODO(), route)
+}
+
+func newHTTPRoute(ns, name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(httpRouteGVK)
+	u.SetNamespace(ns)
+	u.SetName(name)
+
+	return u
+}
+
+// httpRouteStableRules returns the rules of the stable HTTPRoute before requests are split by weight.
+func httpRouteStableRules(route *unstructured.Unstructured) ([]interface{}, error) {
+	if raw, ok := route.GetAnnotations()[stableRulesAnnotation]; ok {
+		var rules []interface{}
+		// numbers are decoded as int64 as the unstructured ones.
+		if err := utiljson.Unmarshal([]byte(raw), &rules); err != nil {
+			return nil, fmt.Errorf("invalid annotation %s: %w", stableRulesAnnotation, err)
+		}
+		return rules, nil
+	}
+
+	rules, _, err := unstructured.NestedSlice(route.Object, "spec", "rules")
+	return rules, err
+}
+
+// canaryRules copies rules sending requests to the stable Service, the backends are replaced by the canary Service
+// and the header matches are added to every match.
+func canaryRules(rules []interface{}, stableService, canaryService string, headers []interface{}) []interface{} {
+	var res []interface{}
+	for _, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		port, found := backendPort(rule, stableService)
+		if !found {
+			continue
+		}
+
+		backend := map[string]interface{}{"name": canaryService}
+		if port != nil {
+			backend["port"] = port
+		}
+		canary := map[string]interface{}{"backendRefs": []interface{}{backend}}
+		if filters, ok := rule["filters"]; ok {
+			canary["filters"] = runtime.DeepCopyJSONValue(filters)
+		}
+
+		matches, _ := rule["matches"].([]interface{})
+		if len(matches) == 0 {
+			matches = []interface{}{map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/"}}}
+		}
+		canaryMatches := make([]interface{}, 0, len(matches))
+		for _, m := range matches {
+			match, ok := runtime.DeepCopyJSONValue(m).(map[string]interface{})
+			if !ok {
+				continue
+			}
+			h, _ := match["headers"].([]interface{})
+			match["headers"] = append(h, runtime.DeepCopyJSONValue(headers).([]interface{})...)
+			canaryMatches = append(canaryMatches, match)
+		}
+		canary["matches"] = canaryMatches
+
+		res = append(res, canary)
+	}
+
+	return res
+}
+
+// weightedRules replaces the backend of the stable Service by the stable and canary Services weighted by the percent.
+// Weights of other backends in the same rule are scaled up, so that they get the same share as before.
+func weightedRules(rules []interface{}, service, stable, canary string, weight int32) []interface{} {
+	res := runtime.DeepCopyJSONValue(rules).([]interface{})
+	for _, r := range res {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, found := backendPort(rule, service); !found {
+			continue
+		}
+
+		refs, _ := rule["backendRefs"].([]interface{})
+		weighted := make([]interface{}, 0, len(refs)+1)
+		for _, ref := range refs {
+			ref, ok := ref.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			w := int64(1)
+			if v, ok := ref["weight"].(int64); ok {
+				w = v
+			}
+			if !isServiceRef(ref, service) {
+				ref["weight"] = w * 100
+				weighted = append(weighted, ref)
+				continue
+			}
+
+			canaryRef := runtime.DeepCopyJSONValue(ref).(map[string]interface{})
+			ref["name"], ref["weight"] = stable, w*int64(100-weight)
+			canaryRef["name"], canaryRef["weight"] = canary, w*int64(weight)
+			weighted = append(weighted, ref, canaryRef)
+		}
+		rule["backendRefs"] = weighted
+	}
+
+	return res
+}
+
+// backendPort returns the port of the backend referring to the Service, the port is nil if it is not set.
+func backendPort(rule map[string]interface{}, service string) (interface{}, bool) {
+	refs, _ := rule["backendRefs"].([]interface{})
+	for _, r := range refs {
+		if ref, ok := r.(map[string]interface{}); ok && isServiceRef(ref, service) {
+			return ref["port"], true
+		}
+	}
+
+	return nil, false
+}
+
+func isServiceRef(ref map[string]interface{}, service string) bool {
+	if kind, ok := ref["kind"]; ok && kind != "Service" {
+		return false
+	}
+
+	return ref["name"] == service
+}
+
+// headerMatches returns the HTTPHeaderMatch list of the headers and the cookie.
+func (c *Canary) headerMatches() []interface{} {
+	res := make([]interface{}, 0, len(c.Routing.Headers)+1)
+	for _, h := range c.Routing.Headers {
+		res = append(res, map[string]interface{}{"type": "Exact", "name": h.Name, "value": h.Value})
+	}
+	if cookie := c.Routing.Cookie; cookie != nil {
+		value := fmt.Sprintf(`(^|;\s*)%s=%s(;|$)`, regexp.QuoteMeta(cookie.Name), regexp.QuoteMeta(cookie.Value))
+		res = append(res, map[string]interface{}{"type": "RegularExpression", "name": "Cookie", "value": value})
+	}
+
+	return res
+}
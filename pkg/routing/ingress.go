@@ -0,0 +1,205 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	ingressClassAnnotation = "kubernetes.io/ingress.class"
+
+	nginxCanary              = "nginx.ingress.kubernetes.io/canary"
+	nginxCanaryByHeader      = "nginx.ingress.kubernetes.io/canary-by-header"
+	nginxCanaryByHeaderValue = "nginx.ingress.kubernetes.io/canary-by-header-value"
+	nginxCanaryByCookie      = "nginx.ingress.kubernetes.io/canary-by-cookie"
+	nginxCanaryWeight        = "nginx.ingress.kubernetes.io/canary-weight"
+)
+
+// ingressRouter copies the paths to the stable Service into a canary Ingress, which is recognized by
+// NGINX Ingress by the canary annotations. NGINX Ingress supports only one header and matches the cookie value "always".
+// Requests are split by the canary-weight annotation, while the stable Ingress sends the rest to the stable Service.
+type ingressRouter struct{}
+
+var _ router = &ingressRouter{}
+
+func (r *ingressRouter) ensure(c *Canary, service string) error {
+	if len(c.Routing.Headers) == 0 && c.Routing.Cookie == nil && c.Weight == nil {
+		return r.remove(c)
+	}
+
+	stable := &networkingv1beta1.Ingress{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: c.Owner.Namespace, Name: c.Routing.Route}, stable); err != nil {
+		return err
+	}
+
+	stableRules, err := ingressStableRules(stable)
+	if err != nil {
+		return err
+	}
+	rules := canaryIngressRules(stableRules, c.Routing.Service, service)
+	if len(rules) == 0 {
+		return fmt.Errorf("ingress %s has no path to service %s", c.Routing.Route, c.Routing.Service)
+	}
+
+	canary := &networkingv1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: c.Owner.Namespace, Name: CanaryName(c.Routing.Route)}}
+	_, err = controllerutil.CreateOrUpdate(context.TODO(), c.Client, canary, func() error {
+		canary.Labels = stable.Labels
+		canary.OwnerReferences = c.ownerReferences()
+		canary.Annotations = c.ingressAnnotations(stable)
+		canary.Spec = networkingv1beta1.IngressSpec{
+			IngressClassName: stable.Spec.IngressClassName,
+			Rules:            rules,
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+func (*ingressRouter) remove(c *Canary) error {
+	return c.removeObject(&networkingv1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: c.Owner.Namespace, Name: CanaryName(c.Routing.Route)}})
+}
+
+// shift points the stable Ingress to the stable Service, the weight is set on the canary Ingress in ensure.
+func (*ingressRouter) shift(c *Canary, stable, _ string, _ int32) error {
+	ing := &networkingv1beta1.Ingress{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: c.Owner.Namespace, Name: c.Routing.Route}, ing); err != nil {
+		return err
+	}
+	if _, ok := ing.Annotations[stableRulesAnnotation]; ok {
+		return nil
+	}
+
+	raw, err := json.Marshal(ing.Spec.Rules)
+	if err != nil {
+		return err
+	}
+	if ing.Annotations == nil {
+		ing.Annotations = map[string]string{}
+	}
+	ing.Annotations[stableRulesAnnotation] = string(raw)
+	for _, r := range ing.Spec.Rules {
+		if r.HTTP == nil {
+			continue
+		}
+		for i := range r.HTTP.Paths {
+			if b := &r.HTTP.Paths[i].Backend; b.ServiceName == c.Routing.Service {
+				b.ServiceName = stable
+			}
+		}
+	}
+
+	return c.Update(context.TODO(), ing)
+}
+
+func (*ingressRouter) restore(c *Canary) error {
+	ing := &networkingv1beta1.Ingress{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: c.Owner.Namespace, Name: c.Routing.Route}, ing); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if _, ok := ing.Annotations[stableRulesAnnotation]; !ok {
+		return nil
+	}
+
+	rules, err := ingressStableRules(ing)
+	if err != nil {
+		return err
+	}
+	delete(ing.Annotations, stableRulesAnnotation)
+	ing.Spec.Rules = rules
+
+	return c.Update(context.TODO(), ing)
+}
+
+// ingressStableRules returns the rules of the stable Ingress before requests are split by weight.
+func ingressStableRules(ing *networkingv1beta1.Ingress) ([]networkingv1beta1.IngressRule, error) {
+	raw, ok := ing.Annotations[stableRulesAnnotation]
+	if !ok {
+		return ing.Spec.Rules, nil
+	}
+
+	var rules []networkingv1beta1.IngressRule
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		return nil, fmt.Errorf("invalid annotation %s: %w", stableRulesAnnotation, err)
+	}
+
+	return rules, nil
+}
+
+// canaryIngressRules copies the paths to the stable Service, the backends are replaced by the canary Service.
+func canaryIngressRules(rules []networkingv1beta1.IngressRule, stableService, canaryService string) []networkingv1beta1.IngressRule {
+	var res []networkingv1beta1.IngressRule
+	for _, r := range rules {
+		if r.HTTP == nil {
+			continue
+		}
+
+		var paths []networkingv1beta1.HTTPIngressPath
+		for _, p := range r.HTTP.Paths {
+			if p.Backend.ServiceName != stableService {
+				continue
+			}
+			p.Backend.ServiceName = canaryService
+			paths = append(paths, p)
+		}
+		if len(paths) == 0 {
+			continue
+		}
+
+		res = append(res, networkingv1beta1.IngressRule{
+			Host: r.Host,
+			IngressRuleValue: networkingv1beta1.IngressRuleValue{
+				HTTP: &networkingv1beta1.HTTPIngressRuleValue{Paths: paths},
+			},
+		})
+	}
+
+	return res
+}
+
+func (c *Canary) ingressAnnotations(stable *networkingv1beta1.Ingress) map[string]string {
+	a := map[string]string{nginxCanary: "true"}
+	if class, ok := stable.Annotations[ingressClassAnnotation]; ok {
+		a[ingressClassAnnotation] = class
+	}
+	if len(c.Routing.Headers) > 0 {
+		a[nginxCanaryByHeader] = c.Routing.Headers[0].Name
+		a[nginxCanaryByHeaderValue] = c.Routing.Headers[0].Value
+	}
+	if c.Routing.Cookie != nil {
+		a[nginxCanaryByCookie] = c.Routing.Cookie.Name
+	}
+	if c.Weight != nil {
+		a[nginxCanaryWeight] = strconv.Itoa(int(*c.Weight))
+	}
+
+	return a
+}
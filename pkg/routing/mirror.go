@@ -0,0 +1,302 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routing
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=networking.istio.io,resources=virtualservices,verbs=get;list;watch;update;patch
+
+var virtualServiceGVK = schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1beta1", Kind: "VirtualService"}
+
+const requestMirrorFilter = "RequestMirror"
+
+// Mirror mirrors requests sent to the stable Service to the canary Service, by the mirror rule of the routes
+// in an Istio VirtualService, or a RequestMirror filter added to the rules of a Gateway API HTTPRoute.
+// Proxies send the mirrored requests in a fire-and-forget way, responses of the canary pods are discarded.
+type Mirror struct {
+	client.Client
+	Owner     *tritonappsv1alpha1.DeployFlow
+	Shadowing *tritonappsv1alpha1.Shadowing
+}
+
+func NewMirror(cl client.Client, owner *tritonappsv1alpha1.DeployFlow, shadowing *tritonappsv1alpha1.Shadowing) *Mirror {
+	return &Mirror{Client: cl, Owner: owner, Shadowing: shadowing}
+}
+
+// Ensure creates or updates the canary Service, and mirrors requests to it.
+func (m *Mirror) Ensure() error {
+	svc, err := m.canary().ensureService(true)
+	if err != nil {
+		return fmt.Errorf("failed to ensure canary service: %w", err)
+	}
+
+	return m.updateRoute(func(rules []interface{}) ([]interface{}, error) {
+		res, found := m.mirroredRules(rules, svc)
+		if !found {
+			return nil, fmt.Errorf("%s %s has no route to service %s", strings.ToLower(string(m.Shadowing.Type)), m.Shadowing.Route, m.Shadowing.Service)
+		}
+		return res, nil
+	})
+}
+
+// Stop removes the mirror to the canary Service, it is fine if the route does not exist.
+func (m *Mirror) Stop() error {
+	err := m.updateRoute(func(rules []interface{}) ([]interface{}, error) {
+		return m.unmirroredRules(rules, CanaryName(m.Shadowing.Service)), nil
+	})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// Remove stops the mirror and removes the canary Service.
+func (m *Mirror) Remove() error {
+	if err := m.Stop(); err != nil {
+		return fmt.Errorf("failed to stop mirroring: %w", err)
+	}
+
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: m.Owner.Namespace, Name: CanaryName(m.Shadowing.Service)}}
+	if err := m.canary().removeObject(svc); err != nil {
+		return fmt.Errorf("failed to remove service %s: %w", svc.Name, err)
+	}
+
+	return nil
+}
+
+// canary returns the Canary sharing the canary Service with the mirror.
+func (m *Mirror) canary() *Canary {
+	return &Canary{
+		Client:  m.Client,
+		Owner:   m.Owner,
+		Routing: &tritonappsv1alpha1.CanaryRouting{Service: m.Shadowing.Service},
+	}
+}
+
+// updateRoute updates the routes of the VirtualService or the rules of the HTTPRoute if they are changed by update.
+func (m *Mirror) updateRoute(update func([]interface{}) ([]interface{}, error)) error {
+	route, field, err := m.newRoute()
+	if err != nil {
+		return err
+	}
+	if err := m.Get(context.TODO(), types.NamespacedName{Namespace: m.Owner.Namespace, Name: m.Shadowing.Route}, route); err != nil {
+		return err
+	}
+
+	rules, _, err := unstructured.NestedSlice(route.Object, "spec", field)
+	if err != nil {
+		return err
+	}
+	res, err := update(rules)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(rules, res) {
+		return nil
+	}
+	if err := unstructured.SetNestedSlice(route.Object, res, "spec", field); err != nil {
+		return err
+	}
+
+	return m.Update(context.TODO(), route)
+}
+
+// newRoute returns an empty route object and the field of its rules.
+func (m *Mirror) newRoute() (*unstructured.Unstructured, string, error) {
+	u := &unstructured.Unstructured{}
+	u.SetNamespace(m.Owner.Namespace)
+	u.SetName(m.Shadowing.Route)
+
+	switch m.Shadowing.Type {
+	case tritonappsv1alpha1.MirrorVirtualService:
+		u.SetGroupVersionKind(virtualServiceGVK)
+		return u, "http", nil
+	case tritonappsv1alpha1.MirrorHTTPRoute:
+		u.SetGroupVersionKind(httpRouteGVK)
+		return u, "rules", nil
+	default:
+		return nil, "", fmt.Errorf("unknown mirror type %q", m.Shadowing.Type)
+	}
+}
+
+func (m *Mirror) mirroredRules(rules []interface{}, canary string) ([]interface{}, bool) {
+	if m.Shadowing.Type == tritonappsv1alpha1.MirrorVirtualService {
+		return mirroredHTTPRoutes(rules, m.Owner.Namespace, m.Shadowing.Service, canary, m.Shadowing.Percent)
+	}
+	return mirroredRules(rules, m.Shadowing.Service, canary, m.Shadowing.Percent)
+}
+
+func (m *Mirror) unmirroredRules(rules []interface{}, canary string) []interface{} {
+	if m.Shadowing.Type == tritonappsv1alpha1.MirrorVirtualService {
+		return unmirroredHTTPRoutes(rules, canary)
+	}
+	return unmirroredRules(rules, canary)
+}
+
+// mirroredHTTPRoutes sets the mirror of VirtualService routes with a destination of the stable Service to the canary Service,
+// it returns false if no route is found.
+func mirroredHTTPRoutes(routes []interface{}, ns, service, canary string, percent *int32) ([]interface{}, bool) {
+	res := runtime.DeepCopyJSONValue(routes).([]interface{})
+	found := false
+	for _, r := range res {
+		route, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		port, matched := destinationPort(route, ns, service)
+		if !matched {
+			continue
+		}
+
+		mirror := map[string]interface{}{"host": canary}
+		if port != nil {
+			mirror["port"] = port
+		}
+		route["mirror"] = mirror
+		if percent != nil {
+			route["mirrorPercentage"] = map[string]interface{}{"value": float64(*percent)}
+		} else {
+			delete(route, "mirrorPercentage")
+		}
+		found = true
+	}
+
+	return res, found
+}
+
+// unmirroredHTTPRoutes removes the mirror to the canary Service from VirtualService routes.
+func unmirroredHTTPRoutes(routes []interface{}, canary string) []interface{} {
+	res := runtime.DeepCopyJSONValue(routes).([]interface{})
+	for _, r := range res {
+		route, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if host, _, _ := unstructured.NestedString(route, "mirror", "host"); host != canary {
+			continue
+		}
+		delete(route, "mirror")
+		delete(route, "mirrorPercentage")
+	}
+
+	return res
+}
+
+// destinationPort returns the port of the destination which is the stable Service, the port is nil if it is not set.
+func destinationPort(route map[string]interface{}, ns, service string) (interface{}, bool) {
+	destinations, _ := route["route"].([]interface{})
+	for _, d := range destinations {
+		dest, ok := d.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		host, _, _ := unstructured.NestedString(dest, "destination", "host")
+		if !isServiceHost(host, ns, service) {
+			continue
+		}
+
+		port, _, _ := unstructured.NestedFieldCopy(dest, "destination", "port")
+		return port, true
+	}
+
+	return nil, false
+}
+
+// isServiceHost returns true if host is the short name or a qualified name of the Service.
+func isServiceHost(host, ns, service string) bool {
+	return host == service || host == service+"."+ns || strings.HasPrefix(host, service+"."+ns+".svc")
+}
+
+// mirroredRules adds a RequestMirror filter to the canary Service to HTTPRoute rules sending requests to the stable Service,
+// it returns false if no rule is found.
+func mirroredRules(rules []interface{}, service, canary string, percent *int32) ([]interface{}, bool) {
+	res := unmirroredRules(rules, canary)
+	found := false
+	for _, r := range res {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		port, matched := backendPort(rule, service)
+		if !matched {
+			continue
+		}
+
+		backend := map[string]interface{}{"name": canary}
+		if port != nil {
+			backend["port"] = port
+		}
+		mirror := map[string]interface{}{"backendRef": backend}
+		if percent != nil {
+			mirror["percent"] = int64(*percent)
+		}
+		filters, _ := rule["filters"].([]interface{})
+		rule["filters"] = append(filters, map[string]interface{}{"type": requestMirrorFilter, "requestMirror": mirror})
+		found = true
+	}
+
+	return res, found
+}
+
+// unmirroredRules removes the RequestMirror filters to the canary Service from HTTPRoute rules.
+func unmirroredRules(rules []interface{}, canary string) []interface{} {
+	res := runtime.DeepCopyJSONValue(rules).([]interface{})
+	for _, r := range res {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		filters, ok := rule["filters"].([]interface{})
+		if !ok {
+			continue
+		}
+
+		kept := make([]interface{}, 0, len(filters))
+		for _, f := range filters {
+			filter, ok := f.(map[string]interface{})
+			if ok && filter["type"] == requestMirrorFilter {
+				if name, _, _ := unstructured.NestedString(filter, "requestMirror", "backendRef", "name"); name == canary {
+					continue
+				}
+			}
+			kept = append(kept, f)
+		}
+		if len(kept) == 0 {
+			delete(rule, "filters")
+		} else {
+			rule["filters"] = kept
+		}
+	}
+
+	return res
+}
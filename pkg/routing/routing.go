@@ -0,0 +1,198 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package routing routes part of requests to canary pods through a canary Service,
+// by a Gateway API HTTPRoute or an NGINX Ingress copied from the stable one.
+// Requests can also be split by weight between the canary Service and a stable Service selecting the pods not updated yet,
+// or mirrored to the canary Service by an Istio VirtualService or a Gateway API HTTPRoute.
+package routing
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	"github.com/triton-io/triton/pkg/setting"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
+
+// stableRulesAnnotation keeps the rules of the stable route before requests are split by weight, they are restored on Remove.
+const stableRulesAnnotation = "apps.triton.io/stable-rules"
+
+// router creates and removes the canary route copied from the stable one.
+type router interface {
+	// ensure routes the matched requests to the canary Service, the canary route is removed if nothing is matched.
+	ensure(c *Canary, service string) error
+	remove(c *Canary) error
+	// shift sends the weight percent of requests to the canary Service and the rest to the stable one.
+	shift(c *Canary, stable, canary string, weight int32) error
+	// restore sends requests back to the original backends of the stable route.
+	restore(c *Canary) error
+}
+
+// Canary manages the canary Service and the canary route of a deploy, both are owned by the deploy.
+type Canary struct {
+	client.Client
+	Owner   *tritonappsv1alpha1.DeployFlow
+	Routing *tritonappsv1alpha1.CanaryRouting
+	// Weight is the percent of requests routed to the canary Service, nil if requests are not split by weight.
+	Weight *int32
+}
+
+func New(cl client.Client, owner *tritonappsv1alpha1.DeployFlow, routing *tritonappsv1alpha1.CanaryRouting) *Canary {
+	return &Canary{Client: cl, Owner: owner, Routing: routing}
+}
+
+// WithWeight sets the percent of requests routed to the canary Service.
+func (c *Canary) WithWeight(weight *int32) *Canary {
+	c.Weight = weight
+	return c
+}
+
+// CanaryName returns the name of the canary Service or route copied from a stable one.
+func CanaryName(name string) string {
+	return name + "-canary"
+}
+
+// StableName returns the name of the Service selecting the pods not updated yet.
+func StableName(name string) string {
+	return name + "-stable"
+}
+
+// Ensure creates or updates the canary Service and route, and splits requests by weight if it is set.
+func (c *Canary) Ensure() error {
+	r, err := c.router()
+	if err != nil {
+		return err
+	}
+	svc, err := c.ensureService(true)
+	if err != nil {
+		return fmt.Errorf("failed to ensure canary service: %w", err)
+	}
+	if err := r.ensure(c, svc); err != nil {
+		return fmt.Errorf("failed to ensure canary route: %w", err)
+	}
+	if c.Weight == nil {
+		return nil
+	}
+
+	stable, err := c.ensureService(false)
+	if err != nil {
+		return fmt.Errorf("failed to ensure stable service: %w", err)
+	}
+	if err := r.shift(c, stable, svc, *c.Weight); err != nil {
+		return fmt.Errorf("failed to shift traffic: %w", err)
+	}
+
+	return nil
+}
+
+// Remove restores the stable route, and removes the canary route and the Services created, it is fine if they do not exist.
+func (c *Canary) Remove() error {
+	r, err := c.router()
+	if err != nil {
+		return err
+	}
+	if err := r.restore(c); err != nil {
+		return fmt.Errorf("failed to restore stable route: %w", err)
+	}
+	if err := r.remove(c); err != nil {
+		return fmt.Errorf("failed to remove canary route: %w", err)
+	}
+
+	for _, name := range []string{CanaryName(c.Routing.Service), StableName(c.Routing.Service)} {
+		svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: c.Owner.Namespace, Name: name}}
+		if err := c.removeObject(svc); err != nil {
+			return fmt.Errorf("failed to remove service %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Canary) router() (router, error) {
+	switch c.Routing.Type {
+	case tritonappsv1alpha1.RoutingHTTPRoute:
+		return &httpRouteRouter{}, nil
+	case tritonappsv1alpha1.RoutingIngress:
+		return &ingressRouter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown routing type %q", c.Routing.Type)
+	}
+}
+
+// ensureService copies the stable Service to select canary pods only, or the pods not updated yet. Not ready addresses
+// of canary pods are published, so that they serve routed requests before they are pulled in.
+func (c *Canary) ensureService(canary bool) (string, error) {
+	stable := &corev1.Service{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: c.Owner.Namespace, Name: c.Routing.Service}, stable); err != nil {
+		return "", err
+	}
+
+	name := StableName(stable.Name)
+	if canary {
+		name = CanaryName(stable.Name)
+	}
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: c.Owner.Namespace, Name: name}}
+	_, err := controllerutil.CreateOrUpdate(context.TODO(), c.Client, svc, func() error {
+		svc.Labels = stable.Labels
+		svc.OwnerReferences = c.ownerReferences()
+
+		selector := make(map[string]string, len(stable.Spec.Selector)+1)
+		for k, v := range stable.Spec.Selector {
+			selector[k] = v
+		}
+		selector[setting.CanaryLabel] = strconv.FormatBool(canary)
+		svc.Spec.Selector = selector
+
+		ports := make([]corev1.ServicePort, 0, len(stable.Spec.Ports))
+		for _, p := range stable.Spec.Ports {
+			p.NodePort = 0
+			ports = append(ports, p)
+		}
+		svc.Spec.Ports = ports
+		svc.Spec.Type = corev1.ServiceTypeClusterIP
+		svc.Spec.PublishNotReadyAddresses = canary
+
+		return nil
+	})
+
+	return svc.Name, err
+}
+
+func (c *Canary) ownerReferences() []metav1.OwnerReference {
+	return []metav1.OwnerReference{*metav1.NewControllerRef(c.Owner, tritonappsv1alpha1.GroupVersion.WithKind("DeployFlow"))}
+}
+
+// removeObject deletes the object created, it is fine if it does not exist.
+func (c *Canary) removeObject(obj runtime.Object) error {
+	if err := c.Delete(context.TODO(), obj); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
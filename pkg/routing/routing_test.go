@@ -0,0 +1,435 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routing
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	"github.com/triton-io/triton/pkg/setting"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const ns = "default"
+
+func newClient(objs ...runtime.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = tritonappsv1alpha1.AddToScheme(scheme)
+
+	stable := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "demo"},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeNodePort,
+			Selector: map[string]string{"app": "demo"},
+			Ports:    []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080), NodePort: 30080}},
+		},
+	}
+
+	return fake.NewFakeClientWithScheme(scheme, append(objs, stable)...)
+}
+
+func newDeploy(routing *tritonappsv1alpha1.CanaryRouting) *tritonappsv1alpha1.DeployFlow {
+	return &tritonappsv1alpha1.DeployFlow{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "demo-x1", UID: "uid"},
+		Spec: tritonappsv1alpha1.DeployFlowSpec{
+			UpdateStrategy: &tritonappsv1alpha1.DeployUpdateStrategy{Canary: 1, Routing: routing},
+		},
+	}
+}
+
+func checkCanaryService(t *testing.T, cl client.Client) {
+	svc := &corev1.Service{}
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: "demo-canary"}, svc); err != nil {
+		t.Fatalf("failed to get canary service: %v", err)
+	}
+	if expected := map[string]string{"app": "demo", setting.CanaryLabel: "true"}; !reflect.DeepEqual(svc.Spec.Selector, expected) {
+		t.Fatalf("expected selector %v, got %v", expected, svc.Spec.Selector)
+	}
+	if svc.Spec.Type != corev1.ServiceTypeClusterIP || svc.Spec.Ports[0].NodePort != 0 || !svc.Spec.PublishNotReadyAddresses {
+		t.Fatalf("unexpected canary service spec %+v", svc.Spec)
+	}
+	if len(svc.OwnerReferences) != 1 || svc.OwnerReferences[0].Name != "demo-x1" {
+		t.Fatalf("expected the canary service to be owned by the deploy, got %+v", svc.OwnerReferences)
+	}
+}
+
+func checkRemoved(t *testing.T, cl client.Client, obj runtime.Object, name string) {
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: name}, obj); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected %s to be removed, got %v", name, err)
+	}
+}
+
+func TestHTTPRoute(t *testing.T) {
+	stable := newHTTPRoute(ns, "demo")
+	stable.Object["spec"] = map[string]interface{}{
+		"parentRefs": []interface{}{map[string]interface{}{"name": "gateway"}},
+		"hostnames":  []interface{}{"demo.example.com"},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches":     []interface{}{map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/api"}}},
+				"backendRefs": []interface{}{map[string]interface{}{"name": "demo", "port": int64(80)}},
+			},
+			map[string]interface{}{
+				"backendRefs": []interface{}{map[string]interface{}{"name": "other", "port": int64(80)}},
+			},
+		},
+	}
+	cl := newClient(stable)
+
+	d := newDeploy(&tritonappsv1alpha1.CanaryRouting{
+		Type:    tritonappsv1alpha1.RoutingHTTPRoute,
+		Service: "demo",
+		Route:   "demo",
+		Headers: []tritonappsv1alpha1.HeaderMatch{{Name: "X-Canary", Value: "qa"}},
+		Cookie:  &tritonappsv1alpha1.CookieMatch{Name: "canary", Value: "always"},
+	})
+	c := New(cl, d, d.Spec.UpdateStrategy.Routing)
+	if err := c.Ensure(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	checkCanaryService(t, cl)
+
+	canary := newHTTPRoute(ns, "demo-canary")
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: "demo-canary"}, canary); err != nil {
+		t.Fatalf("failed to get canary httproute: %v", err)
+	}
+	expected := []interface{}{
+		map[string]interface{}{
+			"matches": []interface{}{map[string]interface{}{
+				"path": map[string]interface{}{"type": "PathPrefix", "value": "/api"},
+				"headers": []interface{}{
+					map[string]interface{}{"type": "Exact", "name": "X-Canary", "value": "qa"},
+					map[string]interface{}{"type": "RegularExpression", "name": "Cookie", "value": `(^|;\s*)canary=always(;|$)`},
+				},
+			}},
+			"backendRefs": []interface{}{map[string]interface{}{"name": "demo-canary", "port": int64(80)}},
+		},
+	}
+	rules, _, _ := unstructured.NestedSlice(canary.Object, "spec", "rules")
+	if !reflect.DeepEqual(rules, expected) {
+		t.Fatalf("expected rules %v, got %v", expected, rules)
+	}
+	if hostnames, _, _ := unstructured.NestedStringSlice(canary.Object, "spec", "hostnames"); !reflect.DeepEqual(hostnames, []string{"demo.example.com"}) {
+		t.Fatalf("expected hostnames to be copied, got %v", hostnames)
+	}
+
+	if err := c.Remove(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	checkRemoved(t, cl, newHTTPRoute(ns, "demo-canary"), "demo-canary")
+	checkRemoved(t, cl, &corev1.Service{}, "demo-canary")
+}
+
+func TestIngress(t *testing.T) {
+	stable := &networkingv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "demo", Annotations: map[string]string{ingressClassAnnotation: "nginx"}},
+		Spec: networkingv1beta1.IngressSpec{
+			Rules: []networkingv1beta1.IngressRule{{
+				Host: "demo.example.com",
+				IngressRuleValue: networkingv1beta1.IngressRuleValue{HTTP: &networkingv1beta1.HTTPIngressRuleValue{Paths: []networkingv1beta1.HTTPIngressPath{
+					{Path: "/api", Backend: networkingv1beta1.IngressBackend{ServiceName: "demo", ServicePort: intstr.FromInt(80)}},
+					{Path: "/other", Backend: networkingv1beta1.IngressBackend{ServiceName: "other", ServicePort: intstr.FromInt(80)}},
+				}}},
+			}},
+		},
+	}
+	cl := newClient(stable)
+
+	d := newDeploy(&tritonappsv1alpha1.CanaryRouting{
+		Type:    tritonappsv1alpha1.RoutingIngress,
+		Service: "demo",
+		Route:   "demo",
+		Headers: []tritonappsv1alpha1.HeaderMatch{{Name: "X-Canary", Value: "qa"}},
+	})
+	c := New(cl, d, d.Spec.UpdateStrategy.Routing)
+	if err := c.Ensure(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	checkCanaryService(t, cl)
+
+	canary := &networkingv1beta1.Ingress{}
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: "demo-canary"}, canary); err != nil {
+		t.Fatalf("failed to get canary ingress: %v", err)
+	}
+	expected := map[string]string{
+		ingressClassAnnotation:   "nginx",
+		nginxCanary:              "true",
+		nginxCanaryByHeader:      "X-Canary",
+		nginxCanaryByHeaderValue: "qa",
+	}
+	if !reflect.DeepEqual(canary.Annotations, expected) {
+		t.Fatalf("expected annotations %v, got %v", expected, canary.Annotations)
+	}
+	paths := canary.Spec.Rules[0].HTTP.Paths
+	if len(paths) != 1 || paths[0].Path != "/api" || paths[0].Backend.ServiceName != "demo-canary" {
+		t.Fatalf("unexpected paths %+v", paths)
+	}
+
+	if err := c.Remove(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	checkRemoved(t, cl, &networkingv1beta1.Ingress{}, "demo-canary")
+	checkRemoved(t, cl, &corev1.Service{}, "demo-canary")
+}
+
+func TestHTTPRouteWeight(t *testing.T) {
+	stable := newHTTPRoute(ns, "demo")
+	rules := []interface{}{
+		map[string]interface{}{
+			"backendRefs": []interface{}{
+				map[string]interface{}{"name": "demo", "port": int64(80)},
+				map[string]interface{}{"name": "other", "port": int64(80), "weight": int64(2)},
+			},
+		},
+	}
+	stable.Object["spec"] = map[string]interface{}{"rules": runtime.DeepCopyJSONValue(rules)}
+	cl := newClient(stable)
+
+	d := newDeploy(&tritonappsv1alpha1.CanaryRouting{Type: tritonappsv1alpha1.RoutingHTTPRoute, Service: "demo", Route: "demo"})
+	for _, weight := range []int32{5, 20} {
+		w := weight
+		if err := New(cl, d, d.Spec.UpdateStrategy.Routing).WithWeight(&w).Ensure(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	svc := &corev1.Service{}
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: "demo-stable"}, svc); err != nil {
+		t.Fatalf("failed to get stable service: %v", err)
+	}
+	if expected := map[string]string{"app": "demo", setting.CanaryLabel: "false"}; !reflect.DeepEqual(svc.Spec.Selector, expected) {
+		t.Fatalf("expected selector %v, got %v", expected, svc.Spec.Selector)
+	}
+	// no header is matched, so no canary route is created.
+	checkRemoved(t, cl, newHTTPRoute(ns, "demo-canary"), "demo-canary")
+
+	route := newHTTPRoute(ns, "demo")
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: "demo"}, route); err != nil {
+		t.Fatalf("failed to get httproute: %v", err)
+	}
+	expected := []interface{}{
+		map[string]interface{}{"name": "demo-stable", "port": int64(80), "weight": int64(80)},
+		map[string]interface{}{"name": "demo-canary", "port": int64(80), "weight": int64(20)},
+		map[string]interface{}{"name": "other", "port": int64(80), "weight": int64(200)},
+	}
+	refs, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	if got := refs[0].(map[string]interface{})["backendRefs"]; !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected backends %v, got %v", expected, got)
+	}
+
+	if err := New(cl, d, d.Spec.UpdateStrategy.Routing).Remove(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	route = newHTTPRoute(ns, "demo")
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: "demo"}, route); err != nil {
+		t.Fatalf("failed to get httproute: %v", err)
+	}
+	if got, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules"); !reflect.DeepEqual(got, rules) {
+		t.Fatalf("expected rules to be restored to %v, got %v", rules, got)
+	}
+	if _, ok := route.GetAnnotations()[stableRulesAnnotation]; ok {
+		t.Fatalf("expected annotation %s to be removed", stableRulesAnnotation)
+	}
+	checkRemoved(t, cl, &corev1.Service{}, "demo-stable")
+}
+
+func TestIngressWeight(t *testing.T) {
+	rules := []networkingv1beta1.IngressRule{{
+		Host: "demo.example.com",
+		IngressRuleValue: networkingv1beta1.IngressRuleValue{HTTP: &networkingv1beta1.HTTPIngressRuleValue{Paths: []networkingv1beta1.HTTPIngressPath{
+			{Path: "/", Backend: networkingv1beta1.IngressBackend{ServiceName: "demo", ServicePort: intstr.FromInt(80)}},
+		}}},
+	}}
+	stable := &networkingv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "demo"},
+		Spec:       networkingv1beta1.IngressSpec{Rules: rules},
+	}
+	cl := newClient(stable.DeepCopy())
+
+	d := newDeploy(&tritonappsv1alpha1.CanaryRouting{Type: tritonappsv1alpha1.RoutingIngress, Service: "demo", Route: "demo"})
+	for _, weight := range []int32{5, 20} {
+		w := weight
+		if err := New(cl, d, d.Spec.UpdateStrategy.Routing).WithWeight(&w).Ensure(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	canary := &networkingv1beta1.Ingress{}
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: "demo-canary"}, canary); err != nil {
+		t.Fatalf("failed to get canary ingress: %v", err)
+	}
+	if expected := map[string]string{nginxCanary: "true", nginxCanaryWeight: "20"}; !reflect.DeepEqual(canary.Annotations, expected) {
+		t.Fatalf("expected annotations %v, got %v", expected, canary.Annotations)
+	}
+	if backend := canary.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName; backend != "demo-canary" {
+		t.Fatalf("expected canary backend demo-canary, got %s", backend)
+	}
+
+	ing := &networkingv1beta1.Ingress{}
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: "demo"}, ing); err != nil {
+		t.Fatalf("failed to get ingress: %v", err)
+	}
+	if backend := ing.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName; backend != "demo-stable" {
+		t.Fatalf("expected stable backend demo-stable, got %s", backend)
+	}
+
+	if err := New(cl, d, d.Spec.UpdateStrategy.Routing).Remove(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	ing = &networkingv1beta1.Ingress{}
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: "demo"}, ing); err != nil {
+		t.Fatalf("failed to get ingress: %v", err)
+	}
+	if !reflect.DeepEqual(ing.Spec.Rules, rules) || len(ing.Annotations) != 0 {
+		t.Fatalf("expected ingress to be restored, got %+v", ing)
+	}
+	checkRemoved(t, cl, &networkingv1beta1.Ingress{}, "demo-canary")
+	checkRemoved(t, cl, &corev1.Service{}, "demo-stable")
+}
+
+func TestVirtualServiceMirror(t *testing.T) {
+	vs := &unstructured.Unstructured{}
+	vs.SetGroupVersionKind(virtualServiceGVK)
+	vs.SetNamespace(ns)
+	vs.SetName("demo")
+	routes := []interface{}{
+		map[string]interface{}{
+			"route": []interface{}{map[string]interface{}{
+				"destination": map[string]interface{}{"host": "demo.default.svc.cluster.local", "port": map[string]interface{}{"number": int64(80)}},
+			}},
+		},
+		map[string]interface{}{
+			"route": []interface{}{map[string]interface{}{"destination": map[string]interface{}{"host": "other"}}},
+		},
+	}
+	vs.Object["spec"] = map[string]interface{}{"http": runtime.DeepCopyJSONValue(routes)}
+	cl := newClient(vs)
+
+	percent := int32(50)
+	d := newDeploy(nil)
+	m := NewMirror(cl, d, &tritonappsv1alpha1.Shadowing{
+		Type:    tritonappsv1alpha1.MirrorVirtualService,
+		Service: "demo",
+		Route:   "demo",
+		Percent: &percent,
+	})
+	if err := m.Ensure(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	checkCanaryService(t, cl)
+
+	got := &unstructured.Unstructured{}
+	got.SetGroupVersionKind(virtualServiceGVK)
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: "demo"}, got); err != nil {
+		t.Fatalf("failed to get virtualservice: %v", err)
+	}
+	http, _, _ := unstructured.NestedSlice(got.Object, "spec", "http")
+	expected := map[string]interface{}{"host": "demo-canary", "port": map[string]interface{}{"number": int64(80)}}
+	if mirror := http[0].(map[string]interface{})["mirror"]; !reflect.DeepEqual(mirror, expected) {
+		t.Fatalf("expected mirror %v, got %v", expected, mirror)
+	}
+	// the float value is decoded as an integer when it has no fraction.
+	if value, _, _ := unstructured.NestedFieldNoCopy(http[0].(map[string]interface{}), "mirrorPercentage", "value"); fmt.Sprint(value) != "50" {
+		t.Fatalf("expected mirror percentage 50, got %v", value)
+	}
+	if _, ok := http[1].(map[string]interface{})["mirror"]; ok {
+		t.Fatalf("expected routes to other services not to be mirrored, got %v", http[1])
+	}
+
+	if err := m.Remove(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: "demo"}, got); err != nil {
+		t.Fatalf("failed to get virtualservice: %v", err)
+	}
+	if http, _, _ := unstructured.NestedSlice(got.Object, "spec", "http"); !reflect.DeepEqual(http, routes) {
+		t.Fatalf("expected routes to be restored to %v, got %v", routes, http)
+	}
+	checkRemoved(t, cl, &corev1.Service{}, "demo-canary")
+}
+
+func TestHTTPRouteMirror(t *testing.T) {
+	stable := newHTTPRoute(ns, "demo")
+	rules := []interface{}{
+		map[string]interface{}{
+			"filters":     []interface{}{map[string]interface{}{"type": "RequestHeaderModifier"}},
+			"backendRefs": []interface{}{map[string]interface{}{"name": "demo", "port": int64(80)}},
+		},
+		map[string]interface{}{
+			"backendRefs": []interface{}{map[string]interface{}{"name": "other", "port": int64(80)}},
+		},
+	}
+	stable.Object["spec"] = map[string]interface{}{"rules": runtime.DeepCopyJSONValue(rules)}
+	cl := newClient(stable)
+
+	d := newDeploy(nil)
+	m := NewMirror(cl, d, &tritonappsv1alpha1.Shadowing{Type: tritonappsv1alpha1.MirrorHTTPRoute, Service: "demo", Route: "demo"})
+	// ensuring twice adds only one filter.
+	for i := 0; i < 2; i++ {
+		if err := m.Ensure(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	route := newHTTPRoute(ns, "demo")
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: "demo"}, route); err != nil {
+		t.Fatalf("failed to get httproute: %v", err)
+	}
+	got, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	expected := []interface{}{
+		map[string]interface{}{"type": "RequestHeaderModifier"},
+		map[string]interface{}{
+			"type":          "RequestMirror",
+			"requestMirror": map[string]interface{}{"backendRef": map[string]interface{}{"name": "demo-canary", "port": int64(80)}},
+		},
+	}
+	if filters := got[0].(map[string]interface{})["filters"]; !reflect.DeepEqual(filters, expected) {
+		t.Fatalf("expected filters %v, got %v", expected, filters)
+	}
+	if _, ok := got[1].(map[string]interface{})["filters"]; ok {
+		t.Fatalf("expected rules to other services not to be mirrored, got %v", got[1])
+	}
+
+	if err := m.Stop(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	route = newHTTPRoute(ns, "demo")
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: ns, Name: "demo"}, route); err != nil {
+		t.Fatalf("failed to get httproute: %v", err)
+	}
+	if got, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules"); !reflect.DeepEqual(got, rules) {
+		t.Fatalf("expected rules to be restored to %v, got %v", rules, got)
+	}
+
+	if err := NewMirror(cl, d, &tritonappsv1alpha1.Shadowing{Type: tritonappsv1alpha1.MirrorHTTPRoute, Service: "missing", Route: "demo"}).Ensure(); err == nil {
+		t.Fatal("expected an error when no rule is sent to the service")
+	}
+}
@@ -3,8 +3,10 @@ package application
 import (
 	"context"
 
+	"github.com/golang/protobuf/ptypes"
 	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
 	"github.com/sirupsen/logrus"
+	"github.com/triton-io/triton/pkg/diff"
 	"github.com/triton-io/triton/pkg/kube/fetcher"
 	"github.com/triton-io/triton/pkg/log"
 	"github.com/triton-io/triton/pkg/setting"
@@ -75,16 +77,6 @@ func (s *Service) Restart(_ context.Context, in *pb.RestartRequest) (*pb.Restart
 	mgr := kubeclient.NewManager()
 	cl := mgr.GetClient()
 
-	cs, found, err := fetcher.GetCloneSetInCache(in.Instance.Namespace, in.Instance.Name, cl)
-	if err != nil {
-		logger.WithError(err).Error("failed to fetch cloneSet")
-		return nil, err
-	} else if !found {
-		return nil, terrors.NewNotFound("cloneSet not found")
-	}
-
-	ics := internalcloneset.FromCloneSet(cs)
-
 	size := intstr.Parse(in.Strategy.BatchSize)
 	strategy := &tritonappsv1alpha1.DeployNonUpdateStrategy{
 		BaseStrategy: tritonappsv1alpha1.BaseStrategy{
@@ -96,13 +88,9 @@ func (s *Service) Restart(_ context.Context, in *pb.RestartRequest) (*pb.Restart
 		PodsToDelete: in.Strategy.PodsToDelete,
 	}
 
+	// the template and the application type are taken from the workload.
 	applicationSpec := tritonappsv1alpha1.ApplicationSpec{
-		AppID:        ics.GetAppID(),
-		GroupID:      ics.GetGroupID(),
-		Replicas:     ics.Spec.Replicas,
-		AppName:      ics.GetAppName(),
-		Template:     ics.Spec.Template,
-		CloneSetName: ics.Name,
+		CloneSetName: in.Instance.Name,
 	}
 
 	req := &deployflow.DeployNonUpdateRequest{
@@ -110,7 +98,7 @@ func (s *Service) Restart(_ context.Context, in *pb.RestartRequest) (*pb.Restart
 		ApplicationSpec:   &applicationSpec,
 		NonUpdateStrategy: strategy,
 	}
-	updated, err := deployflow.CreateNonUpdateDeploy(req, ics.Namespace, cl, logger)
+	updated, err := deployflow.CreateNonUpdateDeploy(req, in.Instance.Namespace, cl, logger)
 	if err != nil {
 		if terrors.IsNotFound(err) {
 			return nil, status.Error(codes.NotFound, "application not found")
@@ -131,16 +119,6 @@ func (s *Service) Scale(_ context.Context, in *pb.ScaleRequest) (*pb.ScaleReply,
 	})
 	mgr := kubeclient.NewManager()
 	cl := mgr.GetClient()
-	cs, found, err := fetcher.GetCloneSetInCache(in.Instance.Namespace, in.Instance.Name, cl)
-	if err != nil {
-		logger.WithError(err).Error("failed to fetch cloneSet")
-		return nil, err
-	} else if !found {
-		return nil, terrors.NewNotFound("cloneSet not found")
-	}
-
-	ics := internalcloneset.FromCloneSet(cs)
-
 	size := intstr.Parse(in.Strategy.BatchSize)
 	strategy := &tritonappsv1alpha1.DeployNonUpdateStrategy{
 		BaseStrategy: tritonappsv1alpha1.BaseStrategy{
@@ -152,12 +130,8 @@ func (s *Service) Scale(_ context.Context, in *pb.ScaleRequest) (*pb.ScaleReply,
 		PodsToDelete: in.Strategy.PodsToDelete,
 	}
 	applicationSpec := tritonappsv1alpha1.ApplicationSpec{
-		AppID:        ics.GetAppID(),
-		GroupID:      ics.GetGroupID(),
 		Replicas:     &in.Replicas,
-		AppName:      ics.GetAppName(),
-		Template:     ics.Spec.Template,
-		CloneSetName: ics.Name,
+		CloneSetName: in.Instance.Name,
 	}
 
 	req := &deployflow.DeployNonUpdateRequest{
@@ -205,7 +179,7 @@ func (s *Service) Rollback(_ context.Context, in *pb.RollbackRequest) (*pb.Rollb
 
 	logger.Infof("Start to rollback application %s", in.Instance.Name)
 
-	updated, oldName, err := deployflow.RollbackDeploy(in.Instance.Namespace, in.Instance.Name, in.DeployName, cl, strategy, logger)
+	updated, oldName, err := deployflow.RollbackDeploy(in.Instance.Namespace, in.Instance.Name, in.DeployName, in.Revision, cl, strategy, logger)
 	if err != nil {
 		if terrors.IsNotFound(err) {
 			return nil, status.Error(codes.NotFound, "application not found")
@@ -218,6 +192,78 @@ func (s *Service) Rollback(_ context.Context, in *pb.RollbackRequest) (*pb.Rollb
 	return &pb.RollbackReply{DeployName: updated.Name, RollbackTo: oldName}, nil
 }
 
+func (s *Service) History(_ context.Context, in *pb.InstanceMetaRequest) (*pb.HistoryReply, error) {
+	history, err := deployflow.ListHistory(in.Instance.Namespace, in.Instance.Name, kubeclient.NewManager().GetClient())
+	if err != nil {
+		if terrors.IsNotFound(err) {
+			return nil, status.Error(codes.NotFound, "application not found")
+		} else if terrors.IsConflict(err) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	entries := make([]*pb.HistoryEntry, 0, len(history))
+	for _, e := range history {
+		created, _ := ptypes.TimestampProto(e.CreatedAt.Time)
+		start, _ := ptypes.TimestampProto(e.StartedAt.Time)
+		end, _ := ptypes.TimestampProto(e.FinishedAt.Time)
+		entries = append(entries, &pb.HistoryEntry{
+			DeployName:     e.DeployName,
+			Action:         e.Action,
+			Revision:       e.Revision,
+			UpdateRevision: e.UpdateRevision,
+			Current:        e.Current,
+			Images:         e.Images,
+			Phase:          string(e.Phase),
+			RollbackOf:     e.RollbackOf,
+			RollbackBy:     e.RollbackBy,
+			CreatedAt:      created,
+			StartedAt:      start,
+			FinishedAt:     end,
+		})
+	}
+
+	return &pb.HistoryReply{Entries: entries}, nil
+}
+
+func (s *Service) Diff(_ context.Context, in *pb.DiffRequest) (*pb.DiffReply, error) {
+	d, err := deployflow.DiffRevisions(in.Instance.Namespace, in.Instance.Name, in.From, in.To, kubeclient.NewManager().GetClient())
+	if err != nil {
+		if terrors.IsNotFound(err) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		} else if terrors.IsConflict(err) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.DiffReply{
+		Labels:         setChangesReply(d.Labels),
+		Annotations:    setChangesReply(d.Annotations),
+		InitContainers: setContainerDiffsReply(d.InitContainers),
+		Containers:     setContainerDiffsReply(d.Containers),
+	}, nil
+}
+
+func setChangesReply(changes []diff.Change) []*pb.Change {
+	res := make([]*pb.Change, 0, len(changes))
+	for _, c := range changes {
+		res = append(res, &pb.Change{Path: c.Path, Kind: string(c.Kind), From: c.From, To: c.To})
+	}
+
+	return res
+}
+
+func setContainerDiffsReply(containers []diff.ContainerDiff) []*pb.ContainerDiff {
+	res := make([]*pb.ContainerDiff, 0, len(containers))
+	for _, c := range containers {
+		res = append(res, &pb.ContainerDiff{Name: c.Name, Kind: string(c.Kind), Changes: setChangesReply(c.Changes)})
+	}
+
+	return res
+}
+
 func setInstanceReply(cs *kruiseappsv1alpha1.CloneSet) *pb.Instance {
 	ics := internalcloneset.FromCloneSet(cs)
 
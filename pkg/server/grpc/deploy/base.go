@@ -6,8 +6,11 @@ import (
 	"github.com/golang/protobuf/ptypes"
 	"github.com/triton-io/triton/pkg/kube/fetcher"
 	"github.com/triton-io/triton/pkg/log"
+	"github.com/triton-io/triton/pkg/services/deployflow"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
 	kubeclient "github.com/triton-io/triton/pkg/kube/client"
@@ -62,9 +65,21 @@ func setDeploy(d *tritonappsv1alpha1.DeployFlow) *pb.Deploy {
 			Pods:           pods,
 			StartedAt:      start,
 			FinishedAt:     end,
+
+			BakeRemainingSeconds: int32(idl.BakeRemaining(&c).Seconds()),
 		})
 	}
 
+	var revertReplicas, revertedReplicas int32
+	if rv := d.Status.Revert; rv != nil {
+		revertReplicas, revertedReplicas = int32(rv.Replicas), int32(rv.RevertedReplicas)
+	}
+
+	var blueGreenPhase, activeRevision string
+	if bg := d.Status.BlueGreen; bg != nil {
+		blueGreenPhase, activeRevision = string(bg.Phase), bg.ActiveRevision
+	}
+
 	return &pb.Deploy{
 		Name:                 d.Name,
 		AppID:                int32(d.Spec.Application.AppID),
@@ -78,6 +93,15 @@ func setDeploy(d *tritonappsv1alpha1.DeployFlow) *pb.Deploy {
 		BatchIntervalSeconds: idl.BatchIntervalSeconds(),
 		Canary:               int32(idl.Canary()),
 
+		TimeoutSeconds:        int32(idl.Timeout().Seconds()),
+		GracefulPeriodSeconds: idl.GracefulPeriodSeconds(),
+
+		RevertReplicas:   revertReplicas,
+		RevertedReplicas: revertedReplicas,
+
+		BlueGreenPhase: blueGreenPhase,
+		ActiveRevision: activeRevision,
+
 		AvailableReplicas:    d.Status.AvailableReplicas,
 		UpdatedReplicas:      d.Status.UpdatedReplicas,
 		UpdatedReadyReplicas: d.Status.UpdatedReadyReplicas,
@@ -156,3 +180,67 @@ func matchFilter(f fetcher.DeployFilter, d *tritonappsv1alpha1.DeployFlow) bool
 
 	return true
 }
+
+func setPlanReply(p *deployflow.DeployPlan) *pb.PlanReply {
+	batches := make([]*pb.BatchPlan, 0, len(p.Batches))
+	for _, b := range p.Batches {
+		patches := make([]*pb.PhasePatch, 0, len(b.Patches))
+		for _, patch := range b.Patches {
+			patches = append(patches, &pb.PhasePatch{Phase: string(patch.Phase), Patch: patch.Patch})
+		}
+		bp := &pb.BatchPlan{
+			Batch:     int32(b.Batch),
+			BatchSize: int32(b.BatchSize),
+			Step:      int32(b.Step),
+			Canary:    b.Canary,
+			Patches:   patches,
+		}
+		if b.Weight != nil {
+			bp.Weight = wrapperspb.Int32(*b.Weight)
+		}
+		batches = append(batches, bp)
+	}
+
+	return &pb.PlanReply{
+		Action:   p.Action,
+		Replicas: p.Replicas,
+		Conflict: p.Conflict,
+		Batches:  batches,
+	}
+}
+
+func toUpdateStrategy(s *pb.UpdateStrategy) *tritonappsv1alpha1.DeployUpdateStrategy {
+	if s == nil {
+		return nil
+	}
+
+	size := intstr.Parse(s.BatchSize)
+	return &tritonappsv1alpha1.DeployUpdateStrategy{
+		BaseStrategy: tritonappsv1alpha1.BaseStrategy{
+			BatchSize:            &size,
+			Batches:              int(s.Batches),
+			BatchIntervalSeconds: s.BatchIntervalSeconds,
+			Mode:                 tritonappsv1alpha1.DeployMode(s.Mode),
+		},
+		NoPullIn: s.NoPullIn,
+		Canary:   int(s.Canary),
+		Stage:    tritonappsv1alpha1.BatchPhase(s.Stage),
+	}
+}
+
+func toNonUpdateStrategy(s *pb.NonUpdateStrategy) *tritonappsv1alpha1.DeployNonUpdateStrategy {
+	if s == nil {
+		return nil
+	}
+
+	size := intstr.Parse(s.BatchSize)
+	return &tritonappsv1alpha1.DeployNonUpdateStrategy{
+		BaseStrategy: tritonappsv1alpha1.BaseStrategy{
+			BatchSize:            &size,
+			Batches:              int(s.Batches),
+			BatchIntervalSeconds: s.BatchIntervalSeconds,
+			Mode:                 tritonappsv1alpha1.DeployMode(s.Mode),
+		},
+		PodsToDelete: s.PodsToDelete,
+	}
+}
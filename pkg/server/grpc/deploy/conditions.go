@@ -28,3 +28,21 @@ func getResumeConditions() ConditionFuncs {
 		},
 	}
 }
+
+func getPromoteConditions() ConditionFuncs {
+	return ConditionFuncs{
+		func(d *tritonappsv1alpha1.DeployFlow) (bool, error) {
+			bg := d.Status.BlueGreen
+			return bg != nil && bg.ActiveRevision == bg.UpdateRevision, nil
+		},
+	}
+}
+
+func getSwitchBackConditions() ConditionFuncs {
+	return ConditionFuncs{
+		func(d *tritonappsv1alpha1.DeployFlow) (bool, error) {
+			bg := d.Status.BlueGreen
+			return bg != nil && bg.Phase == tritonappsv1alpha1.BlueGreenSwitchedBack, nil
+		},
+	}
+}
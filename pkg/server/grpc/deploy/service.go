@@ -18,7 +18,9 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
 	terrors "github.com/triton-io/triton/pkg/errors"
@@ -50,6 +52,23 @@ func (s *Service) Cancel(in *pb.DeployMetaRequest, stream pb.DeployFlow_CancelSe
 	return patchAndWait(in.Deploy.Namespace, in.Deploy.Name, strategyBytes, stream, getCancelConditions()...)
 }
 
+func (s *Service) Abort(in *pb.DeployMetaRequest, stream pb.DeployFlow_AbortServer) error {
+	d, err := getDeploy(in.Deploy.Namespace, in.Deploy.Name)
+	if err != nil {
+		return err
+	}
+	if d.Spec.Action != setting.Update && d.Spec.Action != setting.Rollback {
+		return status.Error(codes.FailedPrecondition, "only update and rollback deploys can be aborted, cancel it instead")
+	}
+	// the updated pods of an in-place or stepped workload can not be reverted, an abort would only cancel the deploy.
+	if idl := internaldeploy.FromDeploy(d); !idl.Revertable() {
+		return status.Error(codes.FailedPrecondition, fmt.Sprintf("updated pods of %s can not be reverted, cancel it instead", idl.ApplicationType()))
+	}
+
+	strategyBytes := []byte(fmt.Sprintf(`{"canceled":%t,"revertOnCancel":%t}`, true, true))
+	return patchAndWait(in.Deploy.Namespace, in.Deploy.Name, strategyBytes, stream, getCancelConditions()...)
+}
+
 func (s *Service) Pause(in *pb.DeployMetaRequest, stream pb.DeployFlow_PauseServer) error {
 	strategyBytes := []byte(fmt.Sprintf(`{"paused":%t}`, true))
 	return patchAndWait(in.Deploy.Namespace, in.Deploy.Name, strategyBytes, stream, getPauseConditions()...)
@@ -65,7 +84,18 @@ func (s *Service) Continue(_ context.Context, in *pb.ContinueRequest) (*pb.Deplo
 		return nil, status.Error(codes.InvalidArgument, "empty target")
 	}
 
-	strategyBytes, err := json.Marshal(in.Target)
+	// batchSize is an int or a percentage, the remaining batches are planned again by the controller if it is changed.
+	target := struct {
+		Stage     string              `json:"stage,omitempty"`
+		Batches   int32               `json:"batches,omitempty"`
+		BatchSize *intstr.IntOrString `json:"batchSize,omitempty"`
+	}{Stage: in.Target.Stage, Batches: in.Target.Batches}
+	if in.Target.BatchSize != "" {
+		size := intstr.Parse(in.Target.BatchSize)
+		target.BatchSize = &size
+	}
+
+	strategyBytes, err := json.Marshal(target)
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "bad target")
 	}
@@ -115,6 +145,107 @@ func (s *Service) Delete(_ context.Context, in *pb.DeployMetaRequest) (*pb.Empty
 	return &pb.EmptyReply{}, nil
 }
 
+func (s *Service) RetryBatch(_ context.Context, in *pb.RetryBatchRequest) (*pb.DeployReply, error) {
+	logger := log.WithFields(logrus.Fields{
+		"context":   "deploy",
+		"method":    "RetryBatch",
+		"namespace": in.Deploy.Namespace,
+		"name":      in.Deploy.Name,
+	})
+	mgr := kubeclient.NewManager()
+	cl := mgr.GetClient()
+	cr := mgr.GetAPIReader()
+
+	d, err := deployflow.RetryBatch(in.Deploy.Namespace, in.Deploy.Name, in.SkipFailedPods, cr, cl, logger)
+	if err != nil {
+		if terrors.IsNotFound(err) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		} else if terrors.IsConflict(err) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return setDeployReply(d), nil
+}
+
+func (s *Service) Promote(in *pb.DeployMetaRequest, stream pb.DeployFlow_PromoteServer) error {
+	return switchTrafficAndWait(in.Deploy, "Promote", deployflow.Promote, stream, getPromoteConditions()...)
+}
+
+func (s *Service) SwitchBack(in *pb.DeployMetaRequest, stream pb.DeployFlow_SwitchBackServer) error {
+	return switchTrafficAndWait(in.Deploy, "SwitchBack", deployflow.SwitchBack, stream, getSwitchBackConditions()...)
+}
+
+func switchTrafficAndWait(meta *pb.DeployMeta, method string,
+	action func(ns, name string, reader client.Reader, cl client.Client, logger *logrus.Entry) (*tritonappsv1alpha1.DeployFlow, error),
+	sender StreamSender, conditions ...ConditionFunc) error {
+	logger := log.WithFields(logrus.Fields{
+		"context":   "deploy",
+		"method":    method,
+		"namespace": meta.Namespace,
+		"name":      meta.Name,
+	})
+	mgr := kubeclient.NewManager()
+	cl := mgr.GetClient()
+	cr := mgr.GetAPIReader()
+
+	if _, err := action(meta.Namespace, meta.Name, cr, cl, logger); err != nil {
+		if terrors.IsNotFound(err) {
+			return status.Error(codes.NotFound, err.Error())
+		} else if terrors.IsConflict(err) {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return waitConditions(meta.Namespace, meta.Name, sender, conditions...)
+}
+
+func (s *Service) Plan(_ context.Context, in *pb.PlanRequest) (*pb.PlanReply, error) {
+	cl := kubeclient.NewManager().GetClient()
+
+	var p *deployflow.DeployPlan
+	var err error
+	switch in.Action {
+	case setting.Update:
+		spec := &tritonappsv1alpha1.ApplicationSpec{}
+		if err := json.Unmarshal(in.ApplicationSpec, spec); err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid applicationSpec: %v", err))
+		}
+		if spec.CloneSetName == "" {
+			spec.CloneSetName = in.CloneSetName
+		}
+		p, err = deployflow.PlanUpdateDeploy(in.Namespace, &deployflow.DeployUpdateRequest{
+			ApplicationSpec: spec,
+			UpdateStrategy:  toUpdateStrategy(in.UpdateStrategy),
+		}, cl)
+	case setting.Restart, setting.Scale:
+		// the template and the application type are taken from the workload.
+		applicationSpec := &tritonappsv1alpha1.ApplicationSpec{
+			CloneSetName: in.CloneSetName,
+		}
+		if in.Action == setting.Scale {
+			applicationSpec.Replicas = &in.Replicas
+		}
+		p, err = deployflow.PlanNonUpdateDeploy(&deployflow.DeployNonUpdateRequest{
+			Action:            in.Action,
+			ApplicationSpec:   applicationSpec,
+			NonUpdateStrategy: toNonUpdateStrategy(in.NonUpdateStrategy),
+		}, in.Namespace, cl)
+	default:
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("action %s can not be planned", in.Action))
+	}
+	if err != nil {
+		if terrors.IsNotFound(err) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return setPlanReply(p), nil
+}
+
 func (s *Service) Watch(in *pb.WatchRequest, stream pb.DeployFlow_WatchServer) error {
 	logger := log.WithFields(logrus.Fields{
 		"context":   "deploy",
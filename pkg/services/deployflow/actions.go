@@ -12,11 +12,14 @@ import (
 	"github.com/triton-io/triton/pkg/kube/fetcher"
 	internalcloneset "github.com/triton-io/triton/pkg/kube/types/cloneset"
 	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	internalpod "github.com/triton-io/triton/pkg/kube/types/pod"
+	"github.com/triton-io/triton/pkg/kube/types/workload"
 	"github.com/triton-io/triton/pkg/log"
 	"github.com/triton-io/triton/pkg/setting"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -59,101 +62,126 @@ func PatchDeployStatus(ns, name string, reader client.Reader, cl client.Client,
 }
 
 func CreateNonUpdateDeploy(r *DeployNonUpdateRequest, ns string, cl client.Client, logger *logrus.Entry) (*tritonappsv1alpha1.DeployFlow, error) {
-	action := r.Action
-	logger.Infof("Start to %s application", action)
+	logger.Infof("Start to %s application", r.Action)
 
-	cs, found, err := fetcher.GetCloneSetInCache(ns, r.ApplicationSpec.CloneSetName, cl)
+	deploy, cs, err := generateNonUpdateDeploy(r, ns, cl)
 	if err != nil {
-		logger.WithError(err).Error("failed to fetch cloneSet")
+		logger.WithError(err).Error("failed to generate deploy")
 		return nil, err
-	} else if !found {
-		return nil, terrors.NewNotFound("cloneSet not found")
 	}
+	action := deploy.Spec.Action
 
 	if err := preSteps(cs, action, cl); err != nil {
 		logger.WithError(err).Error("pre steps failed")
 		return nil, terrors.NewConflict("pre steps failed", err)
 	}
 
+	updated, err := create(deploy, cl)
+	if err != nil {
+		logger.WithError(err).Error("failed to create deploy")
+		return nil, err
+	}
+	logger.Infof("Finished to %s application", action)
+
+	return updated, nil
+}
+
+// generateNonUpdateDeploy generates the deploy of a non-update request without creating it, scale is resolved to scale in or scale out.
+// The application type, the template and the app ID, group ID and app name in its labels are taken from the workload.
+func generateNonUpdateDeploy(r *DeployNonUpdateRequest, ns string, cl client.Client) (*tritonappsv1alpha1.DeployFlow, workload.Object, error) {
+	action := r.Action
+
+	cs, err := getWorkload(ns, r.ApplicationSpec.CloneSetName, r.ApplicationSpec.ApplicationType, cl)
+	if err != nil {
+		return nil, nil, err
+	}
+	// a DaemonSet has no desired replicas, it runs a pod on each node it is scheduled to.
+	current := workload.StatusOf(cs).Replicas
+	if sr := workload.SpecReplicas(cs); sr != nil {
+		current = *sr
+	}
+
 	// it is a scale action if replicas > 0
 	var replicas int32
 	if r.ApplicationSpec != nil && r.ApplicationSpec.Replicas != nil {
 		replicas = *r.ApplicationSpec.Replicas
 	}
 	if replicas >= 0 && action == setting.Scale {
-		if replicas >= *cs.Spec.Replicas {
+		if replicas >= current {
 			action = setting.ScaleOut
 		} else {
 			action = setting.ScaleIn
 		}
 	} else {
-		replicas = *cs.Spec.Replicas
+		replicas = current
+	}
+
+	applicationSpec := r.ApplicationSpec.DeepCopy()
+	applicationSpec.ApplicationType = workload.ApplicationTypeOf(cs)
+	applicationSpec.Template = *workload.TemplateOf(cs)
+	applicationSpec.Replicas = &replicas
+	// pods are selected by the labels of the workload, keep the application in line with them.
+	if appID, groupID, appName := workload.ApplicationOf(cs); appID != 0 {
+		applicationSpec.AppID, applicationSpec.GroupID, applicationSpec.AppName = appID, groupID, appName
 	}
-	ics := internalcloneset.FromCloneSet(cs)
 
-	applicationSpec := tritonappsv1alpha1.ApplicationSpec{
-		AppID:        ics.GetAppID(),
-		GroupID:      ics.GetGroupID(),
-		Replicas:     &replicas,
-		AppName:      ics.GetAppName(),
-		Template:     ics.Spec.Template,
-		CloneSetName: ics.Name,
-	}
-
-	//g := generator{
-	//	applicationSpec:   &applicationSpec,
-	//	namespace:         r.Namespace,
-	//	action:            action,
-	//	nonUpdateStrategy: r.NonUpdateStrategy,
-	//}
-	//deploy := g.generate()
 	g := generator{
 		appID:             applicationSpec.AppID,
 		groupID:           applicationSpec.GroupID,
 		replicas:          replicas,
 		namespace:         ns,
-		appName:           r.ApplicationSpec.AppName,
-		clonesetName:      r.ApplicationSpec.CloneSetName,
+		appName:           applicationSpec.AppName,
+		clonesetName:      applicationSpec.CloneSetName,
 		action:            action,
-		applicationSpec:   r.ApplicationSpec,
+		applicationSpec:   applicationSpec,
 		nonUpdateStrategy: r.NonUpdateStrategy,
 	}
-	deploy := g.generate()
 
+	return g.generate(), cs, nil
+}
+
+func CreateUpdateDeploy(ns string, r *DeployUpdateRequest, cl client.Client, logger *logrus.Entry) (*tritonappsv1alpha1.DeployFlow, error) {
+	logger.Info("Start to create new deploy")
+	// 生成 DeployFlow 自定义资源
+	deploy, cs, err := generateUpdateDeploy(ns, r, cl)
+	if err != nil {
+		logger.WithError(err).Error("failed to get application")
+		return nil, err
+	}
+	// 执行预检查步骤
+	if err := preSteps(cs, deploy.Spec.Action, cl); err != nil {
+		logger.WithError(err).Error("pre steps failed")
+		return nil, terrors.NewConflict("pre steps failed", err)
+	}
+	// 实际创建 CRD 资源
 	updated, err := create(deploy, cl)
 	if err != nil {
 		logger.WithError(err).Error("failed to create deploy")
 		return nil, err
 	}
-	logger.Infof("Finished to %s application", action)
+	logger.Info("Finished to create deploy")
 
 	return updated, nil
 }
 
-func CreateUpdateDeploy(ns string, r *DeployUpdateRequest, cl client.Client, logger *logrus.Entry) (*tritonappsv1alpha1.DeployFlow, error) {
-	logger.Info("Start to create new deploy")
-	// 检查 CloneSet 是否存在
-	cs, found, err := fetcher.GetCloneSetInCache(ns, r.ApplicationSpec.CloneSetName, cl)
+// generateUpdateDeploy generates the deploy of an update request without creating it, the workload is nil if it is a create.
+func generateUpdateDeploy(ns string, r *DeployUpdateRequest, cl client.Client) (*tritonappsv1alpha1.DeployFlow, workload.Object, error) {
+	// 检查 CloneSet 或 Advanced StatefulSet 是否存在
+	cs, found, err := fetcher.GetWorkloadInCache(ns, r.ApplicationSpec.CloneSetName, r.ApplicationSpec.ApplicationType, cl)
 	if err != nil {
-		logger.WithError(err).Error("failed to get application")
-		return nil, err
+		return nil, nil, err
 	}
 	// 创建或者更新
 	action := setting.Create
 	if found && cs != nil {
 		action = setting.Update
 	}
-	// 执行预检查步骤
-	if err := preSteps(cs, action, cl); err != nil {
-		logger.WithError(err).Error("pre steps failed")
-		return nil, terrors.NewConflict("pre steps failed", err)
-	}
 	// 副本数
 	var replicas int32 = 1
 	if r.ApplicationSpec != nil && r.ApplicationSpec.Replicas != nil {
 		replicas = *r.ApplicationSpec.Replicas
-	} else if cs != nil && cs.Spec.Replicas != nil {
-		replicas = *cs.Spec.Replicas
+	} else if cs != nil && workload.SpecReplicas(cs) != nil {
+		replicas = *workload.SpecReplicas(cs)
 	}
 
 	g := generator{
@@ -167,57 +195,120 @@ func CreateUpdateDeploy(ns string, r *DeployUpdateRequest, cl client.Client, log
 		applicationSpec: r.ApplicationSpec,
 		updateStrategy:  r.UpdateStrategy,
 	}
-	// 生成 DeployFlow 自定义资源
-	deploy := g.generate()
-	// 实际创建 CRD 资源
-	updated, err := create(deploy, cl)
-	if err != nil {
-		logger.WithError(err).Error("failed to create deploy")
-		return nil, err
-	}
-	logger.Info("Finished to create deploy")
 
-	return updated, nil
+	return g.generate(), cs, nil
 }
 
-func RollbackDeploy(ns, clonesetName, deployName string, cl client.Client, strategy *tritonappsv1alpha1.DeployUpdateStrategy, logger *logrus.Entry) (*tritonappsv1alpha1.DeployFlow, string, error) {
+// RollbackDeploy rolls back the workload to the given deploy, or to the given revision of a CloneSet if deployName is empty.
+// The latest successful revision other than the current one is used if neither is given.
+func RollbackDeploy(ns, clonesetName, deployName string, revision int64, cl client.Client, strategy *tritonappsv1alpha1.DeployUpdateStrategy, logger *logrus.Entry) (*tritonappsv1alpha1.DeployFlow, string, error) {
 	action := setting.Rollback
 
-	cs, _, _ := fetcher.GetCloneSetInCache(ns, clonesetName, cl)
+	obj, err := getWorkload(ns, clonesetName, "", cl)
+	if err != nil {
+		logger.WithError(err).Error("failed to fetch workload")
+		return nil, "", err
+	}
 
-	if err := preSteps(cs, action, cl); err != nil {
+	if err := preSteps(obj, action, cl); err != nil {
 		logger.WithError(err).Error("pre steps failed")
 		return nil, "", terrors.NewConflict("pre steps failed", err)
 	}
 
-	var deploy *tritonappsv1alpha1.DeployFlow = nil
-	var err error
-	if deployName == "" {
-		// TODO: get latest revision from k8s cloneSet ControllerRevision objects
-		// k8s.io/kubectl/pkg/polymorphichelpers/rollback.go
-		// deploy, err = getLastSuccessfulDeployByRevisionInCache(ns, "revision", cl)
-	} else {
-		deploy, _, err = fetcher.GetDeployInCache(ns, deployName, cl)
+	if deployName != "" {
+		deploy, found, err := fetcher.GetDeployInCache(ns, deployName, cl)
+		if err != nil {
+			logger.WithError(err).Error("failed to get deploy")
+			return nil, "", fmt.Errorf("failed to get deploy: %w", err)
+		} else if !found {
+			return nil, "", terrors.NewNotFound(fmt.Sprintf("deploy %s not found", deployName))
+		}
+		return RollbackTo(deploy, workload.SpecReplicas(obj), strategy, nil, cl, logger)
 	}
 
-	if err != nil || deploy == nil {
-		logger.WithError(err).Error("failed to get deploy")
-		return nil, "", fmt.Errorf("failed to get deploy: %w", err)
+	cs, err := getCloneSet(obj)
+	if err != nil {
+		return nil, "", err
+	}
+	r, found, err := getRollbackRevision(cs, revision, cl)
+	if err != nil {
+		logger.WithError(err).Error("failed to get revisions")
+		return nil, "", fmt.Errorf("failed to get revisions: %w", err)
+	} else if !found {
+		return nil, "", terrors.NewNotFound("no revision to rollback to")
+	}
+	logger.Infof("Rollback to revision %s", r.Name)
+
+	if r.deploy != nil {
+		return RollbackTo(r.deploy.DeepCopy(), cs.Spec.Replicas, strategy, nil, cl, logger)
+	}
+
+	// the deploy of the revision is not found, create a rollback deploy from the template of the ControllerRevision.
+	ics := internalcloneset.FromCloneSet(cs)
+	g := generator{
+		appID:        ics.GetAppID(),
+		groupID:      ics.GetGroupID(),
+		replicas:     *cs.Spec.Replicas,
+		namespace:    ns,
+		appName:      ics.GetAppName(),
+		clonesetName: cs.Name,
+		action:       action,
+		applicationSpec: &tritonappsv1alpha1.ApplicationSpec{
+			AppID:        ics.GetAppID(),
+			GroupID:      ics.GetGroupID(),
+			AppName:      ics.GetAppName(),
+			CloneSetName: cs.Name,
+			Replicas:     cs.Spec.Replicas,
+			Template:     *r.template,
+
+			ApplicationType: workload.ApplicationTypeOf(cs),
+			// the workload is regenerated in a rollback, keep its PVCs, labels and annotations.
+			VolumeClaimTemplates:                 cs.Spec.VolumeClaimTemplates,
+			PersistentVolumeClaimRetentionPolicy: workload.PVCRetentionPolicyOf(cs),
+			ApplicationLabel:                     cs.Labels,
+			ApplicationAnnotation:                cs.Annotations,
+		},
+		updateStrategy: strategy,
+	}
+
+	updated, err := create(g.generate(), cl)
+	if err != nil {
+		logger.WithError(err).Error("failed to create deploy")
+		return nil, "", fmt.Errorf("failed to create deploy: %w", err)
 	}
+	logger.Info("Finished to rollback application")
 
+	return updated, r.Name, nil
+}
+
+// RollbackTo creates a rollback deploy from the given one with the current replicas of the workload if any,
+// the annotations are added to the new deploy.
+// It does not check whether the last deploy is finished, call it only when the last deploy is going to be finished.
+func RollbackTo(deploy *tritonappsv1alpha1.DeployFlow, replicas *int32, strategy *tritonappsv1alpha1.DeployUpdateStrategy,
+	annotations map[string]string, cl client.Client, logger *logrus.Entry) (*tritonappsv1alpha1.DeployFlow, string, error) {
 	logger.Infof("Start to rollback to deploy %s", deploy.Name)
 
 	oldName := deploy.Name
 
 	// modify the old deploy
 	deploy.SetName("")
-	deploy.Spec.Action = action
+	deploy.Spec.Action = setting.Rollback
 	deploy.Spec.UpdateStrategy = strategy
 	deploy.SetResourceVersion("")
+	if len(annotations) > 0 {
+		a := deploy.GetAnnotations()
+		if a == nil {
+			a = make(map[string]string, len(annotations))
+		}
+		for k, v := range annotations {
+			a[k] = v
+		}
+		deploy.SetAnnotations(a)
+	}
 
 	// do not change replicas
-	if cs != nil && cs.Spec.Replicas != nil {
-		*deploy.Spec.Application.Replicas = *cs.Spec.Replicas
+	if replicas != nil {
+		*deploy.Spec.Application.Replicas = *replicas
 	}
 
 	updated, err := create(deploy, cl)
@@ -231,6 +322,26 @@ func RollbackDeploy(ns, clonesetName, deployName string, cl client.Client, strat
 	return updated, oldName, nil
 }
 
+// LastSuccessfulDeploy returns the latest successful deploy which changes the revision of the CloneSet, except the excluded one.
+func LastSuccessfulDeploy(ns, clonesetName, exclude string, cl client.Client) (*tritonappsv1alpha1.DeployFlow, bool, error) {
+	deploys, err := fetcher.GetDeploysInCache(fetcher.DeployFilter{Namespace: ns, CloneSetName: clonesetName, PageSize: 10000}, cl)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// deploys are sorted by creation timestamp, the newest one comes first.
+	for _, d := range deploys {
+		if d.Name == exclude || !internaldeploy.RevisionChanged(d.Spec.Action) {
+			continue
+		}
+		if internaldeploy.FromDeploy(d).Success() {
+			return d, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
 func RemoveDeploy(ns, name string, cl client.Client, logger *logrus.Entry) error {
 
 	d, found, err := fetcher.GetDeployInCache(ns, name, cl)
@@ -274,7 +385,117 @@ func RemoveDeploy(ns, name string, cl client.Client, logger *logrus.Entry) error
 	return nil
 }
 
+// RetryBatch deletes the failed pods in current batch to be created again by the CloneSet, then resumes the deploy.
+// If skipFailedPods is true, the failed pods are accepted instead of being deleted.
+func RetryBatch(ns, name string, skipFailedPods bool, reader client.Reader, cl client.Client, logger *logrus.Entry) (*tritonappsv1alpha1.DeployFlow, error) {
+	d, err := fetcher.GetDeployFromAPIServer(ns, name, reader)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, terrors.NewNotFound(fmt.Sprintf("deploy %s not found", name))
+		}
+		logger.WithError(err).Error("failed to get deploy")
+		return nil, err
+	}
+
+	idl := internaldeploy.FromDeploy(d)
+	if idl.Finished() {
+		return nil, terrors.NewConflict("retrying a finished deploy is not allowed", nil)
+	}
+	failed := idl.CurrentBatchFailedPods()
+	if len(failed) == 0 {
+		return nil, terrors.NewConflict("there are no failed pods in current batch", nil)
+	}
+
+	if skipFailedPods {
+		logger.Infof("Start to accept failed pods %v", failed)
+	} else {
+		logger.Infof("Start to delete failed pods %v", failed)
+		for _, p := range failed {
+			if err := internalpod.DeletePod(ns, p, cl); err != nil {
+				logger.WithError(err).Errorf("failed to delete pod %s", p)
+				return nil, err
+			}
+		}
+	}
+
+	// the status may be updated by controllers at the same time, retry on conflict.
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		d, err := fetcher.GetDeployFromAPIServer(ns, name, reader)
+		if err != nil {
+			return err
+		}
+
+		idl := internaldeploy.FromDeploy(d)
+		if skipFailedPods {
+			idl.AcceptCurrentBatchFailedPods()
+		} else {
+			idl.RetryCurrentBatch()
+		}
+
+		return cl.Status().Update(context.TODO(), idl.Unwrap())
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to reset current batch")
+		return nil, err
+	}
+
+	logger.Info("Finished to retry current batch, resume the deploy")
+	return PatchDeployStrategy(ns, name, d.Spec.Action, reader, cl, []byte(`{"paused":false}`))
+}
+
+// Promote switches traffic of a blue/green deploy to the new pods, it takes effect once all new pods are ready.
+func Promote(ns, name string, reader client.Reader, cl client.Client, logger *logrus.Entry) (*tritonappsv1alpha1.DeployFlow, error) {
+	idl, err := getBlueGreenDeploy(ns, name, reader, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if bg := idl.Status.BlueGreen; bg != nil && bg.Phase != tritonappsv1alpha1.BlueGreenScalingUp && bg.Phase != tritonappsv1alpha1.BlueGreenPreview {
+		return nil, terrors.NewConflict(fmt.Sprintf("promoting a deploy in %s phase is not allowed", bg.Phase), nil)
+	}
+
+	logger.Info("Start to promote the deploy")
+	return PatchDeployStrategy(ns, name, idl.Spec.Action, reader, cl, []byte(`{"blueGreen":{"promoted":true}}`))
+}
+
+// SwitchBack switches traffic of a blue/green deploy back to the old pods, and removes the new pods.
+func SwitchBack(ns, name string, reader client.Reader, cl client.Client, logger *logrus.Entry) (*tritonappsv1alpha1.DeployFlow, error) {
+	idl, err := getBlueGreenDeploy(ns, name, reader, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if bg := idl.Status.BlueGreen; bg != nil && (bg.Phase == tritonappsv1alpha1.BlueGreenScalingDown || bg.Phase == tritonappsv1alpha1.BlueGreenSwitchedBack) {
+		return nil, terrors.NewConflict(fmt.Sprintf("switching back a deploy in %s phase is not allowed", bg.Phase), nil)
+	}
+
+	logger.Info("Start to switch back the deploy")
+	return PatchDeployStrategy(ns, name, idl.Spec.Action, reader, cl, []byte(`{"blueGreen":{"switchedBack":true}}`))
+}
+
+func getBlueGreenDeploy(ns, name string, reader client.Reader, logger *logrus.Entry) (*internaldeploy.Deploy, error) {
+	d, err := fetcher.GetDeployFromAPIServer(ns, name, reader)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, terrors.NewNotFound(fmt.Sprintf("deploy %s not found", name))
+		}
+		logger.WithError(err).Error("failed to get deploy")
+		return nil, err
+	}
+
+	idl := internaldeploy.FromDeploy(d)
+	if idl.BlueGreen() == nil {
+		return nil, terrors.NewConflict(fmt.Sprintf("deploy %s is not a blue/green deploy", name), nil)
+	}
+	if idl.Finished() {
+		return nil, terrors.NewConflict("switching traffic of a finished deploy is not allowed", nil)
+	}
+
+	return idl, nil
+}
+
 func SetDeploy(deploy *tritonappsv1alpha1.DeployFlow) *reply {
+	idl := internaldeploy.FromDeploy(deploy)
 
 	c := deploy.Status.Conditions
 	if len(c) == 0 {
@@ -292,6 +513,10 @@ func SetDeploy(deploy *tritonappsv1alpha1.DeployFlow) *reply {
 		Action:       deploy.Spec.Action,
 		Mode:         string(internaldeploy.FromDeploy(deploy).Mode()),
 
+		TimeoutSeconds:        int32(idl.Timeout().Seconds()),
+		GracefulPeriodSeconds: idl.GracefulPeriodSeconds(),
+		BakeRemainingSeconds:  int32(idl.BakeRemaining(idl.CurrentBatchInfo()).Seconds()),
+
 		AvailableReplicas:    deploy.Status.AvailableReplicas,
 		UpdatedReplicas:      deploy.Status.UpdatedReplicas,
 		UpdatedReadyReplicas: deploy.Status.UpdatedReadyReplicas,
@@ -307,5 +532,8 @@ func SetDeploy(deploy *tritonappsv1alpha1.DeployFlow) *reply {
 		StartedAt:            deploy.Status.StartedAt,
 		FinishedAt:           deploy.Status.FinishedAt,
 		UpdatedAt:            deploy.Status.UpdatedAt,
+		RollbackBy:           deploy.Status.RollbackBy,
+		RollbackOf:           deploy.Status.RollbackOf,
+		Revert:               deploy.Status.Revert,
 	}
 }
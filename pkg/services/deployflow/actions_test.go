@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployflow
+
+import (
+	"testing"
+
+	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	"github.com/triton-io/triton/pkg/kube/types/workload"
+	"github.com/triton-io/triton/pkg/setting"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestGenerateNonUpdateDeploy checks that a deploy generated by a restart or a scale request of the workload name only
+// selects the pods of the workload by its labels.
+func TestGenerateNonUpdateDeploy(t *testing.T) {
+	var replicas, target int32 = 5, 2
+	ls := workload.GetDefaultLabels("demo", "demo-blue", 1001, 2)
+	meta := metav1.ObjectMeta{Namespace: "default", Name: "demo-blue", Labels: ls}
+
+	tests := []struct {
+		name            string
+		applicationType string
+		action          string
+		replicas        *int32
+		expectedAction  string
+	}{
+		{
+			name:            "restart",
+			applicationType: tritonappsv1alpha1.ApplicationTypeStatefulSet,
+			action:          setting.Restart,
+			expectedAction:  setting.Restart,
+		},
+		{
+			name:            "scale",
+			applicationType: tritonappsv1alpha1.ApplicationTypeCloneSet,
+			action:          setting.Scale,
+			replicas:        &target,
+			expectedAction:  setting.ScaleIn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+			_ = kruiseappsv1alpha1.AddToScheme(scheme)
+			cl := fake.NewFakeClientWithScheme(scheme,
+				&kruiseappsv1alpha1.StatefulSet{ObjectMeta: meta, Spec: kruiseappsv1alpha1.StatefulSetSpec{Replicas: &replicas}},
+				&kruiseappsv1alpha1.CloneSet{ObjectMeta: meta, Spec: kruiseappsv1alpha1.CloneSetSpec{Replicas: &replicas}},
+			)
+
+			req := &DeployNonUpdateRequest{
+				Action: tt.action,
+				ApplicationSpec: &tritonappsv1alpha1.ApplicationSpec{
+					CloneSetName:    "demo-blue",
+					ApplicationType: tt.applicationType,
+					Replicas:        tt.replicas,
+				},
+			}
+			deploy, _, err := generateNonUpdateDeploy(req, "default", cl)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if deploy.Spec.Action != tt.expectedAction {
+				t.Errorf("expected action %s, got %s", tt.expectedAction, deploy.Spec.Action)
+			}
+			a := deploy.Spec.Application
+			if a.AppID != 1001 || a.GroupID != 2 || a.AppName != "demo" || a.ApplicationType != tt.applicationType {
+				t.Errorf("unexpected application %d/%d %s of %s", a.AppID, a.GroupID, a.AppName, a.ApplicationType)
+			}
+			for k, v := range ls {
+				if deploy.Labels[k] != v || a.Selector.MatchLabels[k] != v {
+					t.Errorf("expected label %s=%s, got %q in labels and %q in selector", k, v, deploy.Labels[k], a.Selector.MatchLabels[k])
+				}
+			}
+		})
+	}
+}
@@ -3,9 +3,9 @@ package deployflow
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"time"
 
-	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
 	"github.com/pkg/errors"
 	"github.com/triton-io/triton/pkg/kube/fetcher"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -13,8 +13,11 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
 	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	terrors "github.com/triton-io/triton/pkg/errors"
 	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	"github.com/triton-io/triton/pkg/kube/types/workload"
 )
 
 type filter struct {
@@ -24,8 +27,20 @@ type filter struct {
 	PageSize     int    `form:"pageSize"`
 }
 
+type createOptions struct {
+	// DryRun previews the deploy without creating it.
+	DryRun bool `form:"dryRun"`
+}
+
+type diffFilter struct {
+	From string `form:"from" binding:"required"`
+	To   string `form:"to"`
+}
+
 type rollbackRequest struct {
 	DeployName string `json:"deployName"`
+	// Revision is the revision of the ControllerRevision to roll back to, used if deployName is empty.
+	Revision int64 `json:"revision"`
 
 	UpdateStrategy *tritonappsv1alpha1.DeployUpdateStrategy `json:"updateStrategy,omitempty"`
 }
@@ -36,6 +51,10 @@ type scaleRequest struct {
 	NonUpdateStrategy *tritonappsv1alpha1.DeployNonUpdateStrategy `json:"nonUpdateStrategy,omitempty"`
 }
 
+type retryRequest struct {
+	SkipFailedPods bool `json:"skipFailedPods"`
+}
+
 type rollbackResponse struct {
 	RollbackTo string `json:"rollbackTo"`
 	DeployName string `json:"deployName"`
@@ -60,6 +79,12 @@ type reply struct {
 	Action       string `json:"action"`
 	Mode         string `json:"mode,omitempty"`
 
+	TimeoutSeconds        int32 `json:"timeoutSeconds,omitempty"`
+	GracefulPeriodSeconds int32 `json:"gracefulPeriodSeconds,omitempty"`
+
+	// BakeRemainingSeconds is the remaining bake time of current batch.
+	BakeRemainingSeconds int32 `json:"bakeRemainingSeconds,omitempty"`
+
 	AvailableReplicas    int32                               `json:"availableReplicas"`
 	UpdatedReplicas      int32                               `json:"updatedReplicas"`
 	UpdatedReadyReplicas int32                               `json:"updatedReadyReplicas"`
@@ -75,6 +100,9 @@ type reply struct {
 	StartedAt            metav1.Time                         `json:"startedAt,omitempty"`
 	FinishedAt           metav1.Time                         `json:"finishedAt,omitempty"`
 	UpdatedAt            metav1.Time                         `json:"updatedAt,omitempty"`
+	RollbackBy           string                              `json:"rollbackBy,omitempty"`
+	RollbackOf           string                              `json:"rollbackOf,omitempty"`
+	Revert               *tritonappsv1alpha1.RevertStatus    `json:"revert,omitempty"`
 }
 
 func patch(ns, name string, patchBytes []byte, reader client.Reader, cl client.Client) (*tritonappsv1alpha1.DeployFlow, error) {
@@ -127,12 +155,45 @@ func create(deploy *tritonappsv1alpha1.DeployFlow, cl client.Client) (*tritonapp
 	return updated, nil
 }
 
-func preSteps(cs *kruiseappsv1alpha1.CloneSet, action string, cl client.Client) error {
-	if cs == nil {
+// getWorkload returns the workload of the application. The application type is taken from the latest deploy of it
+// if it is not given, a CloneSet is assumed if there is no deploy.
+func getWorkload(ns, name, applicationType string, cl client.Client) (workload.Object, error) {
+	if applicationType == "" {
+		deploys, err := fetcher.GetDeploysInCache(fetcher.DeployFilter{Namespace: ns, CloneSetName: name, PageSize: 1}, cl)
+		if err != nil {
+			return nil, err
+		}
+		if len(deploys) > 0 && deploys[0].Spec.Application != nil {
+			applicationType = deploys[0].Spec.Application.ApplicationType
+		}
+	}
+
+	obj, found, err := fetcher.GetWorkloadInCache(ns, name, applicationType, cl)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		return nil, terrors.NewNotFound("application not found")
+	}
+
+	return obj, nil
+}
+
+// getCloneSet returns the workload as a CloneSet, revisions are recorded by the ControllerRevisions of a CloneSet only.
+func getCloneSet(obj workload.Object) (*kruiseappsv1alpha1.CloneSet, error) {
+	cs, ok := obj.(*kruiseappsv1alpha1.CloneSet)
+	if !ok {
+		return nil, terrors.NewConflict(fmt.Sprintf("revisions are supported by CloneSet only, not by %s", workload.ApplicationTypeOf(obj)), nil)
+	}
+
+	return cs, nil
+}
+
+func preSteps(cs workload.Object, action string, cl client.Client) error {
+	if cs == nil || reflect.ValueOf(cs).IsNil() {
 		return nil
 	}
 
-	deploy, found, err := fetcher.GetDeployInCacheOwningCloneSet(cs, cl)
+	deploy, found, err := fetcher.GetDeployInCacheOwning(cs, cl)
 	if err != nil {
 		return err
 	} else if !found {
@@ -35,8 +35,8 @@ func (g *generator) generate() *tritonappsv1alpha1.DeployFlow {
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: fmt.Sprintf("%s-", g.clonesetName),
 			Namespace:    g.namespace,
-			Labels:       g.getDefaultLabels(),
-			Annotations:  g.getLastAppliedAnnotations(lastApplied),
+			Labels:       g.getLabels(),
+			Annotations:  g.getAnnotations(lastApplied),
 		},
 		Spec: tritonappsv1alpha1.DeployFlowSpec{
 			Application: &tritonappsv1alpha1.ApplicationSpec{
@@ -47,6 +47,13 @@ func (g *generator) generate() *tritonappsv1alpha1.DeployFlow {
 				CloneSetName: g.applicationSpec.CloneSetName,
 				Template:     g.applicationSpec.Template,
 				Replicas:     &g.replicas,
+
+				ApplicationType:      g.applicationSpec.ApplicationType,
+				VolumeClaimTemplates: g.applicationSpec.VolumeClaimTemplates,
+
+				PersistentVolumeClaimRetentionPolicy: g.applicationSpec.PersistentVolumeClaimRetentionPolicy,
+				ApplicationLabel:                     g.applicationSpec.ApplicationLabel,
+				ApplicationAnnotation:                g.applicationSpec.ApplicationAnnotation,
 			},
 
 			Action:            g.action,
@@ -60,6 +67,16 @@ func (g *generator) getDefaultLabels() labels.Set {
 	return workload.GetDefaultLabels(g.appName, g.clonesetName, g.appID, g.groupID)
 }
 
+// getLabels returns the labels of the DeployFlow, the default labels take precedence over the application labels.
+func (g *generator) getLabels() labels.Set {
+	return labels.Merge(g.applicationSpec.ApplicationLabel, g.getDefaultLabels())
+}
+
+// getAnnotations returns the application annotations with the last applied one.
+func (g *generator) getAnnotations(lastApplied []byte) labels.Set {
+	return labels.Merge(g.applicationSpec.ApplicationAnnotation, g.getLastAppliedAnnotations(lastApplied))
+}
+
 func (g *generator) getLastAppliedAnnotations(lastApplied []byte) labels.Set {
 	return labels.Set{
 		setting.LastAppliedLabel: string(lastApplied),
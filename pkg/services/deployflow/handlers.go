@@ -1,7 +1,6 @@
 package deployflow
 
 import (
-	internalcloneset "github.com/triton-io/triton/pkg/kube/types/cloneset"
 	"io"
 
 	terrors "github.com/triton-io/triton/pkg/errors"
@@ -15,6 +14,7 @@ import (
 	"github.com/sirupsen/logrus"
 	kubeclient "github.com/triton-io/triton/pkg/kube/client"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
 	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
@@ -96,6 +96,22 @@ func CreateDeploy(c *gin.Context) {
 	// 创建 k8s客户端管理器
 	mgr := kubeclient.NewManager()
 	cl := mgr.GetClient()
+	// 预览模式，不创建部署
+	o := &createOptions{}
+	if err := c.ShouldBindQuery(o); err != nil {
+		response.BadRequestWithMessage(err.Error(), c)
+		return
+	}
+	if o.DryRun {
+		p, err := PlanUpdateDeploy(ns, r, cl)
+		if err != nil {
+			dLogger.WithError(err).Error("failed to plan deploy")
+			response.ServerErrorWithMessage(err.Error(), c)
+			return
+		}
+		response.OkDetailed(p, "success", c)
+		return
+	}
 	// 核心创建逻辑
 	updated, err := CreateUpdateDeploy(ns, r, cl, dLogger)
 	if err != nil {
@@ -147,7 +163,7 @@ func CreateRollback(c *gin.Context) {
 		"deploy":       r.DeployName,
 	})
 
-	updated, oldName, err := RollbackDeploy(ns, clonesetName, r.DeployName, cl, r.UpdateStrategy, dLogger)
+	updated, oldName, err := RollbackDeploy(ns, clonesetName, r.DeployName, r.Revision, cl, r.UpdateStrategy, dLogger)
 	if err != nil {
 		if terrors.IsNotFound(err) {
 			response.NotFound(c)
@@ -164,6 +180,89 @@ func CreateRollback(c *gin.Context) {
 	dLogger.Info("Finished to rollback application")
 }
 
+func GetRevisions(c *gin.Context) {
+	ns := c.Param("namespace")
+	clonesetName := c.Param("name")
+
+	dLogger := log.WithFields(logrus.Fields{
+		"namespace":    ns,
+		"clonesetName": clonesetName,
+	})
+
+	revisions, err := ListRevisions(ns, clonesetName, kubeclient.NewManager().GetClient())
+	if err != nil {
+		if terrors.IsNotFound(err) {
+			response.NotFound(c)
+		} else if terrors.IsConflict(err) {
+			response.ConflictWithMessage(err.Error(), c)
+		} else {
+			dLogger.WithError(err).Error("failed to list revisions")
+			response.ServerErrorWithErrorAndMessage(err, "failed to list revisions", c)
+		}
+		return
+	}
+
+	response.OkDetailed(revisions, "success", c)
+}
+
+func GetHistory(c *gin.Context) {
+	ns := c.Param("namespace")
+	clonesetName := c.Param("name")
+
+	dLogger := log.WithFields(logrus.Fields{
+		"namespace":    ns,
+		"clonesetName": clonesetName,
+	})
+
+	history, err := ListHistory(ns, clonesetName, kubeclient.NewManager().GetClient())
+	if err != nil {
+		if terrors.IsNotFound(err) {
+			response.NotFound(c)
+		} else if terrors.IsConflict(err) {
+			response.ConflictWithMessage(err.Error(), c)
+		} else {
+			dLogger.WithError(err).Error("failed to list history")
+			response.ServerErrorWithErrorAndMessage(err, "failed to list history", c)
+		}
+		return
+	}
+
+	response.OkDetailed(history, "success", c)
+}
+
+func GetDiff(c *gin.Context) {
+	ns := c.Param("namespace")
+	clonesetName := c.Param("name")
+
+	f := &diffFilter{}
+	if err := c.ShouldBindQuery(f); err != nil {
+		response.BadRequestWithMessage(err.Error(), c)
+		return
+	}
+
+	dLogger := log.WithFields(logrus.Fields{
+		"namespace":    ns,
+		"clonesetName": clonesetName,
+		"from":         f.From,
+		"to":           f.To,
+	})
+
+	d, err := DiffRevisions(ns, clonesetName, f.From, f.To, kubeclient.NewManager().GetClient())
+	if err != nil {
+		if terrors.IsNotFound(err) {
+			response.NotFound(c)
+		} else if terrors.IsConflict(err) {
+			response.ConflictWithMessage(err.Error(), c)
+		} else {
+			dLogger.WithError(err).Error("failed to diff revisions")
+			response.ServerErrorWithErrorAndMessage(err, "failed to diff revisions", c)
+		}
+		return
+	}
+
+	response.OkDetailed(d, "success", c)
+}
+
 func CreateRestart(c *gin.Context) {
 	ns := c.Param("namespace")
 	clonesetName := c.Param("name")
@@ -205,6 +304,77 @@ func DeleteDeploy(c *gin.Context) {
 	response.Deleted(c)
 }
 
+func CreateRetry(c *gin.Context) {
+	name := c.Param("name")
+	ns := c.Param("namespace")
+
+	r := &retryRequest{}
+	err := c.ShouldBindJSON(r)
+	if err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequestWithMessage(err.Error(), c)
+		return
+	}
+
+	dLogger := log.WithFields(logrus.Fields{
+		"namespace": ns,
+		"name":      name,
+	})
+	mgr := kubeclient.NewManager()
+	cl := mgr.GetClient()
+	cr := mgr.GetAPIReader()
+
+	d, err := RetryBatch(ns, name, r.SkipFailedPods, cr, cl, dLogger)
+	if err != nil {
+		if terrors.IsNotFound(err) {
+			response.NotFound(c)
+		} else if terrors.IsConflict(err) {
+			response.ConflictWithMessage(err.Error(), c)
+		} else {
+			response.ServerErrorWithMessage(err.Error(), c)
+		}
+		return
+	}
+
+	rep := setKubeDeployReply(d)
+	response.OkDetailed(rep, "success", c)
+}
+
+func CreatePromotion(c *gin.Context) {
+	switchTraffic(c, Promote)
+}
+
+func CreateSwitchBack(c *gin.Context) {
+	switchTraffic(c, SwitchBack)
+}
+
+func switchTraffic(c *gin.Context, action func(ns, name string, reader client.Reader, cl client.Client, logger *logrus.Entry) (*tritonappsv1alpha1.DeployFlow, error)) {
+	name := c.Param("name")
+	ns := c.Param("namespace")
+
+	dLogger := log.WithFields(logrus.Fields{
+		"namespace": ns,
+		"name":      name,
+	})
+	mgr := kubeclient.NewManager()
+	cl := mgr.GetClient()
+	cr := mgr.GetAPIReader()
+
+	d, err := action(ns, name, cr, cl, dLogger)
+	if err != nil {
+		if terrors.IsNotFound(err) {
+			response.NotFound(c)
+		} else if terrors.IsConflict(err) {
+			response.ConflictWithMessage(err.Error(), c)
+		} else {
+			response.ServerErrorWithMessage(err.Error(), c)
+		}
+		return
+	}
+
+	rep := setKubeDeployReply(d)
+	response.OkDetailed(rep, "success", c)
+}
+
 func GetDeploy(c *gin.Context) {
 	name := c.Param("name")
 	ns := c.Param("namespace")
@@ -280,25 +450,12 @@ func createNonUpdateDeploy(ns, clonesetName, action string, strategy *tritonapps
 	mgr := kubeclient.NewManager()
 	cl := mgr.GetClient()
 
-	cs, found, err := fetcher.GetCloneSetInCache(ns, clonesetName, cl)
-	if err != nil {
-		log.WithError(err).Error("failed to fetch cloneSet")
-		response.ServerErrorWithMessage(err.Error(), c)
-		return
-	} else if !found {
-		log.Errorf("cloneSet not found")
-		return
-	}
-
-	ics := internalcloneset.FromCloneSet(cs)
-
+	// the workload is resolved by CreateNonUpdateDeploy, the template, the application type and the IDs are taken from it.
 	applicationSpec := tritonappsv1alpha1.ApplicationSpec{
-		AppID:        ics.GetAppID(),
-		GroupID:      ics.GetGroupID(),
-		Replicas:     ics.Spec.Replicas,
-		AppName:      ics.GetAppName(),
-		Template:     ics.Spec.Template,
-		CloneSetName: ics.Name,
+		CloneSetName: clonesetName,
+	}
+	if action == setting.Scale {
+		applicationSpec.Replicas = &replicas
 	}
 
 	req := &DeployNonUpdateRequest{
@@ -0,0 +1,155 @@
+package deployflow
+
+import (
+	"fmt"
+	"strconv"
+
+	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	"github.com/triton-io/triton/pkg/diff"
+	terrors "github.com/triton-io/triton/pkg/errors"
+	"github.com/triton-io/triton/pkg/kube/fetcher"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	"github.com/triton-io/triton/pkg/kube/types/workload"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// HistoryEntry is a deploy which changed the revision of the CloneSet, whatever its outcome is.
+type HistoryEntry struct {
+	DeployName string `json:"deployName"`
+	Action     string `json:"action"`
+	// Revision is the revision number of the ControllerRevision, 0 means the ControllerRevision is not found.
+	Revision       int64                          `json:"revision"`
+	UpdateRevision string                         `json:"updateRevision"`
+	Current        bool                           `json:"current"`
+	Images         []string                       `json:"images"`
+	Phase          tritonappsv1alpha1.DeployPhase `json:"phase"`
+	RollbackOf     string                         `json:"rollbackOf,omitempty"`
+	RollbackBy     string                         `json:"rollbackBy,omitempty"`
+	CreatedAt      metav1.Time                    `json:"createdAt"`
+	StartedAt      metav1.Time                    `json:"startedAt"`
+	FinishedAt     metav1.Time                    `json:"finishedAt"`
+}
+
+// ListHistory returns the revision history of the CloneSet, the newest deploy comes first. Other workloads are rejected with a conflict.
+func ListHistory(ns, clonesetName string, cl client.Client) ([]*HistoryEntry, error) {
+	obj, err := getWorkload(ns, clonesetName, "", cl)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := getCloneSet(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	crs, err := fetcher.GetControllerRevisionsInCache(cs, cl)
+	if err != nil {
+		return nil, err
+	}
+	revisions := make(map[string]int64, len(crs))
+	for _, cr := range crs {
+		revisions[cr.Name] = cr.Revision
+	}
+
+	deploys, err := fetcher.GetDeploysInCache(fetcher.DeployFilter{Namespace: ns, CloneSetName: clonesetName, PageSize: 10000}, cl)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*HistoryEntry, 0, len(deploys))
+	for _, d := range deploys {
+		idl := internaldeploy.FromDeploy(d)
+		if !idl.RevisionChanged() {
+			continue
+		}
+
+		e := &HistoryEntry{
+			DeployName:     d.Name,
+			Action:         d.Spec.Action,
+			Revision:       revisions[d.Status.UpdateRevision],
+			UpdateRevision: d.Status.UpdateRevision,
+			Current:        d.Status.UpdateRevision != "" && d.Status.UpdateRevision == cs.Status.UpdateRevision,
+			Images:         getImages(getDeployTemplate(idl)),
+			Phase:          d.Status.Phase,
+			RollbackOf:     d.Status.RollbackOf,
+			RollbackBy:     d.Status.RollbackBy,
+			CreatedAt:      d.CreationTimestamp,
+			StartedAt:      d.Status.StartedAt,
+			FinishedAt:     d.Status.FinishedAt,
+		}
+		history = append(history, e)
+	}
+
+	return history, nil
+}
+
+// DiffRevisions returns the changes of the pod template from one revision to another, a revision is given by the name
+// of a deploy, or by the name or the number of a ControllerRevision of the CloneSet. The current template of the workload
+// is compared if to is empty.
+func DiffRevisions(ns, clonesetName, from, to string, cl client.Client) (*diff.TemplateDiff, error) {
+	fromTemplate, err := getHistoryTemplate(ns, clonesetName, from, cl)
+	if err != nil {
+		return nil, err
+	}
+
+	var toTemplate *corev1.PodTemplateSpec
+	if to == "" {
+		obj, err := getWorkload(ns, clonesetName, "", cl)
+		if err != nil {
+			return nil, err
+		}
+		toTemplate = workload.TemplateOf(obj)
+	} else if toTemplate, err = getHistoryTemplate(ns, clonesetName, to, cl); err != nil {
+		return nil, err
+	}
+
+	return diff.Templates(fromTemplate, toTemplate), nil
+}
+
+// getHistoryTemplate returns the pod template of the revision, a deploy is preferred if the name is taken by both.
+func getHistoryTemplate(ns, clonesetName, revision string, cl client.Client) (*corev1.PodTemplateSpec, error) {
+	d, found, err := fetcher.GetDeployInCache(ns, revision, cl)
+	if err != nil {
+		return nil, err
+	} else if found && d.Spec.Application != nil && d.Spec.Application.CloneSetName == clonesetName {
+		return getDeployTemplate(internaldeploy.FromDeploy(d)), nil
+	}
+
+	obj, err := getWorkload(ns, clonesetName, "", cl)
+	if err != nil {
+		return nil, err
+	}
+	// only a CloneSet is looked up for ControllerRevisions.
+	cs, ok := obj.(*kruiseappsv1alpha1.CloneSet)
+	if !ok {
+		return nil, terrors.NewNotFound(fmt.Sprintf("deploy %s not found", revision))
+	}
+
+	revisions, err := listRevisions(cs, cl)
+	if err != nil {
+		return nil, err
+	}
+	number, _ := strconv.ParseInt(revision, 10, 64)
+	for _, r := range revisions {
+		if (r.Name == revision || number > 0 && r.Revision == number) && r.template != nil {
+			return r.template, nil
+		}
+	}
+
+	return nil, terrors.NewNotFound(fmt.Sprintf("deploy or revision %s not found", revision))
+}
+
+func getImages(template *corev1.PodTemplateSpec) []string {
+	if template == nil {
+		return nil
+	}
+
+	images := make([]string, 0, len(template.Spec.Containers))
+	for _, c := range template.Spec.Containers {
+		images = append(images, c.Image)
+	}
+
+	return images
+}
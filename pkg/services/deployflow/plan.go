@@ -0,0 +1,64 @@
+package deployflow
+
+import (
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	"github.com/triton-io/triton/pkg/kube/types/workload"
+	"github.com/triton-io/triton/pkg/setting"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DeployPlan is a preview of a deploy, nothing is created or changed.
+type DeployPlan struct {
+	Action   string `json:"action"`
+	Replicas int32  `json:"replicas"`
+	// Conflict is why the deploy can not be created now, ex: the last deploy is in progress.
+	Conflict string                         `json:"conflict,omitempty"`
+	Batches  []internaldeploy.BatchPlan     `json:"batches"`
+	Deploy   *tritonappsv1alpha1.DeployFlow `json:"deploy"`
+}
+
+// PlanUpdateDeploy runs the same logic as CreateUpdateDeploy without creating the deploy.
+func PlanUpdateDeploy(ns string, r *DeployUpdateRequest, cl client.Client) (*DeployPlan, error) {
+	deploy, cs, err := generateUpdateDeploy(ns, r, cl)
+	if err != nil {
+		return nil, err
+	}
+
+	return planDeploy(deploy, cs, cl), nil
+}
+
+// PlanNonUpdateDeploy runs the same logic as CreateNonUpdateDeploy without creating the deploy.
+func PlanNonUpdateDeploy(r *DeployNonUpdateRequest, ns string, cl client.Client) (*DeployPlan, error) {
+	deploy, cs, err := generateNonUpdateDeploy(r, ns, cl)
+	if err != nil {
+		return nil, err
+	}
+
+	return planDeploy(deploy, cs, cl), nil
+}
+
+func planDeploy(deploy *tritonappsv1alpha1.DeployFlow, cs workload.Object, cl client.Client) *DeployPlan {
+	p := &DeployPlan{
+		Action:   deploy.Spec.Action,
+		Replicas: *deploy.Spec.Application.Replicas,
+		Deploy:   deploy,
+	}
+	if err := preSteps(cs, deploy.Spec.Action, cl); err != nil {
+		p.Conflict = err.Error()
+	}
+
+	var replicas, updatedReplicas int32
+	if cs != nil {
+		status := workload.StatusOf(cs)
+		replicas = status.Replicas
+		updatedReplicas = status.UpdatedReplicas
+	}
+	// the template is changed by an update, none of the pods is updated when the deploy is started.
+	if deploy.Spec.Action == setting.Update {
+		updatedReplicas = 0
+	}
+	p.Batches = internaldeploy.FromDeploy(deploy).Plan(replicas, updatedReplicas)
+
+	return p
+}
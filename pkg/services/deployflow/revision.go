@@ -0,0 +1,174 @@
+package deployflow
+
+import (
+	"encoding/json"
+	"sort"
+
+	kruiseappsv1alpha1 "github.com/openkruise/kruise-api/apps/v1alpha1"
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	"github.com/triton-io/triton/pkg/kube/fetcher"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Revision is a revision of the CloneSet which can be rolled back to.
+// It comes from a ControllerRevision of the CloneSet, or from a successful deploy if the ControllerRevision has been cleaned up.
+type Revision struct {
+	// Revision is the revision number of the ControllerRevision, 0 means the ControllerRevision is not found.
+	Revision int64 `json:"revision"`
+	// Name is the name of the ControllerRevision, the same as the updateRevision of the deploy.
+	Name string `json:"name"`
+	// Current is true if it is the update revision of the CloneSet.
+	Current bool `json:"current"`
+	// DeployName is the last successful deploy which changed the CloneSet to this revision.
+	DeployName string      `json:"deployName,omitempty"`
+	Images     []string    `json:"images"`
+	CreatedAt  metav1.Time `json:"createdAt"`
+
+	template *corev1.PodTemplateSpec
+	deploy   *tritonappsv1alpha1.DeployFlow
+}
+
+// cloneSetRevisionData is the data of ControllerRevisions created by CloneSet, only the pod template is recorded.
+type cloneSetRevisionData struct {
+	Spec struct {
+		Template corev1.PodTemplateSpec `json:"template"`
+	} `json:"spec"`
+}
+
+// ListRevisions returns the revisions of the CloneSet, the newest one comes first. Other workloads are rejected with a conflict.
+func ListRevisions(ns, clonesetName string, cl client.Client) ([]*Revision, error) {
+	obj, err := getWorkload(ns, clonesetName, "", cl)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := getCloneSet(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return listRevisions(cs, cl)
+}
+
+func listRevisions(cs *kruiseappsv1alpha1.CloneSet, cl client.Client) ([]*Revision, error) {
+	crs, err := fetcher.GetControllerRevisionsInCache(cs, cl)
+	if err != nil {
+		return nil, err
+	}
+	deploys, err := fetcher.GetDeploysInCache(fetcher.DeployFilter{Namespace: cs.Namespace, CloneSetName: cs.Name, PageSize: 10000}, cl)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*Revision, 0, len(crs))
+	byName := make(map[string]*Revision, len(crs))
+	for _, cr := range crs {
+		r := &Revision{
+			Revision:  cr.Revision,
+			Name:      cr.Name,
+			Current:   cr.Name == cs.Status.UpdateRevision,
+			CreatedAt: cr.CreationTimestamp,
+			template:  getControllerRevisionTemplate(cr),
+		}
+		revisions = append(revisions, r)
+		byName[cr.Name] = r
+	}
+
+	// the ControllerRevisions may be cleaned up by revisionHistoryLimit, the successful deploys are still revisions to roll back to.
+	// deploys are sorted by creation timestamp, the newest one comes first.
+	var history []*Revision
+	for _, d := range deploys {
+		idl := internaldeploy.FromDeploy(d)
+		if !idl.RevisionChanged() || !idl.Success() || d.Status.UpdateRevision == "" {
+			continue
+		}
+
+		r, ok := byName[d.Status.UpdateRevision]
+		if !ok {
+			r = &Revision{
+				Name:      d.Status.UpdateRevision,
+				Current:   d.Status.UpdateRevision == cs.Status.UpdateRevision,
+				CreatedAt: d.CreationTimestamp,
+				template:  getDeployTemplate(idl),
+			}
+			history = append(history, r)
+			byName[r.Name] = r
+		}
+		if r.deploy == nil {
+			r.deploy = d
+			r.DeployName = d.Name
+		}
+		if r.template == nil {
+			r.template = getDeployTemplate(idl)
+		}
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[j].CreatedAt.Before(&history[i].CreatedAt)
+	})
+	revisions = append(revisions, history...)
+
+	for _, r := range revisions {
+		r.Images = getImages(r.template)
+	}
+
+	return revisions, nil
+}
+
+// getRollbackRevision returns the revision to roll back to, the latest revision other than the current one is returned if revision is 0.
+// A revision with a successful deploy is preferred since it records the whole application.
+func getRollbackRevision(cs *kruiseappsv1alpha1.CloneSet, revision int64, cl client.Client) (*Revision, bool, error) {
+	revisions, err := listRevisions(cs, cl)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if revision != 0 {
+		for _, r := range revisions {
+			if r.Revision == revision {
+				return r, r.deploy != nil || r.template != nil, nil
+			}
+		}
+		return nil, false, nil
+	}
+
+	var candidate *Revision
+	for _, r := range revisions {
+		if r.Current {
+			continue
+		}
+		if r.deploy != nil && (candidate == nil || candidate.deploy == nil || candidate.deploy.CreationTimestamp.Before(&r.deploy.CreationTimestamp)) {
+			candidate = r
+		} else if candidate == nil && r.template != nil {
+			candidate = r
+		}
+	}
+
+	return candidate, candidate != nil, nil
+}
+
+func getControllerRevisionTemplate(cr *appsv1.ControllerRevision) *corev1.PodTemplateSpec {
+	if len(cr.Data.Raw) == 0 {
+		return nil
+	}
+	data := &cloneSetRevisionData{}
+	if err := json.Unmarshal(cr.Data.Raw, data); err != nil {
+		return nil
+	}
+
+	return &data.Spec.Template
+}
+
+// getDeployTemplate returns the pod template applied by the deploy, the last-applied annotation is preferred.
+func getDeployTemplate(idl *internaldeploy.Deploy) *corev1.PodTemplateSpec {
+	if spec, err := idl.GetLastApplied(); err == nil && spec != nil && len(spec.Template.Spec.Containers) > 0 {
+		return &spec.Template
+	}
+	if idl.Spec.Application != nil {
+		return &idl.Spec.Application.Template
+	}
+
+	return nil
+}
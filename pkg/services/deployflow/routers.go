@@ -11,12 +11,25 @@ func (*Router) SetupRouters(router *gin.RouterGroup) *gin.RouterGroup {
 	router.GET("/namespaces/:namespace/deployflows", GetDeploys)
 	// 获取单个部署，GET /api/v1/namespaces/{namespace}/deployflows/{name}
 	router.GET("/namespaces/:namespace/deployflows/:name", GetDeploy)
-	// 创建部署（需要JSON体），POST /api/v1/namespaces/{namespace}/deployflows
+	// 创建部署（需要JSON体），POST /api/v1/namespaces/{namespace}/deployflows，?dryRun=true 时只预览不创建
 	router.POST("/namespaces/:namespace/deployflows", CreateDeploy)
 	// 部分更新部署（需要JSON体），PUT /api/v1/namespaces/{namespace}/deployflows/{name}
 	router.PATCH("/namespaces/:namespace/deployflows/:name", PatchDeploy)
 	// 删除部署，DELETE /api/v1/namespaces/{namespace}/deployflows/{name}
 	router.DELETE("/namespaces/:namespace/deployflows/:name", DeleteDeploy)
+	// 重试当前批次（可选JSON体），POST /api/v1/namespaces/{namespace}/deployflows/{name}/retries
+	router.POST("/namespaces/:namespace/deployflows/:name/retries", CreateRetry)
+	// 蓝绿发布切换流量到新版本，POST /api/v1/namespaces/{namespace}/deployflows/{name}/promotions
+	router.POST("/namespaces/:namespace/deployflows/:name/promotions", CreatePromotion)
+	// 蓝绿发布切回旧版本，POST /api/v1/namespaces/{namespace}/deployflows/{name}/switchbacks
+	router.POST("/namespaces/:namespace/deployflows/:name/switchbacks", CreateSwitchBack)
+	// 获取可回滚的版本列表，GET /api/v1/namespaces/{namespace}/instances/{name}/revisions
+	router.GET("/namespaces/:namespace/instances/:name/revisions", GetRevisions)
+	// 获取发布历史，GET /api/v1/namespaces/{namespace}/instances/{name}/history
+	router.GET("/namespaces/:namespace/instances/:name/history", GetHistory)
+	// 对比两个版本的差异，GET /api/v1/namespaces/{namespace}/instances/{name}/diff?from={revision}&to={revision}
+	// 版本可以是 deploy 名称，ControllerRevision 名称或者版本号
+	router.GET("/namespaces/:namespace/instances/:name/diff", GetDiff)
 	// 回滚操作，POST /api/v1/namespaces/{namespace}/instances/{name}/rollbacks
 	router.POST("/namespaces/:namespace/instances/:name/rollbacks", CreateRollback)
 	// 重启实例，POST /api/v1/namespaces/{namespace}/instances/{name}/restarts
@@ -3,15 +3,22 @@ package setting
 // setting.go stores things which may change over time.
 
 const (
-	LastAppliedLabel = "kubectl.kubernetes.io/last-applied-configuration"
-	ShouldSendToFile = "sendToFile"
-	AppLabel         = "app.kubernetes.io/name"
-	AppInstanceLabel = "app.kubernetes.io/instance"
-	PodReadinessGate = "apps.triton.io/ready"
-	ApplicationPort  = "app-port"
-	AppIDLabel       = "app"
-	GroupIDLabel     = "group"
-	ManageLabel      = "managed-by"
-	TritonKey        = "triton-io"
-	HarborCred       = "proharborregcred"
+	LastAppliedLabel     = "kubectl.kubernetes.io/last-applied-configuration"
+	ShouldSendToFile     = "sendToFile"
+	AppLabel             = "app.kubernetes.io/name"
+	AppInstanceLabel     = "app.kubernetes.io/instance"
+	PodReadinessGate     = "apps.triton.io/ready"
+	CanaryLabel          = "apps.triton.io/canary"
+	RollbackOfAnnotation = "apps.triton.io/rollback-of"
+	ApplicationPort      = "app-port"
+	AppIDLabel           = "app"
+	GroupIDLabel         = "group"
+	ManageLabel          = "managed-by"
+	TritonKey            = "triton-io"
+	HarborCred           = "proharborregcred"
+
+	// PVCRetentionPolicyAnnotation records the PVC retention policy of the workload, in JSON.
+	PVCRetentionPolicyAnnotation = "apps.triton.io/pvc-retention-policy"
+	// DefaultsConfigMap holds the defaults of the deploys in its namespace.
+	DefaultsConfigMap = "triton-defaults"
 )
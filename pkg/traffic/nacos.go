@@ -0,0 +1,247 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package traffic
+
+import (
+	"errors"
+	"flag"
+	"sync"
+
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	"github.com/triton-io/triton/pkg/traffic/nacos"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Nacos is the name of the provider which registers pods as instances of a Nacos service.
+const Nacos = "nacos"
+
+func init() {
+	flag.StringVar(&nacosConfig.Addr, "nacos-addr", "", "The address of Nacos server, ex: http://127.0.0.1:8848")
+	flag.StringVar(&nacosConfig.Namespace, "nacos-namespace", "", "The id of Nacos namespace where instances are registered.")
+	flag.StringVar(&nacosConfig.Username, "nacos-username", "", "The username to log in Nacos, leave it empty if auth is disabled.")
+	flag.StringVar(&nacosConfig.Password, "nacos-password", "", "The password to log in Nacos.")
+	flag.DurationVar(&nacosConfig.Timeout, "nacos-timeout", 0, "Timeout of requests to Nacos server. Defaults 5s")
+	flag.StringVar(&nacosGroup, "nacos-group", nacos.DefaultGroup, "The group of Nacos services.")
+	flag.StringVar(&nacosCluster, "nacos-cluster", nacos.DefaultCluster, "The cluster of Nacos instances.")
+	flag.Float64Var(&nacosWeight, "nacos-weight", 1, "The weight of an instance when it is enabled.")
+
+	RegisterProvider(Nacos, newNacosProvider)
+}
+
+var (
+	nacosConfig  nacos.Config
+	nacosGroup   string
+	nacosCluster string
+	nacosWeight  float64
+
+	nacosOnce   sync.Once
+	nacosClient *nacos.Client
+)
+
+// nacosProvider registers pods as disabled instances of the Nacos service named after the app,
+// instances are enabled when pulled in, and disabled then deregistered when pulled out.
+type nacosProvider struct {
+	nacos   *nacos.Client
+	group   string
+	cluster string
+	weight  float64
+}
+
+var _ Provider = &nacosProvider{}
+var _ Switcher = &nacosProvider{}
+var _ Drainer = &nacosProvider{}
+
+func newNacosProvider(_ client.Client) (Provider, error) {
+	if nacosConfig.Addr == "" {
+		return nil, errors.New("nacos server address is not set, please specify it by --nacos-addr")
+	}
+
+	nacosOnce.Do(func() {
+		nacosClient = nacos.NewClient(nacosConfig)
+	})
+
+	return NewNacosProvider(nacosClient, nacosGroup, nacosCluster, nacosWeight), nil
+}
+
+// NewNacosProvider returns a nacos provider with the given client.
+func NewNacosProvider(cl *nacos.Client, group, cluster string, weight float64) Provider {
+	return &nacosProvider{nacos: cl, group: group, cluster: cluster, weight: weight}
+}
+
+// Register registers pods which are not in Nacos yet as disabled instances.
+func (p *nacosProvider) Register(d *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) error {
+	instances, err := p.instances(d)
+	if err != nil {
+		return err
+	}
+
+	for i := range pods {
+		if _, ok := instances[podAddr(pods[i])]; ok {
+			continue
+		}
+
+		ins := p.newInstance(d, pods[i])
+		if err := p.nacos.RegisterInstance(ins); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *nacosProvider) Deregister(d *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) error {
+	instances, err := p.instances(d)
+	if err != nil {
+		return err
+	}
+
+	for i := range pods {
+		ins, ok := instances[podAddr(pods[i])]
+		if !ok {
+			continue
+		}
+
+		if err := p.nacos.DeregisterInstance(ins); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *nacosProvider) Enable(d *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) error {
+	return p.setEnabled(d, pods, true)
+}
+
+func (p *nacosProvider) Disable(d *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) error {
+	return p.setEnabled(d, pods, false)
+}
+
+// IsEnabled returns pods whose instances are enabled and healthy.
+func (p *nacosProvider) IsEnabled(d *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) ([]string, error) {
+	instances, err := p.instances(d)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make([]string, 0, len(pods))
+	for i := range pods {
+		ins, ok := instances[podAddr(pods[i])]
+		if ok && ins.Enabled && ins.Healthy {
+			enabled = append(enabled, pods[i].Name)
+		}
+	}
+
+	return enabled, nil
+}
+
+// Drained returns true if none of the pods has an enabled instance.
+func (p *nacosProvider) Drained(d *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) (bool, error) {
+	instances, err := p.instances(d)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range pods {
+		if ins, ok := instances[podAddr(pods[i])]; ok && ins.Enabled {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (p *nacosProvider) setEnabled(d *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo, enabled bool) error {
+	instances, err := p.instances(d)
+	if err != nil {
+		return err
+	}
+
+	for i := range pods {
+		ins, ok := instances[podAddr(pods[i])]
+		if !ok {
+			if !enabled {
+				// nothing to disable.
+				continue
+			}
+			// the pod is not registered yet, ex: pods in a non-canary batch.
+			ins = p.newInstance(d, pods[i])
+		} else if ins.Enabled == enabled {
+			continue
+		}
+
+		ins.Enabled = enabled
+		if enabled {
+			ins.Weight = p.weight
+		}
+		if !ok {
+			err = p.nacos.RegisterInstance(ins)
+		} else {
+			err = p.nacos.UpdateInstance(ins)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// instances returns the instances of the app indexed by ip:port.
+func (p *nacosProvider) instances(d *internaldeploy.Deploy) (map[string]*nacos.Instance, error) {
+	list, err := p.nacos.ListInstances(serviceName(d), p.group, p.cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make(map[string]*nacos.Instance, len(list))
+	for i := range list {
+		ins := &list[i]
+		ins.ServiceName = serviceName(d)
+		ins.GroupName = p.group
+		instances[ins.Addr()] = ins
+	}
+
+	return instances, nil
+}
+
+func (p *nacosProvider) newInstance(d *internaldeploy.Deploy, pod tritonappsv1alpha1.PodInfo) *nacos.Instance {
+	return &nacos.Instance{
+		ServiceName: serviceName(d),
+		GroupName:   p.group,
+		ClusterName: p.cluster,
+		IP:          pod.IP,
+		Port:        pod.Port,
+		Weight:      p.weight,
+		// instances are persistent, so that Nacos server checks their health, and they must be deregistered explicitly.
+		Ephemeral: false,
+		Metadata: map[string]string{
+			"pod":      pod.Name,
+			"cloneset": d.GetCloneSetName(),
+		},
+	}
+}
+
+func serviceName(d *internaldeploy.Deploy) string {
+	return d.Spec.Application.AppName
+}
+
+func podAddr(pod tritonappsv1alpha1.PodInfo) string {
+	ins := nacos.Instance{IP: pod.IP, Port: pod.Port}
+	return ins.Addr()
+}
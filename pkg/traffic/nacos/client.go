@@ -0,0 +1,250 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nacos is a minimal client of the Nacos naming open API.
+// See https://nacos.io/en-us/docs/open-api.html
+package nacos
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	instancePath     = "/nacos/v1/ns/instance"
+	instanceListPath = "/nacos/v1/ns/instance/list"
+	loginPath        = "/nacos/v1/auth/login"
+
+	DefaultGroup   = "DEFAULT_GROUP"
+	DefaultCluster = "DEFAULT"
+)
+
+// Config is the configuration of a Client.
+type Config struct {
+	// Addr is the address of Nacos server, ex: http://127.0.0.1:8848
+	Addr string
+	// Namespace is the id of Nacos namespace, empty means the public namespace.
+	Namespace string
+	// Username and Password are used to log in if the auth of Nacos server is enabled.
+	Username string
+	Password string
+	Timeout  time.Duration
+}
+
+// Instance is an instance of a Nacos service.
+type Instance struct {
+	ServiceName string            `json:"serviceName"`
+	GroupName   string            `json:"-"`
+	ClusterName string            `json:"clusterName"`
+	IP          string            `json:"ip"`
+	Port        int32             `json:"port"`
+	Weight      float64           `json:"weight"`
+	Enabled     bool              `json:"enabled"`
+	Healthy     bool              `json:"healthy"`
+	Ephemeral   bool              `json:"ephemeral"`
+	Metadata    map[string]string `json:"metadata"`
+}
+
+// Addr returns ip:port of the instance.
+func (i *Instance) Addr() string {
+	return fmt.Sprintf("%s:%d", i.IP, i.Port)
+}
+
+type instanceList struct {
+	Hosts []Instance `json:"hosts"`
+}
+
+type loginResult struct {
+	AccessToken string `json:"accessToken"`
+	TokenTTL    int64  `json:"tokenTtl"`
+}
+
+type Client struct {
+	cfg  Config
+	http *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expireAt    time.Time
+}
+
+func NewClient(cfg Config) *Client {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	cfg.Addr = strings.TrimSuffix(cfg.Addr, "/")
+
+	return &Client{
+		cfg:  cfg,
+		http: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// RegisterInstance registers an instance, it updates the instance if it exists.
+func (c *Client) RegisterInstance(ins *Instance) error {
+	_, err := c.do(http.MethodPost, instancePath, c.instanceParams(ins, true))
+	return err
+}
+
+// UpdateInstance updates weight, enabled and metadata of an instance.
+func (c *Client) UpdateInstance(ins *Instance) error {
+	_, err := c.do(http.MethodPut, instancePath, c.instanceParams(ins, true))
+	return err
+}
+
+func (c *Client) DeregisterInstance(ins *Instance) error {
+	_, err := c.do(http.MethodDelete, instancePath, c.instanceParams(ins, false))
+	return err
+}
+
+// ListInstances returns all instances of a service, including the unhealthy and disabled ones.
+func (c *Client) ListInstances(service, group, cluster string) ([]Instance, error) {
+	params := url.Values{}
+	params.Set("serviceName", service)
+	params.Set("groupName", groupOrDefault(group))
+	params.Set("healthyOnly", "false")
+	if cluster != "" {
+		params.Set("clusters", cluster)
+	}
+	if c.cfg.Namespace != "" {
+		params.Set("namespaceId", c.cfg.Namespace)
+	}
+
+	body, err := c.do(http.MethodGet, instanceListPath, params)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &instanceList{}
+	if err := json.Unmarshal(body, l); err != nil {
+		return nil, fmt.Errorf("failed to decode instances of service %s: %v", service, err)
+	}
+
+	return l.Hosts, nil
+}
+
+func (c *Client) instanceParams(ins *Instance, withStatus bool) url.Values {
+	params := url.Values{}
+	params.Set("serviceName", ins.ServiceName)
+	params.Set("groupName", groupOrDefault(ins.GroupName))
+	params.Set("ip", ins.IP)
+	params.Set("port", strconv.Itoa(int(ins.Port)))
+	params.Set("ephemeral", strconv.FormatBool(ins.Ephemeral))
+	if ins.ClusterName != "" {
+		params.Set("clusterName", ins.ClusterName)
+	}
+	if c.cfg.Namespace != "" {
+		params.Set("namespaceId", c.cfg.Namespace)
+	}
+	if !withStatus {
+		return params
+	}
+
+	params.Set("weight", strconv.FormatFloat(ins.Weight, 'f', -1, 64))
+	params.Set("enabled", strconv.FormatBool(ins.Enabled))
+	if len(ins.Metadata) > 0 {
+		metadata, _ := json.Marshal(ins.Metadata)
+		params.Set("metadata", string(metadata))
+	}
+
+	return params
+}
+
+func (c *Client) do(method, path string, params url.Values) ([]byte, error) {
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		params.Set("accessToken", token)
+	}
+
+	req, err := http.NewRequest(method, c.cfg.Addr+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("nacos %s %s failed with status %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return body, nil
+}
+
+// token returns the access token, it logs in again if the token is about to expire.
+func (c *Client) token() (string, error) {
+	if c.cfg.Username == "" {
+		return "", nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.accessToken != "" && time.Now().Before(c.expireAt) {
+		return c.accessToken, nil
+	}
+
+	params := url.Values{}
+	params.Set("username", c.cfg.Username)
+	params.Set("password", c.cfg.Password)
+	resp, err := c.http.PostForm(c.cfg.Addr+loginPath, params)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to log in nacos with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	r := &loginResult{}
+	if err := json.Unmarshal(body, r); err != nil {
+		return "", fmt.Errorf("failed to decode login result: %v", err)
+	}
+	c.accessToken = r.AccessToken
+	// refresh the token before it expires.
+	c.expireAt = time.Now().Add(time.Duration(r.TokenTTL) * time.Second * 9 / 10)
+
+	return c.accessToken, nil
+}
+
+func groupOrDefault(group string) string {
+	if group == "" {
+		return DefaultGroup
+	}
+	return group
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nacos
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// fakeServer is a stand-in of the Nacos naming open API, it keeps instances in memory.
+type fakeServer struct {
+	mu        sync.Mutex
+	token     string
+	instances map[string]Instance
+}
+
+func newFakeServer(token string) (*fakeServer, *httptest.Server) {
+	f := &fakeServer{token: token, instances: map[string]Instance{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(loginPath, f.login)
+	mux.HandleFunc(instancePath, f.instance)
+	mux.HandleFunc(instanceListPath, f.list)
+
+	return f, httptest.NewServer(mux)
+}
+
+func (f *fakeServer) login(w http.ResponseWriter, r *http.Request) {
+	if r.FormValue("username") != "nacos" || r.FormValue("password") != "secret" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(loginResult{AccessToken: f.token, TokenTTL: 18000})
+}
+
+func (f *fakeServer) instance(w http.ResponseWriter, r *http.Request) {
+	if !f.authorized(w, r) {
+		return
+	}
+
+	q := r.URL.Query()
+	port, _ := strconv.Atoi(q.Get("port"))
+	ins := Instance{
+		ServiceName: q.Get("groupName") + "@@" + q.Get("serviceName"),
+		ClusterName: q.Get("clusterName"),
+		IP:          q.Get("ip"),
+		Port:        int32(port),
+		Healthy:     true,
+	}
+	key := ins.ServiceName + "/" + ins.Addr()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPost, http.MethodPut:
+		if _, ok := f.instances[key]; !ok && r.Method == http.MethodPut {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		ins.Weight, _ = strconv.ParseFloat(q.Get("weight"), 64)
+		ins.Enabled, _ = strconv.ParseBool(q.Get("enabled"))
+		ins.Ephemeral, _ = strconv.ParseBool(q.Get("ephemeral"))
+		_ = json.Unmarshal([]byte(q.Get("metadata")), &ins.Metadata)
+		f.instances[key] = ins
+	case http.MethodDelete:
+		delete(f.instances, key)
+	}
+	_, _ = w.Write([]byte("ok"))
+}
+
+func (f *fakeServer) list(w http.ResponseWriter, r *http.Request) {
+	if !f.authorized(w, r) {
+		return
+	}
+
+	q := r.URL.Query()
+	service := q.Get("groupName") + "@@" + q.Get("serviceName")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	l := instanceList{}
+	for _, ins := range f.instances {
+		if ins.ServiceName == service {
+			l.Hosts = append(l.Hosts, ins)
+		}
+	}
+	_ = json.NewEncoder(w).Encode(l)
+}
+
+func (f *fakeServer) authorized(w http.ResponseWriter, r *http.Request) bool {
+	if f.token != "" && r.URL.Query().Get("accessToken") != f.token {
+		w.WriteHeader(http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func TestInstanceLifecycle(t *testing.T) {
+	_, srv := newFakeServer("")
+	defer srv.Close()
+
+	c := NewClient(Config{Addr: srv.URL})
+	ins := &Instance{ServiceName: "demo", IP: "10.0.0.1", Port: 8080, Weight: 1, Metadata: map[string]string{"pod": "demo-0"}}
+
+	if err := c.RegisterInstance(ins); err != nil {
+		t.Fatalf("failed to register instance: %v", err)
+	}
+	list, err := c.ListInstances("demo", "", "")
+	if err != nil {
+		t.Fatalf("failed to list instances: %v", err)
+	}
+	if len(list) != 1 || list[0].Enabled || list[0].Metadata["pod"] != "demo-0" {
+		t.Fatalf("expected one disabled instance of pod demo-0, got %+v", list)
+	}
+
+	ins.Enabled = true
+	ins.Weight = 10
+	if err := c.UpdateInstance(ins); err != nil {
+		t.Fatalf("failed to update instance: %v", err)
+	}
+	list, _ = c.ListInstances("demo", "", "")
+	if len(list) != 1 || !list[0].Enabled || list[0].Weight != 10 {
+		t.Fatalf("expected an enabled instance with weight 10, got %+v", list)
+	}
+
+	if err := c.DeregisterInstance(ins); err != nil {
+		t.Fatalf("failed to deregister instance: %v", err)
+	}
+	list, _ = c.ListInstances("demo", "", "")
+	if len(list) != 0 {
+		t.Fatalf("expected no instances, got %+v", list)
+	}
+}
+
+func TestUpdateMissingInstance(t *testing.T) {
+	_, srv := newFakeServer("")
+	defer srv.Close()
+
+	c := NewClient(Config{Addr: srv.URL})
+	if err := c.UpdateInstance(&Instance{ServiceName: "demo", IP: "10.0.0.1", Port: 8080}); err == nil {
+		t.Fatal("expected an error when updating a missing instance")
+	}
+}
+
+func TestLogin(t *testing.T) {
+	_, srv := newFakeServer("token")
+	defer srv.Close()
+
+	c := NewClient(Config{Addr: srv.URL, Username: "nacos", Password: "secret"})
+	if _, err := c.ListInstances("demo", "", ""); err != nil {
+		t.Fatalf("failed to list instances with access token: %v", err)
+	}
+
+	c = NewClient(Config{Addr: srv.URL, Username: "nacos", Password: "wrong"})
+	if _, err := c.ListInstances("demo", "", ""); err == nil {
+		t.Fatal("expected an error with wrong password")
+	}
+}
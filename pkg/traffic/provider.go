@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package traffic
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Provider pulls pods in and out of the source where the traffic comes from,
+// ex: a Kubernetes Service, a service registry or a gateway.
+type Provider interface {
+	// Register makes the pods known to the traffic source, it is called for canary pods once they are
+	// ContainersReady, and for all pods of a batch when they are pulled in.
+	// Register must be idempotent.
+	Register(d *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) error
+
+	// Deregister stops the traffic to the pods, it is called before the pods are deleted.
+	// Deregister must be idempotent.
+	Deregister(d *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) error
+
+	// IsEnabled returns the names of pods which are receiving traffic right now.
+	IsEnabled(d *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) ([]string, error)
+}
+
+// Drainer is implemented by providers which stop the traffic asynchronously,
+// pods are deleted only after they are drained or the drain timeout is reached.
+type Drainer interface {
+	// Drained returns true if none of the pods is receiving traffic.
+	Drained(d *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) (bool, error)
+}
+
+// Switcher is implemented by providers which keep the registered instances and turn the traffic on and off,
+// instances are enabled when they are pulled in, and disabled before deregistered when they are pulled out.
+type Switcher interface {
+	Enable(d *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) error
+	Disable(d *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) error
+}
+
+// Factory creates a Provider with the given client.
+type Factory func(cl client.Client) (Provider, error)
+
+var (
+	mu        sync.RWMutex
+	factories = map[string]Factory{}
+)
+
+// RegisterProvider makes a provider available by the given name.
+func RegisterProvider(name string, f Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	factories[name] = f
+}
+
+// New returns the provider registered as name.
+func New(name string, cl client.Client) (Provider, error) {
+	mu.RLock()
+	f, ok := factories[name]
+	mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("traffic provider %q is not registered, candidates are %v", name, Providers())
+	}
+
+	return f(cl)
+}
+
+// Providers returns names of all registered providers.
+func Providers() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for n := range factories {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package traffic
+
+import (
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	"github.com/triton-io/triton/pkg/kube/fetcher"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	internalpod "github.com/triton-io/triton/pkg/kube/types/pod"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Readiness is the name of the default provider.
+const Readiness = "readiness"
+
+func init() {
+	RegisterProvider(Readiness, newReadinessProvider)
+}
+
+// readinessProvider treats a pod as enabled once it is Ready, which means traffic
+// comes in as long as the readiness gate is set to True.
+type readinessProvider struct {
+	client.Client
+}
+
+var _ Provider = &readinessProvider{}
+
+func newReadinessProvider(cl client.Client) (Provider, error) {
+	return &readinessProvider{Client: cl}, nil
+}
+
+// Register does nothing, the readiness gate is set by the DeployFlow controller before pulling in.
+func (p *readinessProvider) Register(_ *internaldeploy.Deploy, _ []tritonappsv1alpha1.PodInfo) error {
+	return nil
+}
+
+// Deregister does nothing, pods are out of traffic once they are deleted.
+func (p *readinessProvider) Deregister(_ *internaldeploy.Deploy, _ []tritonappsv1alpha1.PodInfo) error {
+	return nil
+}
+
+func (p *readinessProvider) IsEnabled(d *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) ([]string, error) {
+	enabled := make([]string, 0, len(pods))
+	for i := range pods {
+		pod, found, err := fetcher.GetPodInCache(d.Namespace, pods[i].Name, p.Client)
+		if err != nil {
+			return nil, err
+		}
+		if found && internalpod.FromPod(pod).Ready() {
+			enabled = append(enabled, pods[i].Name)
+		}
+	}
+
+	return enabled, nil
+}
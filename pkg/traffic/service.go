@@ -0,0 +1,223 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package traffic
+
+import (
+	"context"
+
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+	"github.com/triton-io/triton/pkg/kube/fetcher"
+	internaldeploy "github.com/triton-io/triton/pkg/kube/types/deploy"
+	internalpod "github.com/triton-io/triton/pkg/kube/types/pod"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Service is the name of the provider which uses Kubernetes Services and EndpointSlices only.
+const Service = "service"
+
+func init() {
+	RegisterProvider(Service, newServiceProvider)
+}
+
+// +kubebuilder:rbac:groups="",resources=pods/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+
+// serviceProvider pulls pods in and out by the readiness gate, and a pod is treated as enabled
+// only when it shows up as ready in the EndpointSlices of all Services selecting it.
+type serviceProvider struct {
+	client.Client
+}
+
+var _ Provider = &serviceProvider{}
+var _ Drainer = &serviceProvider{}
+
+func newServiceProvider(cl client.Client) (Provider, error) {
+	return &serviceProvider{Client: cl}, nil
+}
+
+func (p *serviceProvider) Register(d *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) error {
+	for i := range pods {
+		if err := internalpod.SetPodReadinessGate(d.Namespace, pods[i].Name, p.Client); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Deregister sets the readiness gate to False, endpoints controller will remove the pods from the EndpointSlices.
+func (p *serviceProvider) Deregister(d *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) error {
+	for i := range pods {
+		pod, found, err := fetcher.GetPodInCache(d.Namespace, pods[i].Name, p.Client)
+		if err != nil {
+			return err
+		}
+		if !found || !internalpod.FromPod(pod).HasReadinessGate() {
+			continue
+		}
+
+		if err := internalpod.UnsetPodReadinessGate(d.Namespace, pods[i].Name, p.Client); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *serviceProvider) IsEnabled(d *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) ([]string, error) {
+	svcs, err := p.listServices(d.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	endpoints := p.newEndpointCache(d.Namespace)
+
+	enabled := make([]string, 0, len(pods))
+	for i := range pods {
+		pod, found, err := fetcher.GetPodInCache(d.Namespace, pods[i].Name, p.Client)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+
+		selecting := selectingServices(svcs, pod)
+		if len(selecting) == 0 {
+			// no Service routes traffic to the pod, fallback to pod readiness.
+			if internalpod.FromPod(pod).Ready() {
+				enabled = append(enabled, pod.Name)
+			}
+			continue
+		}
+
+		up := true
+		for _, svc := range selecting {
+			ready, err := endpoints.ready(svc)
+			if err != nil {
+				return nil, err
+			}
+			if !ready.Has(pod.Status.PodIP) {
+				up = false
+				break
+			}
+		}
+		if up {
+			enabled = append(enabled, pod.Name)
+		}
+	}
+
+	return enabled, nil
+}
+
+// Drained returns true when none of the pods shows up as ready in any EndpointSlice.
+// pods without the readiness gate can not be pulled out and are ignored.
+func (p *serviceProvider) Drained(d *internaldeploy.Deploy, pods []tritonappsv1alpha1.PodInfo) (bool, error) {
+	svcs, err := p.listServices(d.Namespace)
+	if err != nil {
+		return false, err
+	}
+	endpoints := p.newEndpointCache(d.Namespace)
+
+	for i := range pods {
+		pod, found, err := fetcher.GetPodInCache(d.Namespace, pods[i].Name, p.Client)
+		if err != nil {
+			return false, err
+		}
+		if !found || !internalpod.FromPod(pod).HasReadinessGate() {
+			continue
+		}
+
+		for _, svc := range selectingServices(svcs, pod) {
+			ready, err := endpoints.ready(svc)
+			if err != nil {
+				return false, err
+			}
+			if ready.Has(pod.Status.PodIP) {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+func (p *serviceProvider) listServices(ns string) ([]corev1.Service, error) {
+	svcs := &corev1.ServiceList{}
+	if err := p.List(context.TODO(), svcs, client.InNamespace(ns)); err != nil {
+		return nil, err
+	}
+
+	return svcs.Items, nil
+}
+
+// endpointCache caches ready endpoints of Services during one check.
+type endpointCache struct {
+	client.Client
+	namespace string
+	ips       map[string]sets.String
+}
+
+func (p *serviceProvider) newEndpointCache(ns string) *endpointCache {
+	return &endpointCache{Client: p.Client, namespace: ns, ips: map[string]sets.String{}}
+}
+
+// ready returns IPs of the ready endpoints of a Service.
+func (c *endpointCache) ready(svc string) (sets.String, error) {
+	if ips, ok := c.ips[svc]; ok {
+		return ips, nil
+	}
+
+	slices := &discoveryv1beta1.EndpointSliceList{}
+	if err := c.List(context.TODO(), slices, client.InNamespace(c.namespace), client.MatchingLabels{discoveryv1beta1.LabelServiceName: svc}); err != nil {
+		return nil, err
+	}
+
+	ips := sets.NewString()
+	for _, s := range slices.Items {
+		for _, e := range s.Endpoints {
+			// nil should be interpreted as ready.
+			if e.Conditions.Ready != nil && !*e.Conditions.Ready {
+				continue
+			}
+			ips.Insert(e.Addresses...)
+		}
+	}
+	c.ips[svc] = ips
+
+	return ips, nil
+}
+
+// selectingServices returns names of Services whose selector matches the pod.
+func selectingServices(svcs []corev1.Service, pod *corev1.Pod) []string {
+	names := make([]string, 0, len(svcs))
+	for i := range svcs {
+		if len(svcs[i].Spec.Selector) == 0 {
+			continue
+		}
+		if labels.SelectorFromSet(svcs[i].Spec.Selector).Matches(labels.Set(pod.Labels)) {
+			names = append(names, svcs[i].Name)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook calls the HTTP hooks of a DeployFlow.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+)
+
+const (
+	DefaultTimeout = 10 * time.Second
+
+	// maxMessageLength limits the response body recorded in the error.
+	maxMessageLength = 256
+)
+
+// Payload is the request body sent to a webhook.
+type Payload struct {
+	Stage          tritonappsv1alpha1.WebhookStage `json:"stage"`
+	Namespace      string                          `json:"namespace"`
+	Name           string                          `json:"name"`
+	Action         string                          `json:"action"`
+	AppID          int                             `json:"appID"`
+	GroupID        int                             `json:"groupID"`
+	AppName        string                          `json:"appName"`
+	CloneSetName   string                          `json:"clonesetName"`
+	UpdateRevision string                          `json:"updateRevision,omitempty"`
+	Batch          int                             `json:"batch"`
+	Batches        int                             `json:"batches"`
+	BatchSize      int                             `json:"batchSize"`
+	Canary         bool                            `json:"canary"`
+	Pods           []tritonappsv1alpha1.PodInfo    `json:"pods"`
+}
+
+// Call posts the payload to the hook, it returns an error on a non-2xx response or a timeout.
+func Call(hook *tritonappsv1alpha1.Webhook, payload *Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range hook.Headers {
+		req.Header.Set(k, v)
+	}
+
+	timeout := DefaultTimeout
+	if hook.TimeoutSeconds > 0 {
+		timeout = time.Duration(hook.TimeoutSeconds) * time.Second
+	}
+
+	resp, err := (&http.Client{Timeout: timeout}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxMessageLength))
+		return fmt.Errorf("webhook %s returns status %d: %s", hook.Name, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Triton Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tritonappsv1alpha1 "github.com/triton-io/triton/apis/apps/v1alpha1"
+)
+
+func TestCall(t *testing.T) {
+	var received Payload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/approve":
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewDecoder(r.Body).Decode(&received)
+		case "/veto":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("not allowed"))
+		case "/slow":
+			time.Sleep(2 * time.Second)
+		}
+	}))
+	defer srv.Close()
+
+	payload := &Payload{Stage: tritonappsv1alpha1.PreBatch, Name: "demo", Batch: 2}
+	hook := &tritonappsv1alpha1.Webhook{Name: "approve", URL: srv.URL + "/approve", Headers: map[string]string{"Authorization": "Bearer token"}}
+	if err := Call(hook, payload); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if received.Name != "demo" || received.Batch != 2 || received.Stage != tritonappsv1alpha1.PreBatch {
+		t.Fatalf("unexpected payload %+v", received)
+	}
+
+	hook = &tritonappsv1alpha1.Webhook{Name: "veto", URL: srv.URL + "/veto"}
+	if err := Call(hook, payload); err == nil {
+		t.Fatal("expected an error on a non-2xx response")
+	}
+
+	hook = &tritonappsv1alpha1.Webhook{Name: "slow", URL: srv.URL + "/slow", TimeoutSeconds: 1}
+	if err := Call(hook, payload); err == nil {
+		t.Fatal("expected an error on a timeout")
+	}
+}